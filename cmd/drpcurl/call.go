@@ -0,0 +1,297 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/zeebo/errs"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcconn"
+	"storj.io/drpc/drpcmetadata"
+	"storj.io/drpc/drpcreflect"
+)
+
+// encoding marshals the dynamic protobuf messages.
+type encoding struct{}
+
+func (encoding) Marshal(msg drpc.Message) ([]byte, error) {
+	return proto.Marshal(msg.(proto.Message))
+}
+
+func (encoding) Unmarshal(buf []byte, msg drpc.Message) error {
+	return proto.Unmarshal(buf, msg.(proto.Message))
+}
+
+// files returns the protobuf files from the protoset if there is one, and
+// otherwise from the server's reflection service.
+func files(ctx context.Context, conn drpc.Conn, conf config) (*protoregistry.Files, error) {
+	if conf.protoset == "" {
+		return drpcreflect.NewClient(conn).Files(ctx)
+	}
+
+	buf, err := os.ReadFile(conf.protoset)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(buf, &set); err != nil {
+		return nil, errs.New("invalid protoset %q: %v", conf.protoset, err)
+	}
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, errs.New("invalid protoset %q: %v", conf.protoset, err)
+	}
+	return files, nil
+}
+
+// list prints the rpcs the server exposes, from the protoset if there is one
+// and otherwise from the server's reflection service.
+func list(ctx context.Context, raw net.Conn, conf config, stdout io.Writer) error {
+	conn := drpcconn.New(raw)
+	defer func() { _ = conn.Close() }()
+
+	if conf.protoset != "" {
+		files, err := files(ctx, conn, conf)
+		if err != nil {
+			return err
+		}
+		files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+			services := fd.Services()
+			for i := 0; i < services.Len(); i++ {
+				methods := services.Get(i).Methods()
+				for j := 0; j < methods.Len(); j++ {
+					md := methods.Get(j)
+					fmt.Fprintln(stdout, signature(drpcreflect.RPC{
+						Name:            rpcName(md),
+						ClientStreaming: md.IsStreamingClient(),
+						ServerStreaming: md.IsStreamingServer(),
+						InputType:       string(md.Input().FullName()),
+						OutputType:      string(md.Output().FullName()),
+					}))
+				}
+			}
+			return true
+		})
+		return nil
+	}
+
+	rpcs, err := drpcreflect.NewClient(conn).ListRPCs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, rpc := range rpcs {
+		fmt.Fprintln(stdout, signature(rpc))
+	}
+	return nil
+}
+
+// signature formats the rpc like a protobuf method declaration.
+func signature(rpc drpcreflect.RPC) string {
+	stream := func(streaming bool) string {
+		if streaming {
+			return "stream "
+		}
+		return ""
+	}
+	return fmt.Sprintf("%s(%s%s) returns (%s%s)",
+		rpc.Name,
+		stream(rpc.ClientStreaming), rpc.InputType,
+		stream(rpc.ServerStreaming), rpc.OutputType)
+}
+
+// rpcName returns the drpc rpc name of the method.
+func rpcName(md protoreflect.MethodDescriptor) string {
+	return fmt.Sprintf("/%s/%s", md.Parent().FullName(), md.Name())
+}
+
+// findMethod returns the method named by the rpc, which can be of the form
+// "/package.Service/Method", "package.Service/Method" or
+// "package.Service.Method".
+func findMethod(files *protoregistry.Files, rpc string) (protoreflect.MethodDescriptor, error) {
+	name := strings.ReplaceAll(strings.TrimPrefix(rpc, "/"), "/", ".")
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, errs.New("unknown rpc %q: %v", rpc, err)
+	}
+	md, ok := desc.(protoreflect.MethodDescriptor)
+	if !ok {
+		return nil, errs.New("%q is not an rpc", rpc)
+	}
+	return md, nil
+}
+
+// call calls the rpc, reading requests from stdin and writing responses to
+// stdout.
+func call(ctx context.Context, raw net.Conn, conf config, rpc string, stdin io.Reader, stdout io.Writer) error {
+	conn := drpcconn.New(raw)
+	defer func() { _ = conn.Close() }()
+
+	files, err := files(ctx, conn, conf)
+	if err != nil {
+		return err
+	}
+	md, err := findMethod(files, rpc)
+	if err != nil {
+		return err
+	}
+
+	ctx = drpcmetadata.AppendToOutgoingContext(ctx, conf.metadata.pairs()...)
+	c := &caller{
+		md:  md,
+		dec: json.NewDecoder(stdin),
+		out: stdout,
+	}
+
+	if !md.IsStreamingClient() && !md.IsStreamingServer() {
+		return c.unitary(ctx, conn)
+	}
+	return c.stream(ctx, conn)
+}
+
+// caller calls an rpc with dynamic messages.
+type caller struct {
+	md  protoreflect.MethodDescriptor
+	dec *json.Decoder
+	out io.Writer
+}
+
+// next reads the next request. It returns io.EOF if there are no more.
+func (c *caller) next() (proto.Message, error) {
+	var raw json.RawMessage
+	if err := c.dec.Decode(&raw); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		return nil, errs.New("invalid request json: %v", err)
+	}
+	msg := dynamicpb.NewMessage(c.md.Input())
+	if err := protojson.Unmarshal(raw, msg); err != nil {
+		return nil, errs.New("invalid request: %v", err)
+	}
+	return msg, nil
+}
+
+// single reads the only request, which is empty if there are none.
+func (c *caller) single() (proto.Message, error) {
+	msg, err := c.next()
+	if errors.Is(err, io.EOF) {
+		return dynamicpb.NewMessage(c.md.Input()), nil
+	}
+	return msg, err
+}
+
+// print writes the response as a line of JSON.
+func (c *caller) print(msg proto.Message) error {
+	buf, err := protojson.Marshal(msg)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	_, err = fmt.Fprintf(c.out, "%s\n", buf)
+	return errs.Wrap(err)
+}
+
+// unitary calls a unitary rpc.
+func (c *caller) unitary(ctx context.Context, conn drpc.Conn) error {
+	in, err := c.single()
+	if err != nil {
+		return err
+	}
+	out := dynamicpb.NewMessage(c.md.Output())
+	if err := conn.Invoke(ctx, rpcName(c.md), encoding{}, in, out); err != nil {
+		return err
+	}
+	return c.print(out)
+}
+
+// stream calls a streaming rpc, sending the requests concurrently with
+// printing the responses. If sending fails, the stream is closed.
+func (c *caller) stream(ctx context.Context, conn drpc.Conn) (err error) {
+	stream, err := conn.NewStream(ctx, rpcName(c.md), encoding{})
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, stream.Close()) }()
+
+	sent := make(chan error, 1)
+	go func() {
+		err := c.send(stream)
+		sent <- err
+		if err != nil {
+			_ = stream.Close()
+		}
+	}()
+
+	// the send error is the cause of any receive error after it, and if the
+	// server is done before the requests are, the rest are not needed.
+	sendErr := func() error {
+		select {
+		case err := <-sent:
+			return err
+		default:
+			return nil
+		}
+	}
+
+	for {
+		out := dynamicpb.NewMessage(c.md.Output())
+		if err := stream.MsgRecv(out, encoding{}); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			if serr := sendErr(); serr != nil {
+				return serr
+			}
+			return err
+		}
+		if err := c.print(out); err != nil {
+			return err
+		}
+		if !c.md.IsStreamingServer() {
+			break
+		}
+	}
+
+	return sendErr()
+}
+
+// send sends the requests on the stream and then closes the send side.
+func (c *caller) send(stream drpc.Stream) error {
+	if !c.md.IsStreamingClient() {
+		in, err := c.single()
+		if err != nil {
+			return err
+		}
+		if err := stream.MsgSend(in, encoding{}); err != nil {
+			return err
+		}
+		return stream.CloseSend()
+	}
+
+	for {
+		in, err := c.next()
+		if errors.Is(err, io.EOF) {
+			return stream.CloseSend()
+		} else if err != nil {
+			return err
+		}
+		if err := stream.MsgSend(in, encoding{}); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// drpcurl is a command-line client for drpc servers.
+//
+// It dials a server over plain TCP, TLS, or with the drpcmigrate header, and
+// either lists the rpcs the server exposes or calls one of them:
+//
+//	drpcurl [flags] <address> list
+//	drpcurl [flags] <address> <rpc>
+//
+// The rpc is named like "/package.Service/Method" or "package.Service.Method".
+// Its messages are described by a protobuf descriptor set passed with -protoset
+// (as made by protoc --include_imports --descriptor_set_out), or otherwise by
+// the server's drpcreflect service.
+//
+// Requests are read from stdin as JSON, and responses are printed to stdout as
+// JSON, one message per line. Unitary and server streaming rpcs send a single
+// request, which is empty if stdin is. Client and bidi streaming rpcs send
+// every newline-delimited request read from stdin, and then close the send
+// side of the stream.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcmigrate"
+)
+
+// config is the configuration from the command line flags.
+type config struct {
+	protoset   string
+	metadata   metadataFlag
+	timeout    time.Duration
+	migrate    bool
+	tls        bool
+	insecure   bool
+	cacert     string
+	cert       string
+	key        string
+	serverName string
+}
+
+// metadataFlag collects repeated key=value metadata flags.
+type metadataFlag []string
+
+func (m *metadataFlag) String() string { return strings.Join(*m, ",") }
+
+func (m *metadataFlag) Set(v string) error {
+	if !strings.Contains(v, "=") {
+		return errs.New("metadata must be of the form key=value: %q", v)
+	}
+	*m = append(*m, v)
+	return nil
+}
+
+// pairs returns the metadata as alternating keys and values.
+func (m metadataFlag) pairs() []string {
+	kv := make([]string, 0, 2*len(m))
+	for _, entry := range m {
+		i := strings.IndexByte(entry, '=')
+		kv = append(kv, entry[:i], entry[i+1:])
+	}
+	return kv
+}
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	if err := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			if code := drpcerr.Code(err); code != 0 {
+				fmt.Fprintf(os.Stderr, "drpcurl: %v (code %d)\n", err, code)
+			} else {
+				fmt.Fprintf(os.Stderr, "drpcurl: %v\n", err)
+			}
+		}
+		os.Exit(1)
+	}
+}
+
+// run runs the command with the arguments.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var conf config
+	flags := flag.NewFlagSet("drpcurl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: drpcurl [flags] <address> list")
+		fmt.Fprintln(stderr, "       drpcurl [flags] <address> <rpc>")
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&conf.protoset, "protoset", "", "file containing a protobuf descriptor set; if empty, server reflection is used")
+	flags.Var(&conf.metadata, "H", "metadata to send as key=value; can be repeated")
+	flags.DurationVar(&conf.timeout, "timeout", 0, "timeout for the whole call; if zero, there is none")
+	flags.BoolVar(&conf.migrate, "migrate", false, "send the drpcmigrate header when connecting")
+	flags.BoolVar(&conf.tls, "tls", false, "connect with TLS")
+	flags.BoolVar(&conf.insecure, "insecure", false, "skip verification of the server certificate")
+	flags.StringVar(&conf.cacert, "cacert", "", "file containing the CA certificates to verify the server with")
+	flags.StringVar(&conf.cert, "cert", "", "file containing the client certificate")
+	flags.StringVar(&conf.key, "key", "", "file containing the client certificate key")
+	flags.StringVar(&conf.serverName, "servername", "", "server name to verify the server certificate with")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+	address, rpc := flags.Arg(0), flags.Arg(1)
+
+	if conf.timeout > 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, conf.timeout)
+		defer cancel()
+	}
+
+	conn, err := dial(ctx, address, conf)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if rpc == "list" {
+		return list(ctx, conn, conf, stdout)
+	}
+	return call(ctx, conn, conf, rpc, stdin, stdout)
+}
+
+// dial connects to the address as configured.
+func dial(ctx context.Context, address string, conf config) (net.Conn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if conf.migrate {
+		conn = drpcmigrate.NewHeaderConn(conn, drpcmigrate.DRPCHeader)
+	}
+	if !conf.tls {
+		return conn, nil
+	}
+
+	tlsConfig, err := tlsConfig(address, conf)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	tconn := tls.Client(conn, tlsConfig)
+	if err := tconn.HandshakeContext(ctx); err != nil {
+		_ = conn.Close()
+		return nil, errs.Wrap(err)
+	}
+	return tconn, nil
+}
+
+// tlsConfig returns the TLS configuration to connect to the address with.
+func tlsConfig(address string, conf config) (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         conf.serverName,
+		InsecureSkipVerify: conf.insecure, //nolint: gosec // explicitly requested by the flag
+	}
+	if config.ServerName == "" {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		config.ServerName = host
+	}
+
+	if conf.cacert != "" {
+		pem, err := os.ReadFile(conf.cacert)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, errs.New("no certificates found in %q", conf.cacert)
+		}
+	}
+
+	if conf.cert != "" || conf.key != "" {
+		cert, err := tls.LoadX509KeyPair(conf.cert, conf.key)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,229 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zeebo/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcmetadata"
+	"storj.io/drpc/drpcmigrate"
+	"storj.io/drpc/drpcmux"
+	"storj.io/drpc/drpcreflect"
+	"storj.io/drpc/drpcserver"
+	"storj.io/drpc/drpctest"
+)
+
+// echoFile declares the echo service used by the tests.
+var echoFile = func() *descriptorpb.FileDescriptorProto {
+	method := func(name string, client, server bool) *descriptorpb.MethodDescriptorProto {
+		return &descriptorpb.MethodDescriptorProto{
+			Name:            proto.String(name),
+			InputType:       proto.String(".google.protobuf.StringValue"),
+			OutputType:      proto.String(".google.protobuf.StringValue"),
+			ClientStreaming: proto.Bool(client),
+			ServerStreaming: proto.Bool(server),
+		}
+	}
+	return &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("echo.proto"),
+		Package:    proto.String("echo"),
+		Dependency: []string{"google/protobuf/wrappers.proto"},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Echo"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				method("Unitary", false, false),
+				method("Repeat", false, true),
+				method("Concat", true, false),
+				method("Bidi", true, true),
+			},
+		}},
+		Syntax: proto.String("proto3"),
+	}
+}()
+
+type echoServer interface {
+	Unitary(context.Context, *wrapperspb.StringValue) (*wrapperspb.StringValue, error)
+	Repeat(*wrapperspb.StringValue, drpc.Stream) error
+	Concat(drpc.Stream) error
+	Bidi(drpc.Stream) error
+}
+
+type echoImpl struct{}
+
+func (echoImpl) Unitary(ctx context.Context, in *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+	value := drpcmetadata.ValueFromIncomingContext(ctx, "suffix")
+	return wrapperspb.String(in.Value + strings.Join(value, "")), nil
+}
+
+func (echoImpl) Repeat(in *wrapperspb.StringValue, stream drpc.Stream) error {
+	for i := 0; i < 2; i++ {
+		if err := stream.MsgSend(in, encoding{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (echoImpl) Concat(stream drpc.Stream) error {
+	var out wrapperspb.StringValue
+	for {
+		var in wrapperspb.StringValue
+		if err := stream.MsgRecv(&in, encoding{}); err == io.EOF {
+			return stream.MsgSend(&out, encoding{})
+		} else if err != nil {
+			return err
+		}
+		out.Value += in.Value
+	}
+}
+
+func (echoImpl) Bidi(stream drpc.Stream) error {
+	for {
+		var in wrapperspb.StringValue
+		if err := stream.MsgRecv(&in, encoding{}); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := stream.MsgSend(&in, encoding{}); err != nil {
+			return err
+		}
+	}
+}
+
+type echoDescription struct{}
+
+func (echoDescription) NumMethods() int { return 4 }
+
+func (echoDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver, interface{}, bool) {
+	switch n {
+	case 0:
+		return "/echo.Echo/Unitary", encoding{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(echoServer).Unitary(ctx, in1.(*wrapperspb.StringValue))
+			}, echoServer.Unitary, true
+	case 1:
+		return "/echo.Echo/Repeat", encoding{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return nil, srv.(echoServer).Repeat(in1.(*wrapperspb.StringValue), in2.(drpc.Stream))
+			}, echoServer.Repeat, true
+	case 2:
+		return "/echo.Echo/Concat", encoding{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return nil, srv.(echoServer).Concat(in1.(drpc.Stream))
+			}, echoServer.Concat, true
+	case 3:
+		return "/echo.Echo/Bidi", encoding{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return nil, srv.(echoServer).Bidi(in1.(drpc.Stream))
+			}, echoServer.Bidi, true
+	default:
+		return "", nil, nil, nil, false
+	}
+}
+
+func (echoDescription) FileDescriptor() []byte {
+	buf, _ := proto.Marshal(echoFile)
+	return buf
+}
+
+// serve starts a server for the echo service and returns its address.
+func serve(t *testing.T, ctx *drpctest.Tracker, migrate bool) string {
+	mux := drpcmux.New()
+	assert.NoError(t, drpcreflect.Register(mux))
+	assert.NoError(t, mux.Register(echoImpl{}, echoDescription{}))
+	srv := drpcserver.New(mux)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	if migrate {
+		lmux := drpcmigrate.NewListenMux(lis, len(drpcmigrate.DRPCHeader))
+		drpcLis := lmux.Route(drpcmigrate.DRPCHeader)
+		ctx.Run(func(ctx context.Context) { _ = lmux.Run(ctx) })
+		ctx.Run(func(ctx context.Context) { _ = srv.Serve(ctx, drpcLis) })
+	} else {
+		ctx.Run(func(ctx context.Context) { _ = srv.Serve(ctx, lis) })
+	}
+
+	return lis.Addr().String()
+}
+
+func TestDrpcurl(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	addr := serve(t, ctx, false)
+
+	drpcurl := func(stdin string, args ...string) string {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		err := run(ctx, args, strings.NewReader(stdin), &stdout, &stderr)
+		assert.NoError(t, err)
+		assert.Equal(t, stderr.String(), "")
+		return stdout.String()
+	}
+
+	assert.Equal(t, drpcurl("", addr, "list"), ""+
+		"/drpc.reflection.v1.Reflection/FileDescriptors(*drpcreflect.FileDescriptorsRequest) returns (*drpcreflect.FileDescriptorsResponse)\n"+
+		"/drpc.reflection.v1.Reflection/ListRPCs(*drpcreflect.ListRPCsRequest) returns (*drpcreflect.ListRPCsResponse)\n"+
+		"/echo.Echo/Bidi(stream google.protobuf.StringValue) returns (stream google.protobuf.StringValue)\n"+
+		"/echo.Echo/Concat(stream google.protobuf.StringValue) returns (google.protobuf.StringValue)\n"+
+		"/echo.Echo/Repeat(google.protobuf.StringValue) returns (stream google.protobuf.StringValue)\n"+
+		"/echo.Echo/Unitary(google.protobuf.StringValue) returns (google.protobuf.StringValue)\n")
+
+	assert.Equal(t, drpcurl(`"hi"`, addr, "/echo.Echo/Unitary"), "\"hi\"\n")
+	assert.Equal(t, drpcurl(`"hi"`, "-H", "suffix=!", "-H", "suffix=?", addr, "echo.Echo.Unitary"), "\"hi!?\"\n")
+	assert.Equal(t, drpcurl("", addr, "echo.Echo/Unitary"), "\"\"\n")
+	assert.Equal(t, drpcurl(`"hi"`, addr, "echo.Echo/Repeat"), "\"hi\"\n\"hi\"\n")
+	assert.Equal(t, drpcurl("\"a\"\n\"b\"\n", addr, "echo.Echo/Concat"), "\"ab\"\n")
+	assert.Equal(t, drpcurl("\"a\"\n\"b\"\n", addr, "echo.Echo/Bidi"), "\"a\"\n\"b\"\n")
+
+	// errors from the server and from bad requests are returned.
+	var stdout, stderr bytes.Buffer
+	err := run(ctx, []string{addr, "echo.Echo/Missing"}, strings.NewReader(""), &stdout, &stderr)
+	assert.Error(t, err)
+	err = run(ctx, []string{addr, "echo.Echo/Bidi"}, strings.NewReader(`"a" {`), &stdout, &stderr)
+	assert.Error(t, err)
+}
+
+func TestDrpcurl_ProtosetAndMigrate(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	addr := serve(t, ctx, true)
+
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(wrapperspb.File_google_protobuf_wrappers_proto),
+		echoFile,
+	}}
+	buf, err := proto.Marshal(set)
+	assert.NoError(t, err)
+	protoset := filepath.Join(t.TempDir(), "echo.protoset")
+	assert.NoError(t, os.WriteFile(protoset, buf, 0o644))
+
+	var stdout, stderr bytes.Buffer
+	err = run(ctx, []string{"-migrate", "-protoset", protoset, addr, "list"}, strings.NewReader(""), &stdout, &stderr)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Count(stdout.String(), "\n"), 4)
+
+	stdout.Reset()
+	err = run(ctx, []string{"-migrate", "-protoset", protoset, addr, "/echo.Echo/Unitary"}, strings.NewReader(`"hi"`), &stdout, &stderr)
+	assert.NoError(t, err)
+	assert.Equal(t, stdout.String(), "\"hi\"\n")
+}
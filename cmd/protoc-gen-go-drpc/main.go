@@ -12,19 +12,27 @@ import (
 	"strings"
 
 	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
 	"google.golang.org/protobuf/types/pluginpb"
 )
 
 type config struct {
-	protolib string
-	json     bool
+	protolib    string
+	errors      string
+	json        bool
+	grpc        bool
+	descriptors bool
 }
 
 func main() {
 	var flags flag.FlagSet
 	var conf config
 	flags.StringVar(&conf.protolib, "protolib", "google.golang.org/protobuf", "which protobuf library to use for encoding")
+	flags.StringVar(&conf.errors, "errors", "github.com/cockroachdb/errors", "which errors library to use in unimplemented servers")
 	flags.BoolVar(&conf.json, "json", true, "generate encoders with json support")
+	flags.BoolVar(&conf.grpc, "grpc", true, "generate adapters for the clients generated by protoc-gen-go-grpc")
+	flags.BoolVar(&conf.descriptors, "descriptors", false, "embed file descriptors in service descriptions for reflection")
 
 	protogen.Options{
 		ParamFunc: flags.Set,
@@ -42,7 +50,7 @@ func main() {
 
 func generateFile(plugin *protogen.Plugin, file *protogen.File, conf config) {
 	gf := plugin.NewGeneratedFile(file.GeneratedFilenamePrefix+"_drpc.pb.go", file.GoImportPath)
-	d := &drpc{gf, file}
+	d := &drpc{gf, file, conf}
 
 	d.P("// Code generated by protoc-gen-go-drpc. DO NOT EDIT.")
 	if bi, ok := debug.ReadBuildInfo(); ok {
@@ -54,6 +62,9 @@ func generateFile(plugin *protogen.Plugin, file *protogen.File, conf config) {
 	d.P()
 
 	d.generateEncoding(conf)
+	if conf.descriptors {
+		d.generateFileDescriptor()
+	}
 	for _, service := range file.Services {
 		d.generateService(service)
 	}
@@ -62,6 +73,7 @@ func generateFile(plugin *protogen.Plugin, file *protogen.File, conf config) {
 type drpc struct {
 	*protogen.GeneratedFile
 	file *protogen.File
+	conf config
 }
 
 //
@@ -76,6 +88,10 @@ func (d *drpc) EncodingName() string {
 	return "drpcEncoding_" + d.file.GoDescriptorIdent.GoName
 }
 
+func (d *drpc) FileDescriptorName() string {
+	return "drpcFileDescriptor_" + d.file.GoDescriptorIdent.GoName
+}
+
 func (d *drpc) RPCGoString(method *protogen.Method) string {
 	return strconv.Quote(fmt.Sprintf("/%s/%s", method.Parent.Desc.FullName(), method.Desc.Name()))
 }
@@ -263,6 +279,35 @@ func (d *drpc) generateEncoding(conf config) {
 	}
 }
 
+//
+// file descriptor generation
+//
+
+func (d *drpc) generateFileDescriptor() {
+	fdp := proto.Clone(d.file.Proto).(*descriptorpb.FileDescriptorProto)
+	fdp.SourceCodeInfo = nil
+	buf, err := proto.MarshalOptions{Deterministic: true}.Marshal(fdp)
+	if err != nil {
+		panic(err)
+	}
+
+	d.P("var ", d.FileDescriptorName(), " = []byte{")
+	for len(buf) > 0 {
+		n := len(buf)
+		if n > 16 {
+			n = 16
+		}
+		var line strings.Builder
+		for _, b := range buf[:n] {
+			fmt.Fprintf(&line, "0x%02x, ", b)
+		}
+		d.P(strings.TrimSuffix(line.String(), " "))
+		buf = buf[n:]
+	}
+	d.P("}")
+	d.P()
+}
+
 //
 // service generation
 //
@@ -331,6 +376,10 @@ func (d *drpc) generateService(service *protogen.Service) {
 	d.P("}")
 	d.P("}")
 	d.P()
+	if d.conf.descriptors {
+		d.P("func (", d.ServerDesc(service), ") FileDescriptor() []byte { return ", d.FileDescriptorName(), " }")
+		d.P()
+	}
 
 	// Registration helper
 	d.P("func DRPCRegister", service.GoName, "(mux ", d.Ident("storj.io/drpc", "Mux"), ", impl ", d.ServerIface(service), ") error {")
@@ -479,9 +528,9 @@ func (d *drpc) generateServerSignature(method *protogen.Method) string {
 func (d *drpc) generateUnimplementedServerMethod(method *protogen.Method) {
 	d.P("func (s *", d.ServerUnimpl(method.Parent), ") ", d.generateServerSignature(method), " {")
 	if !method.Desc.IsStreamingServer() && !method.Desc.IsStreamingClient() {
-		d.P("return nil, ", d.Ident("storj.io/drpc/drpcerr", "WithCode"), "(", d.Ident("github.com/cockroachdb/errors", "New"), "(\"Unimplemented\"), ", d.Ident("storj.io/drpc/drpcerr", "Unimplemented"), ")")
+		d.P("return nil, ", d.Ident("storj.io/drpc/drpcerr", "WithCode"), "(", d.Ident(d.conf.errors, "New"), "(\"Unimplemented\"), ", d.Ident("storj.io/drpc/drpcerr", "Unimplemented"), ")")
 	} else {
-		d.P("return ", d.Ident("storj.io/drpc/drpcerr", "WithCode"), "(", d.Ident("github.com/cockroachdb/errors", "New"), "(\"Unimplemented\"), ", d.Ident("storj.io/drpc/drpcerr", "Unimplemented"), ")")
+		d.P("return ", d.Ident("storj.io/drpc/drpcerr", "WithCode"), "(", d.Ident(d.conf.errors, "New"), "(\"Unimplemented\"), ", d.Ident("storj.io/drpc/drpcerr", "Unimplemented"), ")")
 	}
 	d.P("}")
 	d.P()
@@ -640,7 +689,9 @@ func (d *drpc) generateRPCClientSignature(method *protogen.Method) string {
 	return fmt.Sprintf("%s(ctx %s%s) (%s, error)", method.GoName, d.Ident("context", "Context"), reqArg, respName)
 }
 func (d *drpc) generateServiceAdapters(service *protogen.Service) {
-	d.generateGRPCAdapter(service)
+	if d.conf.grpc {
+		d.generateGRPCAdapter(service)
+	}
 	d.generateDRPCAdapter(service)
 }
 
@@ -0,0 +1,11 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package drpcauth has implementations of per-rpc credentials and the
+// authenticators that check them.
+//
+// TokenCredentials send bearer tokens, caching them until shortly before they
+// expire. HMACCredentials sign every rpc with a shared secret, and an
+// HMACAuthenticator checks those signatures. The credentials can be passed to
+// drpcclient.WithPerRPCCredentials, and the authenticators to drpcmux.NewAuth.
+package drpcauth
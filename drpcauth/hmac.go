@@ -0,0 +1,118 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcauth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"storj.io/drpc/drpcctx"
+	"storj.io/drpc/drpcerr"
+)
+
+// The metadata keys sent by HMACCredentials.
+const (
+	// HMACKeyIDKey is the metadata key for the id of the signing key.
+	HMACKeyIDKey = "drpc-hmac-key"
+
+	// HMACTimestampKey is the metadata key for when the rpc was signed, in
+	// seconds since the unix epoch.
+	HMACTimestampKey = "drpc-hmac-timestamp"
+
+	// HMACSignatureKey is the metadata key for the hex encoded signature.
+	HMACSignatureKey = "drpc-hmac-signature"
+)
+
+// sign returns the hex encoded HMAC-SHA256 of the key id, rpc and timestamp.
+func sign(secret []byte, keyID, rpc, timestamp string) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write([]byte(keyID + "\n" + rpc + "\n" + timestamp))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// HMACCredentials are per-rpc credentials that sign the name of every rpc and
+// the time it was made with a secret shared with the server.
+type HMACCredentials struct {
+	keyID  string
+	secret []byte
+}
+
+// NewHMACCredentials returns HMACCredentials that sign with the secret, which
+// the server knows by the key id.
+func NewHMACCredentials(keyID string, secret []byte) *HMACCredentials {
+	return &HMACCredentials{keyID: keyID, secret: secret}
+}
+
+// GetRequestMetadata returns the metadata with the signature for an rpc.
+func (c *HMACCredentials) GetRequestMetadata(ctx context.Context, rpc string) (map[string]string, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	return map[string]string{
+		HMACKeyIDKey:     c.keyID,
+		HMACTimestampKey: timestamp,
+		HMACSignatureKey: sign(c.secret, c.keyID, rpc, timestamp),
+	}, nil
+}
+
+// HMACOptions controls configuration settings for an HMACAuthenticator.
+type HMACOptions struct {
+	// MaxSkew is how far the timestamp of a signature can be from the time
+	// it is checked. If zero, 5 minutes is used.
+	MaxSkew time.Duration
+}
+
+// HMACAuthenticator is a drpcmux.Authenticator that checks the signatures sent
+// by HMACCredentials. Rpcs are authenticated as a principal named by the key
+// id. Signatures can be replayed for the same rpc until they are too old, so
+// it should be used over a transport that is already confidential, like TLS.
+type HMACAuthenticator struct {
+	keys map[string][]byte
+	opts HMACOptions
+}
+
+// NewHMACAuthenticator returns an HMACAuthenticator that accepts signatures
+// made with the secrets, keyed by their key id.
+func NewHMACAuthenticator(keys map[string][]byte) *HMACAuthenticator {
+	return NewHMACAuthenticatorWithOptions(keys, HMACOptions{})
+}
+
+// NewHMACAuthenticatorWithOptions returns an HMACAuthenticator that accepts
+// signatures made with the secrets, keyed by their key id, with the options.
+func NewHMACAuthenticatorWithOptions(keys map[string][]byte, opts HMACOptions) *HMACAuthenticator {
+	if opts.MaxSkew <= 0 {
+		opts.MaxSkew = 5 * time.Minute
+	}
+	return &HMACAuthenticator{keys: keys, opts: opts}
+}
+
+// Authenticate checks the signature in the metadata for the rpc.
+func (a *HMACAuthenticator) Authenticate(ctx context.Context, rpc string, md map[string]string) (drpcctx.Principal, error) {
+	keyID, timestamp, signature := md[HMACKeyIDKey], md[HMACTimestampKey], md[HMACSignatureKey]
+	if keyID == "" || timestamp == "" || signature == "" {
+		return drpcctx.Principal{}, drpcerr.Newf(drpcerr.Unauthenticated, "missing hmac signature for %s", rpc)
+	}
+
+	secret, ok := a.keys[keyID]
+	if !ok {
+		return drpcctx.Principal{}, drpcerr.Newf(drpcerr.Unauthenticated, "unknown hmac key %q", keyID)
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return drpcctx.Principal{}, drpcerr.Newf(drpcerr.Unauthenticated, "invalid hmac timestamp %q", timestamp)
+	}
+	if skew := time.Since(time.Unix(unix, 0)); skew > a.opts.MaxSkew || skew < -a.opts.MaxSkew {
+		return drpcctx.Principal{}, drpcerr.Newf(drpcerr.Unauthenticated, "hmac timestamp outside of allowed skew")
+	}
+
+	expected := sign(secret, keyID, rpc, timestamp)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return drpcctx.Principal{}, drpcerr.Newf(drpcerr.Unauthenticated, "invalid hmac signature for %s", rpc)
+	}
+
+	return drpcctx.Principal{Name: keyID}, nil
+}
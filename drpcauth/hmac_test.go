@@ -0,0 +1,61 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcauth
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/zeebo/assert"
+
+	"storj.io/drpc/drpcerr"
+)
+
+func TestHMAC(t *testing.T) {
+	ctx := context.Background()
+	secret := []byte("secret")
+	authn := NewHMACAuthenticatorWithOptions(map[string][]byte{"key": secret}, HMACOptions{
+		MaxSkew: time.Minute,
+	})
+
+	md, err := NewHMACCredentials("key", secret).GetRequestMetadata(ctx, "rpc")
+	assert.NoError(t, err)
+
+	// a valid signature authenticates as the key id.
+	principal, err := authn.Authenticate(ctx, "rpc", md)
+	assert.NoError(t, err)
+	assert.Equal(t, principal.Name, "key")
+
+	unauthenticated := func(rpc string, md map[string]string) {
+		t.Helper()
+		_, err := authn.Authenticate(ctx, rpc, md)
+		assert.Equal(t, drpcerr.Code(err), uint64(drpcerr.Unauthenticated))
+	}
+
+	// signatures are only valid for the rpc they were made for.
+	unauthenticated("other", md)
+
+	// or with the right secret.
+	md2, err := NewHMACCredentials("key", []byte("wrong")).GetRequestMetadata(ctx, "rpc")
+	assert.NoError(t, err)
+	unauthenticated("rpc", md2)
+
+	// or with a known key.
+	md2, err = NewHMACCredentials("other", secret).GetRequestMetadata(ctx, "rpc")
+	assert.NoError(t, err)
+	unauthenticated("rpc", md2)
+
+	// or within the skew.
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	unauthenticated("rpc", map[string]string{
+		HMACKeyIDKey:     "key",
+		HMACTimestampKey: old,
+		HMACSignatureKey: sign(secret, "key", "rpc", old),
+	})
+
+	// and missing signatures are rejected.
+	unauthenticated("rpc", nil)
+}
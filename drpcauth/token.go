@@ -0,0 +1,178 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcauth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+)
+
+// Error is the class of errors returned by this package.
+var Error = errs.Class("drpcauth")
+
+// Token is a bearer token and when it expires.
+type Token struct {
+	// Value is the token sent with rpcs.
+	Value string
+
+	// Expiry is when the token expires. If zero, it never expires.
+	Expiry time.Time
+}
+
+// TokenSource returns a new token.
+type TokenSource func(ctx context.Context) (Token, error)
+
+// TokenOptions controls configuration settings for TokenCredentials.
+type TokenOptions struct {
+	// RefreshMargin is how long before a token expires that a new one is
+	// fetched in the background, so that tokens do not expire while an rpc
+	// is in flight. If zero, 30 seconds is used.
+	RefreshMargin time.Duration
+}
+
+// TokenCredentials are per-rpc credentials that send a token from a
+// TokenSource in the "authorization" metadata as "Bearer <token>". The token
+// is cached and reused until it expires, and refreshed in the background once
+// it is about to.
+type TokenCredentials struct {
+	source TokenSource
+	opts   TokenOptions
+
+	mu    sync.Mutex
+	token Token
+	valid bool
+	fetch *tokenFetch // the fetch in progress, if any
+}
+
+// tokenFetch is a fetch of a new token that is shared by concurrent calls to
+// Token. The token and error are only valid once done is closed.
+type tokenFetch struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int // calls waiting for the fetch, protected by the mutex
+	token   Token
+	err     error
+}
+
+// NewTokenCredentials returns TokenCredentials that get tokens from the source.
+func NewTokenCredentials(source TokenSource) *TokenCredentials {
+	return NewTokenCredentialsWithOptions(source, TokenOptions{})
+}
+
+// NewTokenCredentialsWithOptions returns TokenCredentials that get tokens from
+// the source with the options.
+func NewTokenCredentialsWithOptions(source TokenSource, opts TokenOptions) *TokenCredentials {
+	if opts.RefreshMargin <= 0 {
+		opts.RefreshMargin = 30 * time.Second
+	}
+	return &TokenCredentials{source: source, opts: opts}
+}
+
+// Token returns the cached token, fetching a new one from the source if there
+// is no cached token or it has expired. If the cached token is about to
+// expire, it is returned while a new one is fetched in the background.
+// Concurrent calls share a single fetch, and each call stops waiting for it
+// when its own context is done. The fetch is only canceled once every call
+// waiting for it has stopped.
+func (c *TokenCredentials) Token(ctx context.Context) (Token, error) {
+	c.mu.Lock()
+	if c.valid {
+		remaining := time.Until(c.token.Expiry)
+		if c.token.Expiry.IsZero() || remaining > 0 {
+			// the token is still valid, so callers do not wait for a
+			// new one while it is about to expire.
+			if !c.token.Expiry.IsZero() && remaining <= c.opts.RefreshMargin && c.fetch == nil {
+				c.startLocked(ctx)
+			}
+			token := c.token
+			c.mu.Unlock()
+			return token, nil
+		}
+	}
+
+	f := c.fetch
+	if f == nil {
+		f = c.startLocked(ctx)
+	}
+	f.waiters++
+	c.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.token, f.err
+
+	case <-ctx.Done():
+		c.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 && c.fetch == f {
+			c.fetch = nil
+			f.cancel()
+		}
+		c.mu.Unlock()
+		return Token{}, Error.Wrap(ctx.Err())
+	}
+}
+
+// startLocked starts a fetch of a new token with the values of the context.
+// It must be called with the mutex held.
+func (c *TokenCredentials) startLocked(ctx context.Context) *tokenFetch {
+	fctx, cancel := context.WithCancel(detachedContext{ctx})
+	f := &tokenFetch{done: make(chan struct{}), cancel: cancel}
+	c.fetch = f
+	go c.run(fctx, f)
+	return f
+}
+
+// run fetches a token from the source for the fetch, caching it if the fetch
+// is still wanted.
+func (c *TokenCredentials) run(ctx context.Context, f *tokenFetch) {
+	token, err := c.source(ctx)
+	f.cancel()
+
+	c.mu.Lock()
+	if c.fetch == f {
+		c.fetch = nil
+		if err == nil {
+			c.token, c.valid = token, true
+		}
+	}
+	c.mu.Unlock()
+
+	if err != nil {
+		f.err = Error.Wrap(err)
+	} else {
+		f.token = token
+	}
+	close(f.done)
+}
+
+// Invalidate discards the cached token so that the next rpc fetches a new one,
+// for example after the server rejects it.
+func (c *TokenCredentials) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.token, c.valid = Token{}, false
+}
+
+// detachedContext has the values of its parent context, but is never canceled
+// and has no deadline, so that a fetch shared by many calls does not depend
+// on the first one.
+type detachedContext struct{ context.Context }
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// GetRequestMetadata returns the metadata with the token for an rpc.
+func (c *TokenCredentials) GetRequestMetadata(ctx context.Context, rpc string) (map[string]string, error) {
+	token, err := c.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"authorization": "Bearer " + token.Value}, nil
+}
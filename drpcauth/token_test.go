@@ -0,0 +1,153 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcauth
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zeebo/assert"
+)
+
+func TestTokenCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	var fetches int
+	var expiry time.Time
+	var fetchErr error
+	creds := NewTokenCredentialsWithOptions(func(ctx context.Context) (Token, error) {
+		if fetchErr != nil {
+			return Token{}, fetchErr
+		}
+		fetches++
+		return Token{Value: strconv.Itoa(fetches), Expiry: expiry}, nil
+	}, TokenOptions{RefreshMargin: time.Minute})
+
+	// tokens are cached while they are valid.
+	expiry = time.Now().Add(time.Hour)
+	md, err := creds.GetRequestMetadata(ctx, "rpc")
+	assert.NoError(t, err)
+	assert.Equal(t, md, map[string]string{"authorization": "Bearer 1"})
+
+	md, err = creds.GetRequestMetadata(ctx, "rpc")
+	assert.NoError(t, err)
+	assert.Equal(t, md["authorization"], "Bearer 1")
+	assert.Equal(t, fetches, 1)
+
+	// and refreshed in the background when they are about to expire, while
+	// they are still used.
+	creds.mu.Lock()
+	creds.token.Expiry = time.Now().Add(30 * time.Second)
+	creds.mu.Unlock()
+
+	md, err = creds.GetRequestMetadata(ctx, "rpc")
+	assert.NoError(t, err)
+	assert.Equal(t, md["authorization"], "Bearer 1")
+
+	token, err := creds.Token(ctx)
+	for ; err == nil && token.Value == "1"; token, err = creds.Token(ctx) {
+		time.Sleep(time.Millisecond)
+	}
+	assert.NoError(t, err)
+	assert.Equal(t, token.Value, "2")
+
+	// but fetched before they are used once they expire.
+	creds.mu.Lock()
+	creds.token.Expiry = time.Now().Add(-time.Second)
+	creds.mu.Unlock()
+
+	token, err = creds.Token(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, token.Value, "3")
+
+	// or when they are invalidated.
+	creds.Invalidate()
+	token, err = creds.Token(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, token.Value, "4")
+
+	// tokens without an expiry never expire.
+	expiry = time.Time{}
+	creds.Invalidate()
+	for i := 0; i < 3; i++ {
+		token, err = creds.Token(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, token.Value, "5")
+	}
+
+	// errors from the source are returned.
+	fetchErr = errors.New("unavailable")
+	creds.Invalidate()
+	_, err = creds.GetRequestMetadata(ctx, "rpc")
+	assert.Error(t, err)
+	assert.That(t, Error.Has(err))
+}
+
+func TestTokenCredentials_SharedFetch(t *testing.T) {
+	ctx := context.Background()
+
+	var fetches int32
+	release := make(chan struct{})
+	canceled := make(chan struct{})
+	creds := NewTokenCredentials(func(ctx context.Context) (Token, error) {
+		n := atomic.AddInt32(&fetches, 1)
+		select {
+		case <-release:
+			return Token{Value: strconv.Itoa(int(n))}, nil
+		case <-ctx.Done():
+			close(canceled)
+			return Token{}, ctx.Err()
+		}
+	})
+
+	// concurrent calls share a single fetch.
+	var wg sync.WaitGroup
+	results := make(chan string, 3)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := creds.Token(ctx)
+			assert.NoError(t, err)
+			results <- token.Value
+		}()
+	}
+
+	for waiting := 0; waiting < 3; {
+		time.Sleep(time.Millisecond)
+		creds.mu.Lock()
+		if creds.fetch != nil {
+			waiting = creds.fetch.waiters
+		}
+		creds.mu.Unlock()
+	}
+
+	// a call whose context is done does not wait for the fetch.
+	tctx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	_, err := creds.Token(tctx)
+	assert.That(t, errors.Is(err, context.DeadlineExceeded))
+
+	close(release)
+	wg.Wait()
+	close(results)
+	for value := range results {
+		assert.Equal(t, value, "1")
+	}
+	assert.Equal(t, atomic.LoadInt32(&fetches), int32(1))
+
+	// a fetch is canceled once every call waiting for it has stopped.
+	creds.Invalidate()
+	release = make(chan struct{})
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = creds.Token(cctx)
+	assert.That(t, errors.Is(err, context.Canceled))
+	<-canceled
+}
@@ -0,0 +1,336 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcbalancer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcdebug"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcpool"
+	"storj.io/drpc/drpcsignal"
+)
+
+// Options controls configuration settings for a balancing conn.
+type Options struct {
+	// Policy is how a backend is picked for every rpc.
+	Policy Policy
+
+	// HashKey is the metadata key whose value is hashed to pick a backend
+	// with the ConsistentHash policy.
+	HashKey string
+
+	// Pool controls the options for the pool caching the connections to the
+	// backends.
+	Pool drpcpool.Options
+
+	// FailureThreshold is the number of consecutive rpcs that must fail with
+	// an Unavailable error, like when the backend can not be dialed, for the
+	// backend to be ejected. If zero, 5 is used. If negative, backends are
+	// never ejected.
+	FailureThreshold int
+
+	// EjectionTime is how long an ejected backend is not picked for rpcs
+	// unless every backend is ejected. If zero, 10 seconds is used.
+	EjectionTime time.Duration
+
+	// HealthCheck, if set, is called with a connection to every backend once
+	// every HealthCheckInterval. Backends whose last check failed are not
+	// picked for rpcs unless every backend is ejected or unhealthy. The
+	// drpchealth.Checker function returns a HealthCheck that uses the
+	// grpc.health.v1 service.
+	HealthCheck func(ctx context.Context, conn drpc.Conn) error
+
+	// HealthCheckInterval is how often the backends are checked, and how long
+	// a check can take. If zero, 10 seconds is used.
+	HealthCheckInterval time.Duration
+}
+
+// Conn is a drpc.Conn that spreads the rpcs across a set of backends.
+type Conn struct {
+	opts Options
+	dial func(ctx context.Context, addr string) (drpcpool.Conn, error)
+	pool *drpcpool.Pool[string, drpcpool.Conn]
+	done drpcsignal.Signal
+	next atomic.Uint64
+
+	mu       sync.Mutex
+	backends []*backend // sorted by address and replaced on every update
+	ring     hashRing
+}
+
+var _ drpc.Conn = (*Conn)(nil)
+
+// New returns a conn that balances rpcs across connections to the addresses
+// created with the dial function.
+func New(addrs []string, dial func(ctx context.Context, addr string) (drpcpool.Conn, error)) *Conn {
+	return NewWithOptions(addrs, dial, Options{})
+}
+
+// NewWithOptions returns a conn that balances rpcs across connections to the
+// addresses created with the dial function. The Options control details of how
+// the rpcs are balanced.
+func NewWithOptions(addrs []string, dial func(ctx context.Context, addr string) (drpcpool.Conn, error), opts Options) *Conn {
+	if opts.FailureThreshold == 0 {
+		opts.FailureThreshold = 5
+	}
+	if opts.EjectionTime <= 0 {
+		opts.EjectionTime = 10 * time.Second
+	}
+	if opts.HealthCheckInterval <= 0 {
+		opts.HealthCheckInterval = 10 * time.Second
+	}
+
+	c := &Conn{
+		opts: opts,
+		dial: dial,
+		pool: drpcpool.New[string, drpcpool.Conn](opts.Pool),
+	}
+	c.SetAddresses(addrs)
+	if opts.HealthCheck != nil {
+		go c.checkHealth()
+	}
+	return c
+}
+
+func (c *Conn) log(what string, cb func() string) {
+	if drpcdebug.Enabled {
+		drpcdebug.Log(func() (_, _, _ string) { return fmt.Sprintf("<bal %p>", c), what, cb() })
+	}
+}
+
+// SetAddresses replaces the set of backends the rpcs are balanced across. The
+// health of the backends that remain is kept, and the cached connections to
+// the backends that are removed are closed.
+func (c *Conn) SetAddresses(addrs []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	existing := make(map[string]*backend, len(c.backends))
+	for _, b := range c.backends {
+		existing[b.addr] = b
+	}
+
+	backends := make([]*backend, 0, len(addrs))
+	for _, addr := range addrs {
+		b, ok := existing[addr]
+		if !ok {
+			b = c.newBackend(addr)
+		} else if b == nil {
+			continue // duplicate address
+		}
+		existing[addr] = nil
+		backends = append(backends, b)
+	}
+	sort.Slice(backends, func(i, j int) bool { return backends[i].addr < backends[j].addr })
+
+	for addr, b := range existing {
+		if b != nil {
+			c.log("REMOVE", func() string { return addr })
+			_ = c.pool.Evict(addr)
+		}
+	}
+
+	c.backends = backends
+	c.ring = nil
+	if c.opts.Policy == ConsistentHash {
+		c.ring = newHashRing(backends)
+	}
+}
+
+// newBackend returns a backend for the address whose dial errors are coded as
+// Unavailable so that they count as failures.
+func (c *Conn) newBackend(addr string) *backend {
+	c.log("ADD", func() string { return addr })
+
+	return &backend{
+		addr: addr,
+		conn: c.pool.Get(context.Background(), addr, func(ctx context.Context, addr string) (drpcpool.Conn, error) {
+			conn, err := c.dial(ctx, addr)
+			if err != nil {
+				return nil, drpcerr.WithCode(err, drpcerr.Unavailable)
+			}
+			return conn, nil
+		}),
+	}
+}
+
+// Close closes the conn and every cached connection to the backends.
+func (c *Conn) Close() error {
+	if !c.done.Set(drpc.ClosedError.New("connection closed")) {
+		return nil
+	}
+	return c.pool.Close()
+}
+
+// Closed returns a channel that is closed once the conn is closed.
+func (c *Conn) Closed() <-chan struct{} { return c.done.Signal() }
+
+// Unblocked returns a channel that is closed when the conn is available for an
+// Invoke or NewStream call. Previous cancels only block the connection to a
+// single backend, so it is always unblocked.
+func (c *Conn) Unblocked() <-chan struct{} { return closedCh }
+
+// Invoke issues the rpc on a connection to a backend picked by the policy.
+func (c *Conn) Invoke(ctx context.Context, rpc string, enc drpc.Encoding, in, out drpc.Message) (err error) {
+	b, err := c.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer b.outstanding.Add(-1)
+
+	err = b.conn.Invoke(ctx, rpc, enc, in, out)
+	c.record(b, err)
+	return err
+}
+
+// NewStream begins a streaming rpc on a connection to a backend picked by the
+// policy.
+func (c *Conn) NewStream(ctx context.Context, rpc string, enc drpc.Encoding) (_ drpc.Stream, err error) {
+	b, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := b.conn.NewStream(ctx, rpc, enc)
+	c.record(b, err)
+	if err != nil {
+		b.outstanding.Add(-1)
+		return nil, err
+	}
+
+	go func() {
+		<-stream.Context().Done()
+		b.outstanding.Add(-1)
+	}()
+
+	return stream, nil
+}
+
+// acquire picks a backend and counts an rpc as outstanding on it.
+func (c *Conn) acquire(ctx context.Context) (*backend, error) {
+	if err, ok := c.done.Get(); ok {
+		return nil, err
+	}
+
+	b, ok := c.pick(ctx)
+	if !ok {
+		return nil, drpcerr.New(drpcerr.Unavailable, "no addresses to balance across")
+	}
+	b.outstanding.Add(1)
+	return b, nil
+}
+
+// record updates the health of the backend after an rpc finished with the
+// error.
+func (c *Conn) record(b *backend, err error) {
+	if c.opts.FailureThreshold < 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if drpcerr.Code(err) != drpcerr.Unavailable {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= c.opts.FailureThreshold {
+		c.log("EJECT", func() string { return b.addr })
+		b.failures = 0
+		b.ejected = time.Now().Add(c.opts.EjectionTime)
+	}
+}
+
+// checkHealth checks the health of every backend once every interval until
+// the conn is closed.
+func (c *Conn) checkHealth() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-c.done.Signal()
+		cancel()
+	}()
+
+	ticker := time.NewTicker(c.opts.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		c.mu.Lock()
+		backends := c.backends
+		c.mu.Unlock()
+
+		var wg sync.WaitGroup
+		for _, b := range backends {
+			wg.Add(1)
+			go func(b *backend) {
+				defer wg.Done()
+				c.checkBackend(ctx, b)
+			}(b)
+		}
+		wg.Wait()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkBackend runs the health check on the backend and records the result.
+func (c *Conn) checkBackend(ctx context.Context, b *backend) {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.HealthCheckInterval)
+	defer cancel()
+
+	unhealthy := c.opts.HealthCheck(ctx, b.conn) != nil
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if unhealthy != b.unhealthy {
+		c.log("HEALTH", func() string { return fmt.Sprintf("%s unhealthy=%v", b.addr, unhealthy) })
+		b.unhealthy = unhealthy
+	}
+}
+
+//
+// backends
+//
+
+// backend is a single address that rpcs are balanced to.
+type backend struct {
+	addr        string
+	conn        drpcpool.Conn
+	outstanding atomic.Int64
+
+	mu        sync.Mutex
+	failures  int       // consecutive unavailable failures
+	ejected   time.Time // when the ejection ends
+	unhealthy bool      // if the last health check failed
+}
+
+// healthy returns true if the backend is not ejected and did not fail its last
+// health check.
+func (b *backend) healthy(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return !b.unhealthy && !now.Before(b.ejected)
+}
+
+// closedCh is an already closed channel.
+var closedCh = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
@@ -0,0 +1,351 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcbalancer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zeebo/assert"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcmetadata"
+	"storj.io/drpc/drpcpool"
+	"storj.io/drpc/drpctest"
+)
+
+func TestBalancer_RoundRobin(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	bk := newBackends()
+	conn := New([]string{"a", "b", "c"}, bk.dial)
+	defer func() { _ = conn.Close() }()
+
+	for i := 0; i < 6; i++ {
+		assert.NoError(t, conn.Invoke(ctx, "rpc", nil, nil, nil))
+	}
+	assert.DeepEqual(t, bk.counts(), map[string]int{"a": 2, "b": 2, "c": 2})
+}
+
+func TestBalancer_LeastOutstanding(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	bk := newBackends()
+	conn := NewWithOptions([]string{"a", "b"}, bk.dial, Options{Policy: LeastOutstanding})
+	defer func() { _ = conn.Close() }()
+
+	// keep a stream open on one of the backends.
+	stream, err := conn.NewStream(ctx, "rpc", nil)
+	assert.NoError(t, err)
+	busy := bk.last()
+
+	// every rpc should go to the other backend while the stream is active.
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, conn.Invoke(ctx, "rpc", nil, nil, nil))
+		assert.That(t, bk.last() != busy)
+	}
+
+	assert.NoError(t, stream.Close())
+}
+
+func TestBalancer_PowerOfTwo(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	bk := newBackends()
+	conn := NewWithOptions([]string{"a", "b"}, bk.dial, Options{Policy: PowerOfTwo})
+	defer func() { _ = conn.Close() }()
+
+	// with two backends, both are always compared, so the idle one is used.
+	stream, err := conn.NewStream(ctx, "rpc", nil)
+	assert.NoError(t, err)
+	busy := bk.last()
+
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, conn.Invoke(ctx, "rpc", nil, nil, nil))
+		assert.That(t, bk.last() != busy)
+	}
+
+	assert.NoError(t, stream.Close())
+}
+
+func TestBalancer_ConsistentHash(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	bk := newBackends()
+	conn := NewWithOptions([]string{"a", "b", "c", "d"}, bk.dial, Options{
+		Policy:  ConsistentHash,
+		HashKey: "user",
+	})
+	defer func() { _ = conn.Close() }()
+
+	// rpcs with the same key go to the same backend.
+	picked := make(map[string]string)
+	for _, user := range []string{"alice", "bob", "carol", "dave", "eve"} {
+		uctx := drpcmetadata.Add(ctx, "user", user)
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, conn.Invoke(uctx, "rpc", nil, nil, nil))
+			if i == 0 {
+				picked[user] = bk.last()
+			}
+			assert.Equal(t, bk.last(), picked[user])
+		}
+	}
+
+	// as do rpcs with the key in their outgoing metadata.
+	for user, addr := range picked {
+		uctx := drpcmetadata.AppendToOutgoingContext(ctx, "user", "mallory", "user", user)
+		assert.NoError(t, conn.Invoke(uctx, "rpc", nil, nil, nil))
+		assert.Equal(t, bk.last(), addr)
+	}
+
+	// removing a backend only moves the keys that were on it.
+	var removed string
+	for _, addr := range picked {
+		removed = addr
+		break
+	}
+	var remaining []string
+	for _, addr := range []string{"a", "b", "c", "d"} {
+		if addr != removed {
+			remaining = append(remaining, addr)
+		}
+	}
+	conn.SetAddresses(remaining)
+
+	for user, addr := range picked {
+		assert.NoError(t, conn.Invoke(drpcmetadata.Add(ctx, "user", user), "rpc", nil, nil, nil))
+		if addr != removed {
+			assert.Equal(t, bk.last(), addr)
+		} else {
+			assert.That(t, bk.last() != removed)
+		}
+	}
+}
+
+func TestBalancer_Ejection(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	bk := newBackends()
+	bk.fail["a"] = true
+	conn := NewWithOptions([]string{"a", "b"}, bk.dial, Options{
+		FailureThreshold: 2,
+		EjectionTime:     time.Hour,
+	})
+	defer func() { _ = conn.Close() }()
+
+	// the failing backend is used until it is ejected.
+	failures := 0
+	for i := 0; i < 10; i++ {
+		if err := conn.Invoke(ctx, "rpc", nil, nil, nil); err != nil {
+			assert.Equal(t, drpcerr.Code(err), drpcerr.Unavailable)
+			failures++
+		}
+	}
+	assert.Equal(t, failures, 2)
+
+	// once every backend is ejected, they are used anyway.
+	bk.fail["b"] = true
+	for i := 0; i < 4; i++ {
+		_ = conn.Invoke(ctx, "rpc", nil, nil, nil)
+	}
+	assert.Error(t, conn.Invoke(ctx, "rpc", nil, nil, nil))
+}
+
+func TestBalancer_HealthCheck(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	var mu sync.Mutex
+	sick := map[string]bool{"a": true}
+	check := func(ctx context.Context, conn drpc.Conn) error {
+		var addr string
+		if err := conn.Invoke(ctx, "health", nil, nil, &addr); err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if sick[addr] {
+			return errors.New("not serving")
+		}
+		return nil
+	}
+
+	bk := newBackends()
+	conn := NewWithOptions([]string{"a", "b"}, bk.dial, Options{
+		HealthCheck:         check,
+		HealthCheckInterval: 10 * time.Millisecond,
+	})
+	defer func() { _ = conn.Close() }()
+
+	waitHealthy := func(addr string, healthy bool) {
+		t.Helper()
+		conn.mu.Lock()
+		backends := conn.backends
+		conn.mu.Unlock()
+		for _, b := range backends {
+			if b.addr != addr {
+				continue
+			}
+			for b.healthy(time.Now()) != healthy {
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}
+
+	// unhealthy backends are not picked.
+	waitHealthy("a", false)
+	for i := 0; i < 4; i++ {
+		var addr string
+		assert.NoError(t, conn.Invoke(ctx, "rpc", nil, nil, &addr))
+		assert.Equal(t, addr, "b")
+	}
+
+	// and are picked again once they pass a check.
+	mu.Lock()
+	sick["a"] = false
+	mu.Unlock()
+	waitHealthy("a", true)
+}
+
+func TestBalancer_SetAddresses(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	bk := newBackends()
+	conn := New(nil, bk.dial)
+	defer func() { _ = conn.Close() }()
+
+	// no addresses is unavailable.
+	err := conn.Invoke(ctx, "rpc", nil, nil, nil)
+	assert.Equal(t, drpcerr.Code(err), drpcerr.Unavailable)
+
+	conn.SetAddresses([]string{"a", "a"})
+	assert.NoError(t, conn.Invoke(ctx, "rpc", nil, nil, nil))
+	assert.NoError(t, conn.Invoke(ctx, "rpc", nil, nil, nil))
+	assert.DeepEqual(t, bk.counts(), map[string]int{"a": 2})
+
+	// the cached connection to a removed backend is closed.
+	conn.SetAddresses([]string{"b"})
+	assert.Equal(t, bk.closes(), 1)
+	assert.NoError(t, conn.Invoke(ctx, "rpc", nil, nil, nil))
+	assert.Equal(t, bk.last(), "b")
+
+	assert.NoError(t, conn.Close())
+	assert.That(t, drpc.ClosedError.Has(conn.Invoke(ctx, "rpc", nil, nil, nil)))
+}
+
+//
+// helpers
+//
+
+// fakeBackends records the rpcs sent to every address.
+type fakeBackends struct {
+	mu     sync.Mutex
+	fail   map[string]bool
+	calls  map[string]int
+	addr   string
+	closed int
+}
+
+func newBackends() *fakeBackends {
+	return &fakeBackends{
+		fail:  make(map[string]bool),
+		calls: make(map[string]int),
+	}
+}
+
+func (f *fakeBackends) counts() map[string]int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	out := make(map[string]int, len(f.calls))
+	for addr, n := range f.calls {
+		out[addr] = n
+	}
+	return out
+}
+
+func (f *fakeBackends) last() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.addr
+}
+
+func (f *fakeBackends) closes() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.closed
+}
+
+func (f *fakeBackends) rpc(addr string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls[addr]++
+	f.addr = addr
+	if f.fail[addr] {
+		return drpcerr.New(drpcerr.Unavailable, "rpc failed")
+	}
+	return nil
+}
+
+func (f *fakeBackends) dial(ctx context.Context, addr string) (drpcpool.Conn, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.fail[addr] {
+		return nil, errors.New("dial failed")
+	}
+	return &fakeConn{addr: addr, backends: f}, nil
+}
+
+// fakeConn is a connection to a single fake backend.
+type fakeConn struct {
+	addr     string
+	backends *fakeBackends
+}
+
+func (f *fakeConn) Close() error {
+	f.backends.mu.Lock()
+	defer f.backends.mu.Unlock()
+
+	f.backends.closed++
+	return nil
+}
+
+func (f *fakeConn) Closed() <-chan struct{}    { return nil }
+func (f *fakeConn) Unblocked() <-chan struct{} { return closedCh }
+
+func (f *fakeConn) Invoke(ctx context.Context, rpc string, enc drpc.Encoding, in, out drpc.Message) error {
+	if addr, ok := out.(*string); ok {
+		*addr = f.addr
+	}
+	return f.backends.rpc(f.addr)
+}
+
+func (f *fakeConn) NewStream(ctx context.Context, rpc string, enc drpc.Encoding) (drpc.Stream, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &fakeStream{ctx: ctx, cancel: cancel}, f.backends.rpc(f.addr)
+}
+
+// fakeStream is a stream that is finished when it is closed.
+type fakeStream struct {
+	drpc.Stream
+	ctx    context.Context
+	cancel func()
+}
+
+func (f *fakeStream) Context() context.Context { return f.ctx }
+func (f *fakeStream) Close() error             { f.cancel(); return nil }
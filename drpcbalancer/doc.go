@@ -0,0 +1,11 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package drpcbalancer is a drpc.Conn that spreads rpcs across many backends.
+//
+// Every rpc picks a backend with one of the balancing policies and is sent on
+// a connection to it that is cached by a drpcpool.Pool. Backends that keep
+// failing with Unavailable errors are ejected from the set of candidates for
+// a while, and backends can be health checked so that the unhealthy ones are
+// not picked.
+package drpcbalancer
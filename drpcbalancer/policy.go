@@ -0,0 +1,177 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcbalancer
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"strconv"
+	"time"
+
+	"storj.io/drpc/drpcmetadata"
+)
+
+// Policy is the way a backend is picked for every rpc.
+type Policy int
+
+const (
+	// RoundRobin picks the backends in order.
+	RoundRobin Policy = iota
+
+	// LeastOutstanding picks the backend with the fewest active rpcs.
+	LeastOutstanding
+
+	// PowerOfTwo picks two backends at random and uses the one with the
+	// fewest active rpcs.
+	PowerOfTwo
+
+	// ConsistentHash picks the backend by hashing the value of the metadata
+	// key set by Options.HashKey, so that rpcs with the same value go to the
+	// same backend while the set of backends is unchanged. The value is taken
+	// from the outgoing metadata or the metadata added with drpcmetadata.Add.
+	// Rpcs without the key are picked with RoundRobin.
+	ConsistentHash
+)
+
+// String returns a string representation of the policy.
+func (p Policy) String() string {
+	switch p {
+	case RoundRobin:
+		return "RoundRobin"
+	case LeastOutstanding:
+		return "LeastOutstanding"
+	case PowerOfTwo:
+		return "PowerOfTwo"
+	case ConsistentHash:
+		return "ConsistentHash"
+	default:
+		return "Policy(" + strconv.Itoa(int(p)) + ")"
+	}
+}
+
+// pick picks a backend for the rpc according to the policy.
+func (c *Conn) pick(ctx context.Context) (*backend, bool) {
+	c.mu.Lock()
+	backends, ring := c.backends, c.ring
+	c.mu.Unlock()
+
+	if len(backends) == 0 {
+		return nil, false
+	}
+
+	// if every backend is ejected or unhealthy, it is better to try one anyway.
+	now := time.Now()
+	healthy := make([]*backend, 0, len(backends))
+	for _, b := range backends {
+		if b.healthy(now) {
+			healthy = append(healthy, b)
+		}
+	}
+	if len(healthy) == 0 {
+		healthy = backends
+	}
+
+	switch c.opts.Policy {
+	case LeastOutstanding:
+		start := c.roundRobin(len(healthy))
+		best := healthy[start]
+		for i := 1; i < len(healthy); i++ {
+			if b := healthy[(start+i)%len(healthy)]; b.outstanding.Load() < best.outstanding.Load() {
+				best = b
+			}
+		}
+		return best, true
+
+	case PowerOfTwo:
+		if len(healthy) == 1 {
+			return healthy[0], true
+		}
+		i := rand.Intn(len(healthy))
+		j := rand.Intn(len(healthy) - 1)
+		if j >= i {
+			j++
+		}
+		if healthy[j].outstanding.Load() < healthy[i].outstanding.Load() {
+			return healthy[j], true
+		}
+		return healthy[i], true
+
+	case ConsistentHash:
+		if value, ok := hashValue(ctx, c.opts.HashKey); ok {
+			return ring.lookup(hashString(value), now), true
+		}
+	}
+
+	return healthy[c.roundRobin(len(healthy))], true
+}
+
+// hashValue returns the value of the metadata key that the rpc is sent with.
+// The outgoing metadata is sent after the metadata added with
+// drpcmetadata.Add, so its last value for the key is preferred.
+func hashValue(ctx context.Context, key string) (string, bool) {
+	if md, ok := drpcmetadata.FromOutgoingContext(ctx); ok {
+		if values := md.Get(key); len(values) > 0 {
+			return values[len(values)-1], true
+		}
+	}
+	return drpcmetadata.GetValue(ctx, key)
+}
+
+// roundRobin returns the next index in [0, n).
+func (c *Conn) roundRobin(n int) int {
+	return int((c.next.Add(1) - 1) % uint64(n))
+}
+
+//
+// consistent hashing
+//
+
+// ringReplicas is the number of points on the ring for every backend, which
+// spreads the keys more evenly.
+const ringReplicas = 100
+
+// ringEntry is a point on the hash ring.
+type ringEntry struct {
+	hash    uint64
+	backend *backend
+}
+
+// hashRing is a sorted set of points for the backends.
+type hashRing []ringEntry
+
+// newHashRing returns a ring with points for every backend.
+func newHashRing(backends []*backend) hashRing {
+	ring := make(hashRing, 0, len(backends)*ringReplicas)
+	for _, b := range backends {
+		for i := 0; i < ringReplicas; i++ {
+			ring = append(ring, ringEntry{
+				hash:    hashString(b.addr + "#" + strconv.Itoa(i)),
+				backend: b,
+			})
+		}
+	}
+	sort.Slice(ring, func(i, j int) bool { return ring[i].hash < ring[j].hash })
+	return ring
+}
+
+// lookup returns the first healthy backend at or after the hash on the ring,
+// or the first backend if none are healthy.
+func (r hashRing) lookup(hash uint64, now time.Time) *backend {
+	start := sort.Search(len(r), func(i int) bool { return r[i].hash >= hash })
+	for i := 0; i < len(r); i++ {
+		if b := r[(start+i)%len(r)].backend; b.healthy(now) {
+			return b
+		}
+	}
+	return r[start%len(r)].backend
+}
+
+// hashString returns the hash of the string.
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum64()
+}
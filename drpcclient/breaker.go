@@ -0,0 +1,281 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcclient
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcerr"
+)
+
+// BreakerOptions controls configuration settings for a Breaker.
+type BreakerOptions struct {
+	// ConsecutiveFailures is the number of consecutive failed calls that
+	// trips the circuit open. If zero, 5 is used. If negative, consecutive
+	// failures never trip the circuit.
+	ConsecutiveFailures int
+
+	// FailureRate, if positive, trips the circuit open when at least this
+	// fraction of the calls in the current window fail.
+	FailureRate float64
+
+	// MinRequests is the number of calls that must be made in the window
+	// before the FailureRate is checked. If zero, 10 is used.
+	MinRequests int
+
+	// Window is how long calls are counted for the FailureRate before the
+	// counts are reset. If zero, 10 seconds is used.
+	Window time.Duration
+
+	// Cooldown is how long the circuit stays open before it is half-open and
+	// lets probing calls through. If zero, 5 seconds is used.
+	Cooldown time.Duration
+
+	// HalfOpenProbes is the number of calls let through while the circuit is
+	// half-open. If they all succeed the circuit is closed, and if any fail it
+	// is open again. If zero, 1 is used.
+	HalfOpenProbes int
+
+	// IsFailure returns true if the error from a call counts as a failure. If
+	// nil, errors without a code and errors with the Unknown,
+	// DeadlineExceeded, ResourceExhausted, Internal, Unavailable or DataLoss
+	// codes are failures.
+	IsFailure func(err error) bool
+}
+
+// isFailure is the default for BreakerOptions.IsFailure.
+func isFailure(err error) bool {
+	switch drpcerr.Code(err) {
+	case 0, drpcerr.Unknown, drpcerr.DeadlineExceeded, drpcerr.ResourceExhausted,
+		drpcerr.Internal, drpcerr.Unavailable, drpcerr.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
+
+// Breaker is a circuit breaker for the calls made on ClientConns. It keeps a
+// circuit for every rpc name on every ClientConn. A circuit trips open after
+// too many failures, and while it is open calls fail immediately with an error
+// with the drpcerr.CircuitOpen code. After a cooldown it lets a few probing
+// calls through to decide if it should close again. The circuits for a
+// ClientConn are removed once it is closed.
+type Breaker struct {
+	opts BreakerOptions
+
+	mu       sync.Mutex
+	circuits map[*ClientConn]map[string]*circuit
+}
+
+// NewBreaker returns a Breaker with the options.
+func NewBreaker(opts BreakerOptions) *Breaker {
+	if opts.ConsecutiveFailures == 0 {
+		opts.ConsecutiveFailures = 5
+	}
+	if opts.MinRequests <= 0 {
+		opts.MinRequests = 10
+	}
+	if opts.Window <= 0 {
+		opts.Window = 10 * time.Second
+	}
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = 5 * time.Second
+	}
+	if opts.HalfOpenProbes <= 0 {
+		opts.HalfOpenProbes = 1
+	}
+	if opts.IsFailure == nil {
+		opts.IsFailure = isFailure
+	}
+
+	return &Breaker{
+		opts:     opts,
+		circuits: make(map[*ClientConn]map[string]*circuit),
+	}
+}
+
+// WithCircuitBreaker returns a DialOption that adds the unary and stream
+// interceptors of a new Breaker with the options.
+func WithCircuitBreaker(opts BreakerOptions) DialOption {
+	b := NewBreaker(opts)
+	return func(opt *dialOptions) {
+		opt.unaryInts = append(opt.unaryInts, b.UnaryClientInterceptor)
+		opt.streamInts = append(opt.streamInts, b.StreamClientInterceptor)
+	}
+}
+
+// UnaryClientInterceptor is a UnaryClientInterceptor that fails the call if
+// the circuit for the rpc is open, and otherwise records if it failed.
+func (b *Breaker) UnaryClientInterceptor(ctx context.Context, rpc string, enc drpc.Encoding, in, out drpc.Message, cc *ClientConn, next UnaryInvoker) error {
+	c := b.circuit(cc, rpc)
+	gen, err := c.allow(rpc)
+	if err != nil {
+		return err
+	}
+	err = next(ctx, rpc, enc, in, out, cc)
+	c.record(gen, err)
+	return err
+}
+
+// StreamClientInterceptor is a StreamClientInterceptor that fails the call if
+// the circuit for the rpc is open, and otherwise records if opening the
+// stream failed. Errors after the stream is open are not recorded.
+func (b *Breaker) StreamClientInterceptor(ctx context.Context, rpc string, enc drpc.Encoding, cc *ClientConn, streamer Streamer) (drpc.Stream, error) {
+	c := b.circuit(cc, rpc)
+	gen, err := c.allow(rpc)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := streamer(ctx, rpc, enc, cc)
+	c.record(gen, err)
+	return stream, err
+}
+
+// circuit returns the circuit for the rpc on the conn, creating it if needed.
+func (b *Breaker) circuit(cc *ClientConn, rpc string) *circuit {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	circuits := b.circuits[cc]
+	if circuits == nil {
+		circuits = make(map[string]*circuit)
+		b.circuits[cc] = circuits
+		if closed := cc.Closed(); closed != nil {
+			go b.forget(cc, closed)
+		}
+	}
+
+	c := circuits[rpc]
+	if c == nil {
+		c = &circuit{opts: &b.opts, windowStart: time.Now()}
+		circuits[rpc] = c
+	}
+	return c
+}
+
+// forget removes the circuits for the conn once it is closed so that the
+// breaker does not keep closed conns alive.
+func (b *Breaker) forget(cc *ClientConn, closed <-chan struct{}) {
+	<-closed
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.circuits, cc)
+}
+
+//
+// circuits
+//
+
+// circuitState is the state of a circuit.
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuit tracks the failures of calls for a single rpc on a single conn.
+type circuit struct {
+	opts *BreakerOptions
+
+	mu          sync.Mutex
+	state       circuitState
+	gen         uint64    // incremented on every state change
+	consecutive int       // consecutive failures while closed
+	requests    int       // calls in the window while closed
+	failures    int       // failed calls in the window while closed
+	windowStart time.Time // when the window started
+	openedAt    time.Time // when the circuit was opened
+	probes      int       // calls let through while half-open
+	successes   int       // successful probes while half-open
+}
+
+// allow returns the generation to record the result of a call with, or an
+// error if the call must fail because the circuit is open.
+func (c *circuit) allow(rpc string) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == circuitOpen && time.Since(c.openedAt) >= c.opts.Cooldown {
+		c.setStateLocked(circuitHalfOpen)
+	}
+
+	switch c.state {
+	case circuitOpen:
+		return 0, drpcerr.Newf(drpcerr.CircuitOpen, "circuit open for %s", rpc)
+	case circuitHalfOpen:
+		if c.probes >= c.opts.HalfOpenProbes {
+			return 0, drpcerr.Newf(drpcerr.CircuitOpen, "circuit half-open for %s", rpc)
+		}
+		c.probes++
+	}
+	return c.gen, nil
+}
+
+// record records the result of a call allowed in the generation. Results for
+// calls that were allowed in a previous state are ignored.
+func (c *circuit) record(gen uint64, err error) {
+	failed := err != nil && c.opts.IsFailure(err)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if gen != c.gen {
+		return
+	}
+
+	switch c.state {
+	case circuitHalfOpen:
+		if failed {
+			c.setStateLocked(circuitOpen)
+			return
+		}
+		c.successes++
+		if c.successes >= c.opts.HalfOpenProbes {
+			c.setStateLocked(circuitClosed)
+		}
+
+	case circuitClosed:
+		if now := time.Now(); now.Sub(c.windowStart) >= c.opts.Window {
+			c.windowStart, c.requests, c.failures = now, 0, 0
+		}
+
+		c.requests++
+		if failed {
+			c.failures++
+			c.consecutive++
+		} else {
+			c.consecutive = 0
+		}
+
+		if c.opts.ConsecutiveFailures > 0 && c.consecutive >= c.opts.ConsecutiveFailures {
+			c.setStateLocked(circuitOpen)
+		} else if c.opts.FailureRate > 0 && c.requests >= c.opts.MinRequests &&
+			float64(c.failures) >= c.opts.FailureRate*float64(c.requests) {
+			c.setStateLocked(circuitOpen)
+		}
+	}
+}
+
+// setStateLocked changes the state and resets the counts. It must be called
+// with the mutex held.
+func (c *circuit) setStateLocked(state circuitState) {
+	c.state = state
+	c.gen++
+	c.consecutive, c.requests, c.failures = 0, 0, 0
+	c.probes, c.successes = 0, 0
+
+	switch state {
+	case circuitOpen:
+		c.openedAt = time.Now()
+	case circuitClosed:
+		c.windowStart = time.Now()
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpctest"
+)
+
+func TestBreaker_Consecutive(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	conn := &failingConn{}
+	cc, err := NewClientConnWithOptions(ctx, conn, WithCircuitBreaker(BreakerOptions{
+		ConsecutiveFailures: 2,
+		Cooldown:            time.Hour,
+	}))
+	assert.NoError(t, err)
+
+	invoke := func(rpc string) error {
+		in, out := "in", ""
+		return cc.Invoke(ctx, rpc, testEncoding{}, &in, &out)
+	}
+
+	// errors that are not failures do not trip the circuit.
+	notFound := drpcerr.New(drpcerr.NotFound, "not found")
+	conn.errs = []error{notFound, notFound, notFound}
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, notFound, invoke("rpc"))
+	}
+
+	// consecutive failures trip it, and calls fail without being sent.
+	conn.errs = []error{unavailable, unavailable}
+	assert.Equal(t, unavailable, invoke("rpc"))
+	assert.Equal(t, unavailable, invoke("rpc"))
+	assert.Equal(t, 5, conn.invokes)
+
+	err = invoke("rpc")
+	assert.Equal(t, uint64(drpcerr.CircuitOpen), drpcerr.Code(err))
+	assert.Equal(t, 5, conn.invokes)
+
+	// other rpcs have their own circuit.
+	assert.NoError(t, invoke("other"))
+}
+
+func TestBreaker_HalfOpen(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	conn := &failingConn{}
+	b := NewBreaker(BreakerOptions{ConsecutiveFailures: 1, Cooldown: time.Hour})
+	cc, err := NewClientConnWithOptions(ctx, conn, WithChainUnaryInterceptor(b.UnaryClientInterceptor))
+	assert.NoError(t, err)
+
+	invoke := func() error {
+		in, out := "in", ""
+		return cc.Invoke(ctx, "rpc", testEncoding{}, &in, &out)
+	}
+	expire := func() {
+		c := b.circuit(cc, "rpc")
+		c.mu.Lock()
+		c.openedAt = time.Now().Add(-time.Hour)
+		c.mu.Unlock()
+	}
+
+	conn.errs = []error{unavailable}
+	assert.Equal(t, unavailable, invoke())
+	assert.Equal(t, uint64(drpcerr.CircuitOpen), drpcerr.Code(invoke()))
+
+	// after the cooldown a failed probe opens the circuit again.
+	expire()
+	conn.errs = []error{unavailable}
+	assert.Equal(t, unavailable, invoke())
+	assert.Equal(t, uint64(drpcerr.CircuitOpen), drpcerr.Code(invoke()))
+
+	// and a successful probe closes it.
+	expire()
+	assert.NoError(t, invoke())
+	assert.NoError(t, invoke())
+	assert.Equal(t, 4, conn.invokes)
+}
+
+func TestBreaker_FailureRate(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	conn := &failingConn{}
+	cc, err := NewClientConnWithOptions(ctx, conn, WithCircuitBreaker(BreakerOptions{
+		ConsecutiveFailures: -1,
+		FailureRate:         0.5,
+		MinRequests:         4,
+		Cooldown:            time.Hour,
+	}))
+	assert.NoError(t, err)
+
+	// alternating failures never trip on consecutive failures, but trip the
+	// rate once there are enough requests.
+	conn.errs = []error{unavailable, nil, unavailable, nil}
+	for i := 0; i < 4; i++ {
+		in, out := "in", ""
+		_ = cc.Invoke(ctx, "rpc", testEncoding{}, &in, &out)
+	}
+
+	in, out := "in", ""
+	err = cc.Invoke(ctx, "rpc", testEncoding{}, &in, &out)
+	assert.Equal(t, uint64(drpcerr.CircuitOpen), drpcerr.Code(err))
+}
+
+func TestBreaker_Forget(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	b := NewBreaker(BreakerOptions{})
+	count := func() int {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		return len(b.circuits)
+	}
+
+	conn := &closingConn{closed: make(chan struct{})}
+	cc, err := NewClientConnWithOptions(ctx, conn, WithChainUnaryInterceptor(b.UnaryClientInterceptor))
+	assert.NoError(t, err)
+
+	in, out := "in", ""
+	assert.NoError(t, cc.Invoke(ctx, "rpc", testEncoding{}, &in, &out))
+	assert.Equal(t, 1, count())
+
+	// the circuits are removed once the conn is closed.
+	assert.NoError(t, cc.Close())
+	assert.Eventually(t, func() bool { return count() == 0 }, time.Second, time.Millisecond)
+}
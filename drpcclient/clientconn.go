@@ -11,7 +11,8 @@ import (
 // connection with dial options such as interceptors.
 type ClientConn struct {
 	drpc.Conn
-	dopts dialOptions
+	dopts    dialOptions
+	throttle *retryThrottle
 }
 
 // NewClientConnWithOptions creates a new ClientConn with the specified dial options and drpc connection.
@@ -25,37 +26,46 @@ func NewClientConnWithOptions(ctx context.Context, conn drpc.Conn, opts ...DialO
 		opt(&clientConn.dopts)
 	}
 	clientConn.initInterceptors()
+	clientConn.throttle = newRetryThrottle(clientConn.dopts.retryBudget)
 	return clientConn, nil
 }
 
 // finalInvoker returns a UnaryInvoker which executes at the end in an interceptor chain.
 func finalInvoker(ctx context.Context, rpc string, enc drpc.Encoding, in, out drpc.Message, cc *ClientConn) error {
-	return cc.Conn.Invoke(ctx, rpc, enc, in, out)
+	return cc.invoke(ctx, rpc, enc, in, out)
 }
 
 func (c *ClientConn) Invoke(ctx context.Context, rpc string, enc drpc.Encoding, in, out drpc.Message) error {
 	if c.dopts.perRPCMetadata != nil {
 		ctx = drpcmetadata.AddPairs(ctx, c.dopts.perRPCMetadata)
 	}
+	ctx, err := c.withCredentials(ctx, rpc)
+	if err != nil {
+		return err
+	}
 	if c.dopts.unaryInt != nil {
 		return c.dopts.unaryInt(ctx, rpc, enc, in, out, c, finalInvoker)
 	}
-	return c.Conn.Invoke(ctx, rpc, enc, in, out)
+	return c.invoke(ctx, rpc, enc, in, out)
 }
 
 // finalStreamer returns a Streamer which executes at the end in an interceptor chain.
 func finalStreamer(ctx context.Context, rpc string, enc drpc.Encoding, cc *ClientConn) (drpc.Stream, error) {
-	return cc.Conn.NewStream(ctx, rpc, enc)
+	return cc.newStream(ctx, rpc, enc)
 }
 
 func (c *ClientConn) NewStream(ctx context.Context, rpc string, enc drpc.Encoding) (drpc.Stream, error) {
 	if c.dopts.perRPCMetadata != nil {
 		ctx = drpcmetadata.AddPairs(ctx, c.dopts.perRPCMetadata)
 	}
+	ctx, err := c.withCredentials(ctx, rpc)
+	if err != nil {
+		return nil, err
+	}
 	if c.dopts.streamInt != nil {
 		return c.dopts.streamInt(ctx, rpc, enc, c, finalStreamer)
 	}
-	return c.Conn.NewStream(ctx, rpc, enc)
+	return c.newStream(ctx, rpc, enc)
 }
 
 func (c *ClientConn) initInterceptors() {
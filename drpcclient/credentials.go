@@ -0,0 +1,62 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcclient
+
+import (
+	"context"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcmetadata"
+)
+
+// PerRPCCredentials provides the metadata that authenticates an rpc, like a
+// bearer token or a signature.
+type PerRPCCredentials interface {
+	// GetRequestMetadata returns the metadata to attach to a call of the rpc.
+	// It is called for every Invoke and NewStream, so anything expensive to
+	// compute, like a token that must be fetched, should be cached.
+	GetRequestMetadata(ctx context.Context, rpc string) (map[string]string, error)
+}
+
+// WithPerRPCCredentials returns a DialOption that attaches the metadata from
+// the credentials to every call. Errors getting the metadata fail the call,
+// and are coded with drpcerr.Unauthenticated if they do not have a code.
+func WithPerRPCCredentials(creds PerRPCCredentials) DialOption {
+	return func(opt *dialOptions) {
+		opt.creds = append(opt.creds, creds)
+	}
+}
+
+// withCredentials returns a context with the metadata from the credentials for
+// a call of the rpc attached. The metadata already on the context is copied
+// rather than added to, because the context may be shared by concurrent calls.
+func (c *ClientConn) withCredentials(ctx context.Context, rpc string) (context.Context, error) {
+	if len(c.dopts.creds) == 0 {
+		return ctx, nil
+	}
+
+	md := make(map[string]string)
+	if existing, ok := drpcmetadata.Get(ctx); ok {
+		for key, value := range existing {
+			md[key] = value
+		}
+	}
+
+	for _, creds := range c.dopts.creds {
+		extra, err := creds.GetRequestMetadata(ctx, rpc)
+		if err != nil {
+			if drpcerr.Code(err) == 0 {
+				err = drpcerr.WithCode(errs.Wrap(err), drpcerr.Unauthenticated)
+			}
+			return nil, err
+		}
+		for key, value := range extra {
+			md[key] = value
+		}
+	}
+
+	return drpcmetadata.AddPairs(drpcmetadata.ClearContext(ctx), md), nil
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcmetadata"
+	"storj.io/drpc/drpctest"
+)
+
+// counterCredentials are credentials that send the rpc and how many times
+// they have been called.
+type counterCredentials struct {
+	calls int
+	err   error
+}
+
+func (c *counterCredentials) GetRequestMetadata(ctx context.Context, rpc string) (map[string]string, error) {
+	c.calls++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return map[string]string{"rpc": rpc, "calls": string(rune('0' + c.calls))}, nil
+}
+
+// metadataConn is a drpc.Conn that records the metadata of its calls.
+type metadataConn struct {
+	mockDrpcConn
+	md map[string]string
+}
+
+func (m *metadataConn) Invoke(ctx context.Context, rpc string, enc drpc.Encoding, in, out drpc.Message) error {
+	m.md, _ = drpcmetadata.Get(ctx)
+	return nil
+}
+
+func (m *metadataConn) NewStream(ctx context.Context, rpc string, enc drpc.Encoding) (drpc.Stream, error) {
+	m.md, _ = drpcmetadata.Get(ctx)
+	return &mockStream{name: rpc}, nil
+}
+
+func TestPerRPCCredentials(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	conn := &metadataConn{}
+	creds := &counterCredentials{}
+	cc, err := NewClientConnWithOptions(ctx, conn, WithPerRPCCredentials(creds))
+	assert.NoError(t, err)
+
+	// the credentials are asked for metadata on every call, and it is merged
+	// with the metadata already on the context without changing it.
+	callCtx := drpcmetadata.Add(ctx, "key", "value")
+	in, out := "in", ""
+	assert.NoError(t, cc.Invoke(callCtx, "invoke", testEncoding{}, &in, &out))
+	assert.Equal(t, map[string]string{"key": "value", "rpc": "invoke", "calls": "1"}, conn.md)
+
+	_, err = cc.NewStream(callCtx, "stream", testEncoding{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"key": "value", "rpc": "stream", "calls": "2"}, conn.md)
+
+	md, _ := drpcmetadata.Get(callCtx)
+	assert.Equal(t, map[string]string{"key": "value"}, md)
+
+	// errors fail the call and are unauthenticated if they have no code.
+	creds.err = errors.New("no token")
+	err = cc.Invoke(ctx, "invoke", testEncoding{}, &in, &out)
+	assert.Equal(t, uint64(drpcerr.Unauthenticated), drpcerr.Code(err))
+
+	creds.err = drpcerr.New(drpcerr.Unavailable, "token service down")
+	_, err = cc.NewStream(ctx, "stream", testEncoding{})
+	assert.Equal(t, uint64(drpcerr.Unavailable), drpcerr.Code(err))
+}
@@ -1,5 +1,12 @@
 package drpcclient
 
+import (
+	"context"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcconn"
+)
+
 // dialOptions configure a NewClientConnWithOptions call. dialOptions are set by the DialOption
 // values passed to NewClientConnWithOptions.
 type dialOptions struct {
@@ -10,6 +17,16 @@ type dialOptions struct {
 	streamInts []StreamClientInterceptor
 
 	perRPCMetadata map[string]string
+	creds          []PerRPCCredentials
+
+	retry       *RetryPolicy
+	methodRetry map[string]*RetryPolicy
+	retryBudget RetryBudget
+
+	hedging     map[string]*HedgingPolicy
+	hedgingDial func(ctx context.Context) (drpc.Conn, error)
+
+	connOpts drpcconn.Options
 }
 
 // DialOption configures how we set up the client connection.
@@ -40,3 +57,62 @@ func WithPerRPCMetadata(metadata map[string]string) DialOption {
 		opt.perRPCMetadata = metadata
 	}
 }
+
+// WithRetryPolicy returns a DialOption that retries failed rpcs according to
+// the policy, unless the rpc has its own policy set by WithMethodRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) DialOption {
+	return func(opt *dialOptions) {
+		policy = policy.withDefaults()
+		opt.retry = &policy
+	}
+}
+
+// WithMethodRetryPolicy returns a DialOption that retries failed calls to the
+// rpc according to the policy instead of the one set by WithRetryPolicy. A
+// policy with MaxAttempts less than 2 disables retries for the rpc.
+func WithMethodRetryPolicy(rpc string, policy RetryPolicy) DialOption {
+	return func(opt *dialOptions) {
+		if opt.methodRetry == nil {
+			opt.methodRetry = make(map[string]*RetryPolicy)
+		}
+		policy = policy.withDefaults()
+		opt.methodRetry[rpc] = &policy
+	}
+}
+
+// WithRetryBudget returns a DialOption that limits the retries of every rpc on
+// the connection with the budget.
+func WithRetryBudget(budget RetryBudget) DialOption {
+	return func(opt *dialOptions) {
+		opt.retryBudget = budget
+	}
+}
+
+// WithHedgingPolicy returns a DialOption that hedges calls to the rpc according
+// to the policy. Hedged rpcs are not retried by the retry policy.
+func WithHedgingPolicy(rpc string, policy HedgingPolicy) DialOption {
+	return func(opt *dialOptions) {
+		if opt.hedging == nil {
+			opt.hedging = make(map[string]*HedgingPolicy)
+		}
+		policy = policy.withDefaults()
+		opt.hedging[rpc] = &policy
+	}
+}
+
+// WithHedgingDialer returns a DialOption that sends every hedged request on a
+// new connection from the dial function. The connection is closed once the
+// request is finished.
+func WithHedgingDialer(dial func(ctx context.Context) (drpc.Conn, error)) DialOption {
+	return func(opt *dialOptions) {
+		opt.hedgingDial = dial
+	}
+}
+
+// WithConnOptions returns a DialOption that sets the options for the
+// connections created by DialTLS.
+func WithConnOptions(opts drpcconn.Options) DialOption {
+	return func(opt *dialOptions) {
+		opt.connOpts = opts
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcclient
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	"storj.io/drpc"
+)
+
+// OnFinish returns a stream that calls fn once the stream finishes, which is
+// when receiving from it fails, with io.EOF if it was successful, or when its
+// context is done otherwise. If the context is done while an operation is in
+// progress, the stream finishes once no operations are in progress. fn is
+// called with the first error other than io.EOF that an operation failed
+// with. It is useful for stream interceptors that record when streams end.
+func OnFinish(stream drpc.Stream, fn func(err error)) drpc.Stream {
+	fs := &finishStream{Stream: stream, fn: fn}
+	go func() {
+		<-stream.Context().Done()
+		fs.contextDone()
+	}()
+	return fs
+}
+
+// finishStream is the stream returned by OnFinish.
+type finishStream struct {
+	drpc.Stream
+	fn func(err error)
+
+	mu       sync.Mutex
+	ops      int   // number of operations in progress
+	err      error // first error other than io.EOF
+	done     bool  // set when the context is done
+	finished bool  // set when fn has been called
+}
+
+// begin starts an operation.
+func (s *finishStream) begin() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ops++
+}
+
+// end finishes an operation that failed with the error. If terminal is true,
+// the stream finishes too.
+func (s *finishStream) end(err error, terminal bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ops--
+	if s.err == nil && err != nil && !errors.Is(err, io.EOF) {
+		s.err = err
+	}
+	if terminal || (s.done && s.ops == 0) {
+		s.finishLocked()
+	}
+}
+
+// contextDone finishes the stream once its context is done unless an
+// operation is in progress, which finishes it instead.
+func (s *finishStream) contextDone() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.done = true
+	if s.ops == 0 {
+		s.finishLocked()
+	}
+}
+
+// finishLocked calls fn if it has not already been called. It must be called
+// with the mutex held.
+func (s *finishStream) finishLocked() {
+	if !s.finished {
+		s.finished = true
+		s.fn(s.err)
+	}
+}
+
+func (s *finishStream) MsgSend(msg drpc.Message, enc drpc.Encoding) (err error) {
+	s.begin()
+	defer func() { s.end(err, false) }()
+
+	return s.Stream.MsgSend(msg, enc)
+}
+
+func (s *finishStream) MsgRecv(msg drpc.Message, enc drpc.Encoding) (err error) {
+	s.begin()
+	defer func() { s.end(err, err != nil) }()
+
+	return s.Stream.MsgRecv(msg, enc)
+}
+
+func (s *finishStream) CloseSend() (err error) {
+	s.begin()
+	defer func() { s.end(err, false) }()
+
+	return s.Stream.CloseSend()
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/drpc"
+)
+
+func TestOnFinish(t *testing.T) {
+	newStream := func(errs ...error) (drpc.Stream, context.CancelFunc, chan error) {
+		ctx, cancel := context.WithCancel(context.Background())
+		finished := make(chan error, 2)
+		stream := OnFinish(&erroringStream{ctx: ctx, errs: errs}, func(err error) { finished <- err })
+		return stream, cancel, finished
+	}
+
+	t.Run("Receive", func(t *testing.T) {
+		stream, cancel, finished := newStream(nil, io.EOF)
+		defer cancel()
+
+		var out string
+		assert.NoError(t, stream.MsgRecv(&out, testEncoding{}))
+		assert.Len(t, finished, 0)
+
+		// the stream finishes successfully when receiving fails with io.EOF,
+		// and only once.
+		assert.Equal(t, io.EOF, stream.MsgRecv(&out, testEncoding{}))
+		assert.NoError(t, <-finished)
+		cancel()
+		assert.Len(t, finished, 0)
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		failed := errors.New("failed")
+		stream, cancel, finished := newStream(failed)
+
+		// the stream finishes with the first error once the context is done.
+		in := "in"
+		assert.Equal(t, failed, stream.MsgSend(&in, testEncoding{}))
+		assert.Len(t, finished, 0)
+		cancel()
+		assert.Equal(t, failed, <-finished)
+	})
+}
+
+// erroringStream is a stream whose operations fail with the queued errors.
+type erroringStream struct {
+	mockStream
+	ctx  context.Context
+	errs []error
+}
+
+func (e *erroringStream) Context() context.Context { return e.ctx }
+
+func (e *erroringStream) next() error {
+	err := e.errs[0]
+	e.errs = e.errs[1:]
+	return err
+}
+
+func (e *erroringStream) MsgSend(msg drpc.Message, enc drpc.Encoding) error { return e.next() }
+func (e *erroringStream) MsgRecv(msg drpc.Message, enc drpc.Encoding) error { return e.next() }
@@ -0,0 +1,149 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcclient
+
+import (
+	"context"
+	"time"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/internal/drpcopts"
+)
+
+// HedgingPolicy describes how to hedge a unary rpc: if the first request has
+// not been answered after a delay, duplicate requests are sent, the first
+// successful response is used, and the remaining requests are canceled. The
+// canceled requests are always soft canceled, so their connections stay usable
+// even if they do not use soft cancels otherwise, unless a request is still
+// being sent. Only idempotent rpcs should be hedged.
+//
+// The hedged requests are sent on connections from the dialer set by
+// WithHedgingDialer. Without one, they are sent on the ClientConn's conn,
+// which must support concurrent rpcs, like a conn from a drpcpool.Pool which
+// uses a separate connection for every concurrent rpc.
+type HedgingPolicy struct {
+	// MaxHedges is the maximum number of requests sent in addition to the
+	// first one. Values less than 1 disable hedging.
+	MaxHedges int
+
+	// Delay is how long to wait for a response before sending each hedged
+	// request.
+	Delay time.Duration
+
+	// NonFatalCodes are the drpcerr codes of failures that cause the next
+	// hedged request to be sent immediately instead of failing the rpc. If
+	// empty, only errors with the Unavailable code are non-fatal.
+	NonFatalCodes []uint64
+}
+
+// withDefaults returns the policy with the defaults filled in.
+func (p HedgingPolicy) withDefaults() HedgingPolicy {
+	if p.Delay < 0 {
+		p.Delay = 0
+	}
+	if len(p.NonFatalCodes) == 0 {
+		p.NonFatalCodes = []uint64{drpcerr.Unavailable}
+	}
+	return p
+}
+
+// nonFatal returns true if the error has one of the non-fatal codes.
+func (p *HedgingPolicy) nonFatal(err error) bool {
+	code := drpcerr.Code(err)
+	for _, c := range p.NonFatalCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// hedgingPolicy returns the policy for the rpc and true if it should be hedged.
+func (c *ClientConn) hedgingPolicy(rpc string) (*HedgingPolicy, bool) {
+	policy, ok := c.dopts.hedging[rpc]
+	if !ok || policy.MaxHedges < 1 {
+		return nil, false
+	}
+	return policy, true
+}
+
+// hedgeResult is the result of a single hedged request.
+type hedgeResult struct {
+	out rawMessage
+	err error
+}
+
+// hedge invokes the rpc with the hedging policy. The input is marshaled once so
+// that every request sends the same data, and each request unmarshals into its
+// own buffer so that only the response that is used is unmarshaled into out.
+func (c *ClientConn) hedge(ctx context.Context, policy *HedgingPolicy, rpc string, enc drpc.Encoding, in, out drpc.Message) error {
+	data, err := enc.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	// the requests that are still running are canceled once one of them is
+	// used. they are always soft canceled so that their connections are not
+	// closed, even if the manager does not use soft cancels.
+	ctx, cancel := context.WithCancel(drpcopts.WithSoftCancel(ctx))
+	defer cancel()
+
+	results := make(chan hedgeResult, policy.MaxHedges+1)
+	send := func(hedged bool) {
+		go func() {
+			in, out := rawMessage(data), rawMessage(nil)
+			err := c.hedgeInvoke(ctx, hedged, rpc, &in, &out)
+			results <- hedgeResult{out: out, err: err}
+		}()
+	}
+
+	send(false)
+	sent, pending := 1, 1
+
+	timer := time.NewTimer(policy.Delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case res := <-results:
+			pending--
+			if res.err == nil {
+				return enc.Unmarshal(res.out, out)
+			} else if !policy.nonFatal(res.err) {
+				return res.err
+			}
+
+			if sent <= policy.MaxHedges {
+				send(true)
+				sent, pending = sent+1, pending+1
+			} else if pending == 0 {
+				return res.err
+			}
+
+		case <-timer.C:
+			if sent <= policy.MaxHedges {
+				send(true)
+				sent, pending = sent+1, pending+1
+				timer.Reset(policy.Delay)
+			}
+		}
+	}
+}
+
+// hedgeInvoke invokes a single request for the hedge, using a connection from
+// the hedging dialer for hedged requests if there is one.
+func (c *ClientConn) hedgeInvoke(ctx context.Context, hedged bool, rpc string, in, out *rawMessage) error {
+	if !hedged || c.dopts.hedgingDial == nil {
+		return c.Conn.Invoke(ctx, rpc, rawEncoding{}, in, out)
+	}
+
+	conn, err := c.dopts.hedgingDial(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	return conn.Invoke(ctx, rpc, rawEncoding{}, in, out)
+}
@@ -0,0 +1,190 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcclient
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcconn"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcmanager"
+	"storj.io/drpc/drpctest"
+)
+
+func TestHedge(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	t.Run("SlowFirst", func(t *testing.T) {
+		canceled := make(chan struct{})
+		conn := &hedgeConn{calls: []hedgeCall{
+			func(ctx context.Context) (string, error) {
+				<-ctx.Done()
+				close(canceled)
+				return "", ctx.Err()
+			},
+			func(ctx context.Context) (string, error) { return "second", nil },
+		}}
+		cc, err := NewClientConnWithOptions(ctx, conn,
+			WithHedgingPolicy("rpc", HedgingPolicy{MaxHedges: 1, Delay: time.Millisecond}))
+		assert.NoError(t, err)
+
+		in, out := "in", ""
+		assert.NoError(t, cc.Invoke(ctx, "rpc", testEncoding{}, &in, &out))
+		assert.Equal(t, "second", out)
+
+		// the slow request is canceled once the hedged one is used.
+		<-canceled
+	})
+
+	t.Run("FastFirst", func(t *testing.T) {
+		conn := &hedgeConn{calls: []hedgeCall{
+			func(ctx context.Context) (string, error) { return "first", nil },
+		}}
+		cc, err := NewClientConnWithOptions(ctx, conn,
+			WithHedgingPolicy("rpc", HedgingPolicy{MaxHedges: 2, Delay: time.Hour}))
+		assert.NoError(t, err)
+
+		in, out := "in", ""
+		assert.NoError(t, cc.Invoke(ctx, "rpc", testEncoding{}, &in, &out))
+		assert.Equal(t, "first", out)
+		assert.Equal(t, 1, conn.count())
+	})
+
+	t.Run("NonFatal", func(t *testing.T) {
+		conn := &hedgeConn{calls: []hedgeCall{
+			func(ctx context.Context) (string, error) { return "", unavailable },
+			func(ctx context.Context) (string, error) { return "second", nil },
+		}}
+		cc, err := NewClientConnWithOptions(ctx, conn,
+			WithHedgingPolicy("rpc", HedgingPolicy{MaxHedges: 1, Delay: time.Hour}))
+		assert.NoError(t, err)
+
+		// the failure sends the hedged request without waiting for the delay.
+		in, out := "in", ""
+		assert.NoError(t, cc.Invoke(ctx, "rpc", testEncoding{}, &in, &out))
+		assert.Equal(t, "second", out)
+	})
+
+	t.Run("Fatal", func(t *testing.T) {
+		notFound := drpcerr.New(drpcerr.NotFound, "not found")
+		conn := &hedgeConn{calls: []hedgeCall{
+			func(ctx context.Context) (string, error) { return "", notFound },
+		}}
+		cc, err := NewClientConnWithOptions(ctx, conn,
+			WithHedgingPolicy("rpc", HedgingPolicy{MaxHedges: 1, Delay: time.Hour}))
+		assert.NoError(t, err)
+
+		in, out := "in", ""
+		assert.Equal(t, notFound, cc.Invoke(ctx, "rpc", testEncoding{}, &in, &out))
+		assert.Equal(t, 1, conn.count())
+	})
+
+	t.Run("Dialer", func(t *testing.T) {
+		conn := &hedgeConn{calls: []hedgeCall{
+			func(ctx context.Context) (string, error) { <-ctx.Done(); return "", ctx.Err() },
+		}}
+		dialed := &hedgeConn{calls: []hedgeCall{
+			func(ctx context.Context) (string, error) { return "dialed", nil },
+		}}
+		cc, err := NewClientConnWithOptions(ctx, conn,
+			WithHedgingPolicy("rpc", HedgingPolicy{MaxHedges: 1, Delay: time.Millisecond}),
+			WithHedgingDialer(func(ctx context.Context) (drpc.Conn, error) { return dialed, nil }))
+		assert.NoError(t, err)
+
+		in, out := "in", ""
+		assert.NoError(t, cc.Invoke(ctx, "rpc", testEncoding{}, &in, &out))
+		assert.Equal(t, "dialed", out)
+		assert.Equal(t, 1, conn.count())
+	})
+
+	t.Run("SoftCancel", func(t *testing.T) {
+		pc, ps := net.Pipe()
+		defer func() { _ = pc.Close() }()
+		defer func() { _ = ps.Close() }()
+
+		man := drpcmanager.New(ps)
+		defer func() { _ = man.Close() }()
+
+		received := make(chan struct{})
+		ctx.Run(func(ctx context.Context) {
+			// the first request hangs until it is canceled.
+			stream, _, err := man.NewServerStream(ctx)
+			assert.NoError(t, err)
+			var in string
+			assert.NoError(t, stream.MsgRecv(&in, testEncoding{}))
+			close(received)
+			<-stream.Context().Done()
+
+			// the next one is answered on the same transport.
+			stream, _, err = man.NewServerStream(ctx)
+			assert.NoError(t, err)
+			assert.NoError(t, stream.MsgRecv(&in, testEncoding{}))
+			_ = stream.MsgSend(&in, testEncoding{})
+			_ = stream.CloseSend()
+		})
+
+		// the conn does not use soft cancels itself.
+		conn := drpcconn.New(pc)
+		defer func() { _ = conn.Close() }()
+
+		// the hedged request is only answered once the first one has been sent,
+		// because requests that are still being sent must be hard canceled.
+		dialed := &hedgeConn{calls: []hedgeCall{
+			func(ctx context.Context) (string, error) { <-received; return "dialed", nil },
+		}}
+		cc, err := NewClientConnWithOptions(ctx, conn,
+			WithHedgingPolicy("rpc", HedgingPolicy{MaxHedges: 1, Delay: time.Millisecond}),
+			WithHedgingDialer(func(ctx context.Context) (drpc.Conn, error) { return dialed, nil }))
+		assert.NoError(t, err)
+
+		in, out := "in", ""
+		assert.NoError(t, cc.Invoke(ctx, "rpc", testEncoding{}, &in, &out))
+		assert.Equal(t, "dialed", out)
+
+		// the losing request was soft canceled, so its conn is still usable.
+		in, out = "again", ""
+		assert.NoError(t, conn.Invoke(ctx, "other", testEncoding{}, &in, &out))
+		assert.Equal(t, "again", out)
+	})
+}
+
+type hedgeCall = func(ctx context.Context) (string, error)
+
+// hedgeConn is a drpc.Conn whose concurrent invokes are answered by calls in
+// the order they are made.
+type hedgeConn struct {
+	mockDrpcConn
+
+	mu    sync.Mutex
+	calls []hedgeCall
+	n     int
+}
+
+func (h *hedgeConn) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.n
+}
+
+func (h *hedgeConn) Invoke(ctx context.Context, rpc string, enc drpc.Encoding, in, out drpc.Message) error {
+	h.mu.Lock()
+	call := h.calls[h.n]
+	h.n++
+	h.mu.Unlock()
+
+	resp, err := call(ctx)
+	if err != nil {
+		return err
+	}
+	return enc.Unmarshal([]byte(resp), out)
+}
@@ -0,0 +1,322 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcclient
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcerr"
+)
+
+// State is the connectivity state of a ReconnectingConn.
+type State int
+
+const (
+	// Idle means there is no connection, and one is dialed by the next rpc.
+	Idle State = iota
+
+	// Connecting means a connection is being dialed.
+	Connecting
+
+	// Ready means there is a connection that rpcs are sent on.
+	Ready
+
+	// TransientFailure means the last dial failed, and rpcs fail until the
+	// backoff has passed.
+	TransientFailure
+
+	// Shutdown means the conn has been closed.
+	Shutdown
+)
+
+// String returns a string representation of the state.
+func (s State) String() string {
+	switch s {
+	case Idle:
+		return "Idle"
+	case Connecting:
+		return "Connecting"
+	case Ready:
+		return "Ready"
+	case TransientFailure:
+		return "TransientFailure"
+	case Shutdown:
+		return "Shutdown"
+	default:
+		return "State(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// ReconnectOptions controls configuration settings for a ReconnectingConn.
+type ReconnectOptions struct {
+	// InitialBackoff is how long to wait after the first failed dial before
+	// dialing again. If zero, 1 second is used.
+	InitialBackoff time.Duration
+
+	// MaxBackoff bounds how long to wait after any failed dial. If zero, 2
+	// minutes is used.
+	MaxBackoff time.Duration
+
+	// BackoffMultiplier is the factor the backoff grows by after every failed
+	// dial. If less than 1, 1.6 is used.
+	BackoffMultiplier float64
+
+	// Jitter is the fraction of every backoff that is randomized, between 0
+	// and 1. If zero, 0.2 is used. If negative, there is no jitter.
+	Jitter float64
+}
+
+// ReconnectingConn is a drpc.Conn that dials a connection when an rpc needs
+// one, and dials a new one for the next rpc after the connection is closed,
+// like when the transport fails. While it waits for the backoff after a failed
+// dial, rpcs fail immediately with an error with the Unavailable code.
+type ReconnectingConn struct {
+	opts   ReconnectOptions
+	dial   func(ctx context.Context) (drpc.Conn, error)
+	ctx    context.Context
+	cancel func()
+
+	mu       sync.Mutex
+	state    State
+	changed  chan struct{} // closed when the state changes
+	conn     drpc.Conn
+	err      error         // the error from the last dial
+	failures int           // consecutive failed dials
+	retryAt  time.Time     // when the backoff after a failed dial ends
+	dialing  chan struct{} // closed when the current dial is finished
+}
+
+var _ drpc.Conn = (*ReconnectingConn)(nil)
+
+// NewReconnectingConn returns a conn that uses connections from the dial
+// function.
+func NewReconnectingConn(dial func(ctx context.Context) (drpc.Conn, error)) *ReconnectingConn {
+	return NewReconnectingConnWithOptions(dial, ReconnectOptions{})
+}
+
+// NewReconnectingConnWithOptions returns a conn that uses connections from the
+// dial function. The ReconnectOptions control how often it dials after
+// failures.
+func NewReconnectingConnWithOptions(dial func(ctx context.Context) (drpc.Conn, error), opts ReconnectOptions) *ReconnectingConn {
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = 2 * time.Minute
+	}
+	if opts.BackoffMultiplier < 1 {
+		opts.BackoffMultiplier = 1.6
+	}
+	if opts.Jitter == 0 {
+		opts.Jitter = 0.2
+	} else if opts.Jitter < 0 {
+		opts.Jitter = 0
+	} else if opts.Jitter > 1 {
+		opts.Jitter = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ReconnectingConn{
+		opts:    opts,
+		dial:    dial,
+		ctx:     ctx,
+		cancel:  cancel,
+		changed: make(chan struct{}),
+	}
+}
+
+// State returns the current connectivity state.
+func (c *ReconnectingConn) State() State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.state
+}
+
+// WaitForStateChange blocks until the state is different from source, or the
+// context is canceled. It returns true if the state changed.
+func (c *ReconnectingConn) WaitForStateChange(ctx context.Context, source State) bool {
+	c.mu.Lock()
+	state, changed := c.state, c.changed
+	c.mu.Unlock()
+
+	if state != source {
+		return true
+	}
+
+	select {
+	case <-changed:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// setStateLocked changes the state, waking up any waiters. It must be called
+// with the mutex held.
+func (c *ReconnectingConn) setStateLocked(state State) {
+	if c.state == state {
+		return
+	}
+	c.state = state
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// getConn returns the connection to use for an rpc, dialing one if necessary.
+func (c *ReconnectingConn) getConn(ctx context.Context) (drpc.Conn, error) {
+	for {
+		c.mu.Lock()
+		switch c.state {
+		case Shutdown:
+			c.mu.Unlock()
+			return nil, drpc.ClosedError.New("connection closed")
+
+		case Ready:
+			conn := c.conn
+			c.mu.Unlock()
+			return conn, nil
+
+		case TransientFailure:
+			if time.Now().Before(c.retryAt) {
+				err := c.err
+				c.mu.Unlock()
+				return nil, err
+			}
+			c.startDialLocked()
+
+		case Idle:
+			c.startDialLocked()
+		}
+		dialing := c.dialing
+		c.mu.Unlock()
+
+		select {
+		case <-dialing:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// startDialLocked dials a connection in the background. It must be called with
+// the mutex held.
+func (c *ReconnectingConn) startDialLocked() {
+	c.setStateLocked(Connecting)
+	c.dialing = make(chan struct{})
+	go c.connect(c.dialing)
+}
+
+// connect dials a connection, updating the state with the result.
+func (c *ReconnectingConn) connect(dialing chan struct{}) {
+	defer close(dialing)
+
+	conn, err := c.dial(c.ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == Shutdown {
+		if conn != nil {
+			_ = conn.Close()
+		}
+		return
+	}
+
+	if err != nil {
+		c.failures++
+		c.err = drpcerr.WithCode(err, drpcerr.Unavailable)
+		c.retryAt = time.Now().Add(backoff(c.opts.InitialBackoff, c.opts.MaxBackoff,
+			c.opts.BackoffMultiplier, c.opts.Jitter, c.failures))
+		c.setStateLocked(TransientFailure)
+		return
+	}
+
+	c.failures, c.err = 0, nil
+	c.conn = conn
+	c.setStateLocked(Ready)
+	go c.watch(conn)
+}
+
+// watch waits for the connection to be closed so that the next rpc dials a
+// new one.
+func (c *ReconnectingConn) watch(conn drpc.Conn) {
+	select {
+	case <-conn.Closed():
+	case <-c.ctx.Done():
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == conn && c.state == Ready {
+		c.conn = nil
+		c.setStateLocked(Idle)
+	}
+}
+
+// Close closes the current connection and causes any future rpcs to fail.
+func (c *ReconnectingConn) Close() error {
+	c.mu.Lock()
+	if c.state == Shutdown {
+		c.mu.Unlock()
+		return nil
+	}
+	c.setStateLocked(Shutdown)
+	conn := c.conn
+	c.conn = nil
+	c.mu.Unlock()
+
+	c.cancel()
+	if conn != nil {
+		return conn.Close()
+	}
+	return nil
+}
+
+// Closed returns a channel that is closed once the conn is closed.
+func (c *ReconnectingConn) Closed() <-chan struct{} { return c.ctx.Done() }
+
+// Unblocked returns a channel that is closed when the current connection is
+// available for an Invoke or NewStream call, if it reports that.
+func (c *ReconnectingConn) Unblocked() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if conn, ok := c.conn.(interface{ Unblocked() <-chan struct{} }); ok {
+		return conn.Unblocked()
+	}
+	return closedCh
+}
+
+// Invoke issues the rpc on the current connection, dialing one if necessary.
+func (c *ReconnectingConn) Invoke(ctx context.Context, rpc string, enc drpc.Encoding, in, out drpc.Message) error {
+	conn, err := c.getConn(ctx)
+	if err != nil {
+		return err
+	}
+	return conn.Invoke(ctx, rpc, enc, in, out)
+}
+
+// NewStream begins a streaming rpc on the current connection, dialing one if
+// necessary.
+func (c *ReconnectingConn) NewStream(ctx context.Context, rpc string, enc drpc.Encoding) (drpc.Stream, error) {
+	conn, err := c.getConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return conn.NewStream(ctx, rpc, enc)
+}
+
+// closedCh is an already closed channel.
+var closedCh = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
@@ -0,0 +1,122 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpctest"
+)
+
+func TestReconnectingConn(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	var mu sync.Mutex
+	var dials int
+	var dialErr error
+	var conns []*closingConn
+	dial := func(ctx context.Context) (drpc.Conn, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		dials++
+		if dialErr != nil {
+			return nil, dialErr
+		}
+		conn := &closingConn{closed: make(chan struct{})}
+		conns = append(conns, conn)
+		return conn, nil
+	}
+	setErr := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		dialErr = err
+	}
+
+	conn := NewReconnectingConnWithOptions(dial, ReconnectOptions{
+		InitialBackoff: time.Hour,
+		Jitter:         -1,
+	})
+	defer func() { _ = conn.Close() }()
+
+	// connections are dialed lazily.
+	assert.Equal(t, Idle, conn.State())
+	in, out := "in", ""
+	assert.NoError(t, conn.Invoke(ctx, "rpc", testEncoding{}, &in, &out))
+	assert.Equal(t, Ready, conn.State())
+	assert.Equal(t, 1, dials)
+
+	// the connection is reused while it is open.
+	assert.NoError(t, conn.Invoke(ctx, "rpc", testEncoding{}, &in, &out))
+	assert.Equal(t, 1, dials)
+
+	// once it is closed, the conn is idle until the next rpc.
+	close(conns[0].closed)
+	assert.True(t, conn.WaitForStateChange(ctx, Ready))
+	assert.Equal(t, Idle, conn.State())
+
+	// failed dials fail the rpc and every rpc during the backoff.
+	setErr(errors.New("dial failed"))
+	err := conn.Invoke(ctx, "rpc", testEncoding{}, &in, &out)
+	assert.Equal(t, uint64(drpcerr.Unavailable), drpcerr.Code(err))
+	assert.Equal(t, TransientFailure, conn.State())
+
+	err = conn.Invoke(ctx, "rpc", testEncoding{}, &in, &out)
+	assert.Equal(t, uint64(drpcerr.Unavailable), drpcerr.Code(err))
+	assert.Equal(t, 2, dials)
+
+	// once the backoff has passed, the next rpc dials again.
+	setErr(nil)
+	conn.mu.Lock()
+	conn.retryAt = time.Now()
+	conn.mu.Unlock()
+	assert.NoError(t, conn.Invoke(ctx, "rpc", testEncoding{}, &in, &out))
+	assert.Equal(t, Ready, conn.State())
+	assert.Equal(t, 3, dials)
+
+	// closing shuts down the conn and its connection.
+	wctx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	assert.False(t, conn.WaitForStateChange(wctx, Ready))
+
+	assert.NoError(t, conn.Close())
+	assert.Equal(t, Shutdown, conn.State())
+	assert.True(t, conns[1].isClosed())
+	<-conn.Closed()
+
+	_, err = conn.NewStream(ctx, "rpc", testEncoding{})
+	assert.True(t, drpc.ClosedError.Has(err))
+}
+
+// closingConn is a drpc.Conn that can be closed.
+type closingConn struct {
+	mockDrpcConn
+	once   sync.Once
+	closed chan struct{}
+}
+
+func (c *closingConn) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return nil
+}
+
+func (c *closingConn) Closed() <-chan struct{} { return c.closed }
+
+func (c *closingConn) isClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
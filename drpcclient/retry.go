@@ -0,0 +1,400 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcclient
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcerr"
+)
+
+// RetryPolicy describes when and how often failed rpcs are retried. Unary rpcs
+// are retried whenever they fail with a retryable code. Streams are retried
+// only until the first message is received from the server: the messages sent
+// before then are buffered and sent again on the new stream. A stream that
+// sends more than MaxBufferSize bytes before then is no longer retried.
+//
+// When the ClientConn is backed by a drpcpool conn, every attempt takes a
+// connection from the pool, so attempts after a transport failure land on a
+// fresh connection.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	// Values less than 2 disable retries.
+	MaxAttempts int
+
+	// InitialBackoff is how long to wait before the first retry. If zero,
+	// 100 milliseconds is used.
+	InitialBackoff time.Duration
+
+	// MaxBackoff bounds how long to wait before any retry. If zero, 5 seconds
+	// is used.
+	MaxBackoff time.Duration
+
+	// BackoffMultiplier is the factor the backoff grows by after every retry.
+	// If less than 1, 2 is used.
+	BackoffMultiplier float64
+
+	// Jitter is the fraction of every backoff that is randomized, between 0
+	// and 1. For example, 0.2 waits for between 80% and 100% of the backoff.
+	Jitter float64
+
+	// RetryableCodes are the drpcerr codes that are retried. If empty, only
+	// errors with the Unavailable code are retried.
+	RetryableCodes []uint64
+
+	// MaxBufferSize is the most message data a stream buffers to send again
+	// if it is retried. Once a stream has sent more, the buffer is released
+	// and the stream is no longer retried. If zero, 256KiB is used.
+	MaxBufferSize int
+}
+
+// withDefaults returns the policy with the defaults filled in.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = 100 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 5 * time.Second
+	}
+	if p.BackoffMultiplier < 1 {
+		p.BackoffMultiplier = 2
+	}
+	if p.Jitter < 0 {
+		p.Jitter = 0
+	} else if p.Jitter > 1 {
+		p.Jitter = 1
+	}
+	if len(p.RetryableCodes) == 0 {
+		p.RetryableCodes = []uint64{drpcerr.Unavailable}
+	}
+	if p.MaxBufferSize <= 0 {
+		p.MaxBufferSize = 256 << 10
+	}
+	return p
+}
+
+// retryable returns true if the error has one of the retryable codes.
+func (p *RetryPolicy) retryable(err error) bool {
+	code := drpcerr.Code(err)
+	for _, c := range p.RetryableCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns how long to wait before the retry with the given number,
+// starting at 1 for the first retry.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	return backoff(p.InitialBackoff, p.MaxBackoff, p.BackoffMultiplier, p.Jitter, retry)
+}
+
+// backoff returns the delay before the nth retry, starting at 1, for an
+// exponential backoff with the jitter fraction of the delay randomized.
+func backoff(initial, max time.Duration, multiplier, jitter float64, n int) time.Duration {
+	delay := float64(initial) * math.Pow(multiplier, float64(n-1))
+	if delay > float64(max) {
+		delay = float64(max)
+	}
+	delay -= delay * jitter * rand.Float64()
+	return time.Duration(delay)
+}
+
+// RetryBudget limits retries across every rpc on a ClientConn so that retries
+// do not overwhelm a server that is failing. The budget starts with MaxTokens
+// tokens. Every failure that would be retried removes a token, every success
+// adds TokenRatio tokens, and retries are only attempted while more than half
+// of MaxTokens remain.
+type RetryBudget struct {
+	// MaxTokens is the size of the budget. It must be positive for the budget
+	// to have any effect.
+	MaxTokens float64
+
+	// TokenRatio is the number of tokens added by every successful rpc. If
+	// zero, 0.1 is used.
+	TokenRatio float64
+}
+
+// retryThrottle keeps track of the tokens in a RetryBudget.
+type retryThrottle struct {
+	mu     sync.Mutex
+	budget RetryBudget
+	tokens float64
+}
+
+func newRetryThrottle(budget RetryBudget) *retryThrottle {
+	if budget.MaxTokens <= 0 {
+		return nil
+	}
+	if budget.TokenRatio <= 0 {
+		budget.TokenRatio = 0.1
+	}
+	return &retryThrottle{budget: budget, tokens: budget.MaxTokens}
+}
+
+// success records a successful rpc.
+func (t *retryThrottle) success() {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.tokens = math.Min(t.tokens+t.budget.TokenRatio, t.budget.MaxTokens)
+}
+
+// failure records a retryable failure and returns true if the rpc may be
+// retried.
+func (t *retryThrottle) failure() bool {
+	if t == nil {
+		return true
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.tokens = math.Max(t.tokens-1, 0)
+	return t.tokens > t.budget.MaxTokens/2
+}
+
+// retryPolicy returns the policy for the rpc and true if it should be retried.
+func (c *ClientConn) retryPolicy(rpc string) (*RetryPolicy, bool) {
+	policy, ok := c.dopts.methodRetry[rpc]
+	if !ok {
+		policy = c.dopts.retry
+	}
+	if policy == nil || policy.MaxAttempts < 2 {
+		return nil, false
+	}
+	return policy, true
+}
+
+// shouldRetry records the result of an attempt and returns true if it should
+// be retried after waiting for the backoff.
+func (c *ClientConn) shouldRetry(ctx context.Context, policy *RetryPolicy, attempt int, err error) bool {
+	if err == nil {
+		c.throttle.success()
+		return false
+	}
+	if ctx.Err() != nil || !policy.retryable(err) {
+		return false
+	}
+	if !c.throttle.failure() || attempt >= policy.MaxAttempts {
+		return false
+	}
+
+	timer := time.NewTimer(policy.backoff(attempt))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// invoke calls Invoke on the underlying conn, hedging or retrying if there is
+// a policy for the rpc.
+func (c *ClientConn) invoke(ctx context.Context, rpc string, enc drpc.Encoding, in, out drpc.Message) error {
+	if policy, ok := c.hedgingPolicy(rpc); ok {
+		return c.hedge(ctx, policy, rpc, enc, in, out)
+	}
+
+	policy, ok := c.retryPolicy(rpc)
+	if !ok {
+		return c.Conn.Invoke(ctx, rpc, enc, in, out)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := c.Conn.Invoke(ctx, rpc, enc, in, out)
+		if !c.shouldRetry(ctx, policy, attempt, err) {
+			return err
+		}
+	}
+}
+
+// newStream calls NewStream on the underlying conn, returning a stream that
+// retries if there is a policy.
+func (c *ClientConn) newStream(ctx context.Context, rpc string, enc drpc.Encoding) (drpc.Stream, error) {
+	policy, ok := c.retryPolicy(rpc)
+	if !ok {
+		return c.Conn.NewStream(ctx, rpc, enc)
+	}
+
+	rs := &retryStream{
+		cc:     c,
+		ctx:    ctx,
+		rpc:    rpc,
+		enc:    enc,
+		policy: policy,
+	}
+	if err := rs.open(); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+//
+// streams
+//
+
+// retryStream is a stream that opens a new stream and sends the buffered
+// messages again if it fails before the first message is received.
+type retryStream struct {
+	cc     *ClientConn
+	ctx    context.Context
+	rpc    string
+	enc    drpc.Encoding
+	policy *RetryPolicy
+
+	mu        sync.Mutex
+	stream    drpc.Stream
+	attempt   int
+	sent      [][]byte // messages to send again, if not committed
+	buffered  int      // total size of the messages to send again
+	closed    bool     // CloseSend was called
+	committed bool     // a message was received, so no more retries
+}
+
+// open opens a stream, retrying if opening it fails.
+func (rs *retryStream) open() error {
+	for {
+		rs.attempt++
+		stream, err := rs.cc.Conn.NewStream(rs.ctx, rs.rpc, rs.enc)
+		if err == nil {
+			rs.stream = stream
+			return nil
+		}
+		if !rs.cc.shouldRetry(rs.ctx, rs.policy, rs.attempt, err) {
+			return err
+		}
+	}
+}
+
+// retry replaces the failed stream with a new one after the error. It returns
+// nil if the stream was replaced, and otherwise the error to report.
+func (rs *retryStream) retry(err error) error {
+	if rs.committed || !rs.cc.shouldRetry(rs.ctx, rs.policy, rs.attempt, err) {
+		return err
+	}
+	_ = rs.stream.Close()
+
+	if err := rs.open(); err != nil {
+		return err
+	}
+	for _, data := range rs.sent {
+		data := data
+		if err := rs.stream.MsgSend((*rawMessage)(&data), rawEncoding{}); err != nil {
+			return rs.retry(err)
+		}
+	}
+	if rs.closed {
+		if err := rs.stream.CloseSend(); err != nil {
+			return rs.retry(err)
+		}
+	}
+	return nil
+}
+
+// current returns the stream for the current attempt.
+func (rs *retryStream) current() drpc.Stream {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	return rs.stream
+}
+
+// Context returns the context of the stream for the current attempt.
+func (rs *retryStream) Context() context.Context { return rs.current().Context() }
+
+// MsgSend sends the message, buffering it to send again if the stream is
+// retried. If the buffer would grow past the policy's MaxBufferSize, the stream
+// is committed instead. Errors sending on a stream that is not committed are
+// not returned, and the next receive retries the stream instead.
+func (rs *retryStream) MsgSend(msg drpc.Message, enc drpc.Encoding) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.committed {
+		return rs.stream.MsgSend(msg, enc)
+	}
+
+	data, err := enc.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if rs.buffered+len(data) > rs.policy.MaxBufferSize {
+		rs.committed, rs.sent, rs.buffered = true, nil, 0
+		return rs.stream.MsgSend((*rawMessage)(&data), rawEncoding{})
+	}
+	rs.sent = append(rs.sent, data)
+	rs.buffered += len(data)
+
+	// errors sending are reported by the next receive, which retries.
+	_ = rs.stream.MsgSend((*rawMessage)(&data), rawEncoding{})
+	return nil
+}
+
+// MsgRecv receives a message, retrying the stream if it fails before the first
+// message is received. The mutex is not held while receiving so that messages
+// can be sent concurrently.
+func (rs *retryStream) MsgRecv(msg drpc.Message, enc drpc.Encoding) error {
+	for {
+		err := rs.current().MsgRecv(msg, enc)
+
+		rs.mu.Lock()
+		if err == nil && !rs.committed {
+			rs.committed, rs.sent, rs.buffered = true, nil, 0
+			rs.cc.throttle.success()
+		}
+		if err != nil {
+			err = rs.retry(err)
+		}
+		retried := err == nil && !rs.committed
+		rs.mu.Unlock()
+
+		if !retried {
+			return err
+		}
+	}
+}
+
+// CloseSend signals to the remote that no more messages are sent. Like
+// MsgSend, errors on a stream that is not committed are not returned.
+func (rs *retryStream) CloseSend() error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.closed = true
+	if err := rs.stream.CloseSend(); err != nil && rs.committed {
+		return err
+	}
+	return nil
+}
+
+// Close closes the stream for the current attempt.
+func (rs *retryStream) Close() error { return rs.current().Close() }
+
+// rawMessage is a message that has already been marshaled.
+type rawMessage []byte
+
+// rawEncoding sends rawMessages as they are.
+type rawEncoding struct{}
+
+func (rawEncoding) Marshal(msg drpc.Message) ([]byte, error) {
+	return *msg.(*rawMessage), nil
+}
+
+func (rawEncoding) Unmarshal(buf []byte, msg drpc.Message) error {
+	*msg.(*rawMessage) = append((*msg.(*rawMessage))[:0], buf...)
+	return nil
+}
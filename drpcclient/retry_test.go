@@ -0,0 +1,239 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpctest"
+)
+
+var fastRetries = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Microsecond,
+	MaxBackoff:     time.Microsecond,
+}
+
+func TestRetry_Unary(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	t.Run("Succeeds", func(t *testing.T) {
+		conn := &failingConn{errs: []error{unavailable, unavailable}}
+		cc, err := NewClientConnWithOptions(ctx, conn, WithRetryPolicy(fastRetries))
+		assert.NoError(t, err)
+
+		in, out := "in", ""
+		assert.NoError(t, cc.Invoke(ctx, "rpc", testEncoding{}, &in, &out))
+		assert.Equal(t, 3, conn.invokes)
+		assert.Equal(t, "in", out)
+	})
+
+	t.Run("MaxAttempts", func(t *testing.T) {
+		conn := &failingConn{errs: []error{unavailable, unavailable, unavailable}}
+		cc, err := NewClientConnWithOptions(ctx, conn, WithRetryPolicy(fastRetries))
+		assert.NoError(t, err)
+
+		in, out := "in", ""
+		assert.Equal(t, unavailable, cc.Invoke(ctx, "rpc", testEncoding{}, &in, &out))
+		assert.Equal(t, 3, conn.invokes)
+	})
+
+	t.Run("NotRetryable", func(t *testing.T) {
+		notFound := drpcerr.New(drpcerr.NotFound, "not found")
+		conn := &failingConn{errs: []error{notFound}}
+		cc, err := NewClientConnWithOptions(ctx, conn, WithRetryPolicy(fastRetries))
+		assert.NoError(t, err)
+
+		in, out := "in", ""
+		assert.Equal(t, notFound, cc.Invoke(ctx, "rpc", testEncoding{}, &in, &out))
+		assert.Equal(t, 1, conn.invokes)
+	})
+
+	t.Run("MethodOverride", func(t *testing.T) {
+		conn := &failingConn{errs: []error{unavailable}}
+		cc, err := NewClientConnWithOptions(ctx, conn,
+			WithRetryPolicy(fastRetries),
+			WithMethodRetryPolicy("once", RetryPolicy{MaxAttempts: 1}))
+		assert.NoError(t, err)
+
+		in, out := "in", ""
+		assert.Equal(t, unavailable, cc.Invoke(ctx, "once", testEncoding{}, &in, &out))
+		assert.Equal(t, 1, conn.invokes)
+	})
+
+	t.Run("Budget", func(t *testing.T) {
+		conn := &failingConn{errs: []error{unavailable, unavailable, unavailable, unavailable}}
+		cc, err := NewClientConnWithOptions(ctx, conn,
+			WithRetryPolicy(fastRetries),
+			WithRetryBudget(RetryBudget{MaxTokens: 4}))
+		assert.NoError(t, err)
+
+		// the first failure leaves 3 tokens, which is enough to retry, but the
+		// second leaves 2, which is not.
+		in, out := "in", ""
+		assert.Equal(t, unavailable, cc.Invoke(ctx, "rpc", testEncoding{}, &in, &out))
+		assert.Equal(t, 2, conn.invokes)
+	})
+}
+
+func TestRetry_Stream(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	t.Run("BeforeReceive", func(t *testing.T) {
+		conn := &failingConn{errs: []error{unavailable}}
+		cc, err := NewClientConnWithOptions(ctx, conn, WithRetryPolicy(fastRetries))
+		assert.NoError(t, err)
+
+		stream, err := cc.NewStream(ctx, "rpc", testEncoding{})
+		assert.NoError(t, err)
+
+		a, b := "a", "b"
+		assert.NoError(t, stream.MsgSend(&a, testEncoding{}))
+		assert.NoError(t, stream.MsgSend(&b, testEncoding{}))
+		assert.NoError(t, stream.CloseSend())
+
+		// the first stream fails, so the messages are sent again.
+		var out string
+		assert.NoError(t, stream.MsgRecv(&out, testEncoding{}))
+		assert.Equal(t, "ab", out)
+		assert.Equal(t, 2, conn.streams)
+	})
+
+	t.Run("SendFails", func(t *testing.T) {
+		conn := &failingConn{sendErrs: []error{unavailable}}
+		cc, err := NewClientConnWithOptions(ctx, conn, WithRetryPolicy(fastRetries))
+		assert.NoError(t, err)
+
+		stream, err := cc.NewStream(ctx, "rpc", testEncoding{})
+		assert.NoError(t, err)
+
+		// the error sending is not returned, and the receive retries.
+		a, b := "a", "b"
+		assert.NoError(t, stream.MsgSend(&a, testEncoding{}))
+		assert.NoError(t, stream.MsgSend(&b, testEncoding{}))
+		assert.NoError(t, stream.CloseSend())
+
+		var out string
+		assert.NoError(t, stream.MsgRecv(&out, testEncoding{}))
+		assert.Equal(t, "ab", out)
+		assert.Equal(t, 2, conn.streams)
+	})
+
+	t.Run("AfterReceive", func(t *testing.T) {
+		conn := &failingConn{}
+		cc, err := NewClientConnWithOptions(ctx, conn, WithRetryPolicy(fastRetries))
+		assert.NoError(t, err)
+
+		stream, err := cc.NewStream(ctx, "rpc", testEncoding{})
+		assert.NoError(t, err)
+
+		var out string
+		assert.NoError(t, stream.MsgRecv(&out, testEncoding{}))
+
+		// once a message is received, errors are returned.
+		conn.errs = []error{unavailable}
+		assert.Equal(t, unavailable, stream.MsgRecv(&out, testEncoding{}))
+		assert.Equal(t, 1, conn.streams)
+	})
+
+	t.Run("BufferLimit", func(t *testing.T) {
+		policy := fastRetries
+		policy.MaxBufferSize = 2
+
+		conn := &failingConn{errs: []error{unavailable}}
+		cc, err := NewClientConnWithOptions(ctx, conn, WithRetryPolicy(policy))
+		assert.NoError(t, err)
+
+		stream, err := cc.NewStream(ctx, "rpc", testEncoding{})
+		assert.NoError(t, err)
+
+		a, bc := "a", "bc"
+		assert.NoError(t, stream.MsgSend(&a, testEncoding{}))
+		assert.NoError(t, stream.MsgSend(&bc, testEncoding{}))
+
+		// the messages do not fit in the buffer, so errors are returned.
+		var out string
+		assert.Equal(t, unavailable, stream.MsgRecv(&out, testEncoding{}))
+		assert.Equal(t, 1, conn.streams)
+	})
+}
+
+var unavailable = drpcerr.New(drpcerr.Unavailable, "unavailable")
+
+// failingConn is a drpc.Conn whose rpcs fail with the queued errors before
+// echoing the sent messages. Streams fail sending with the queued send errors.
+type failingConn struct {
+	mockDrpcConn
+	errs     []error
+	sendErrs []error
+	invokes  int
+	streams  int
+}
+
+func (f *failingConn) next() error {
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func (f *failingConn) Invoke(ctx context.Context, rpc string, enc drpc.Encoding, in, out drpc.Message) error {
+	f.invokes++
+	if err := f.next(); err != nil {
+		return err
+	}
+	*out.(*string) = *in.(*string)
+	return nil
+}
+
+func (f *failingConn) NewStream(ctx context.Context, rpc string, enc drpc.Encoding) (drpc.Stream, error) {
+	f.streams++
+	return &echoStream{conn: f}, nil
+}
+
+// echoStream receives the concatenation of the sent messages. Once sending
+// fails, sending, closing and receiving fail with the error.
+type echoStream struct {
+	mockStream
+	conn *failingConn
+	sent string
+	err  error
+}
+
+func (e *echoStream) MsgSend(msg drpc.Message, enc drpc.Encoding) error {
+	if len(e.conn.sendErrs) > 0 {
+		e.err, e.conn.sendErrs = e.conn.sendErrs[0], e.conn.sendErrs[1:]
+	}
+	if e.err != nil {
+		return e.err
+	}
+	data, err := enc.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	e.sent += string(data)
+	return nil
+}
+
+func (e *echoStream) CloseSend() error { return e.err }
+
+func (e *echoStream) MsgRecv(msg drpc.Message, enc drpc.Encoding) error {
+	if e.err != nil {
+		return e.err
+	}
+	if err := e.conn.next(); err != nil {
+		return err
+	}
+	return enc.Unmarshal([]byte(e.sent), msg)
+}
@@ -0,0 +1,33 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcclient
+
+import (
+	"context"
+	"crypto/tls"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/drpc/drpcconn"
+	"storj.io/drpc/drpcerr"
+)
+
+// DialTLS dials the address on the named network, completes a TLS handshake
+// with the config, and returns a ClientConn for the connection configured with
+// the dial options. If the config does not have a ServerName, the host of the
+// address is used. Errors dialing are coded with drpcerr.Unavailable.
+func DialTLS(ctx context.Context, network, address string, config *tls.Config, opts ...DialOption) (*ClientConn, error) {
+	dopts := defaultDialOptions()
+	for _, opt := range opts {
+		opt(&dopts)
+	}
+
+	dialer := tls.Dialer{Config: config}
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, drpcerr.WithCode(errs.Wrap(err), drpcerr.Unavailable)
+	}
+
+	return NewClientConnWithOptions(ctx, drpcconn.NewWithOptions(conn, dopts.connOpts), opts...)
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpccompress
+
+import (
+	"sync"
+
+	"github.com/zeebo/errs"
+)
+
+// Error is the class of errors returned by this package.
+var Error = errs.Class("drpccompress")
+
+// Compressor compresses and decompresses message data.
+type Compressor interface {
+	// Name returns the name that the compressor is registered and advertised
+	// with.
+	Name() string
+
+	// Compress appends the compressed form of src to dst.
+	Compress(dst, src []byte) ([]byte, error)
+
+	// Decompress appends the decompressed form of src to dst. It returns an
+	// error if the decompressed data would be larger than max bytes.
+	Decompress(dst, src []byte, max int) ([]byte, error)
+}
+
+var registry = struct {
+	mu    sync.RWMutex
+	comps map[string]Compressor
+}{comps: make(map[string]Compressor)}
+
+// Register makes the compressor available by its name, replacing any
+// compressor previously registered with the same name.
+func Register(c Compressor) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.comps[c.Name()] = c
+}
+
+// Get returns the compressor registered with the name.
+func Get(name string) (Compressor, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	c, ok := registry.comps[name]
+	return c, ok
+}
+
+func init() { Register(Gzip) }
+
+// TooLarge returns the error for decompressed data that is larger than max
+// bytes. Compressors should return it from Decompress.
+func TooLarge(max int) error {
+	return Error.New("decompressed message larger than %d bytes", max)
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpccompress
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/zeebo/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	c, ok := Get("gzip")
+	assert.That(t, ok)
+	assert.Equal(t, c.Name(), "gzip")
+
+	_, ok = Get("unknown")
+	assert.That(t, !ok)
+}
+
+func TestGzip(t *testing.T) {
+	data := bytes.Repeat([]byte("hello world "), 1000)
+
+	comp, err := Gzip.Compress([]byte("prefix"), data)
+	assert.NoError(t, err)
+	assert.That(t, bytes.HasPrefix(comp, []byte("prefix")))
+	assert.That(t, len(comp) < len(data))
+
+	// compressors are reused from a pool.
+	for i := 0; i < 2; i++ {
+		dec, err := Gzip.Decompress([]byte("prefix"), comp[len("prefix"):], len(data))
+		assert.NoError(t, err)
+		assert.DeepEqual(t, dec, append([]byte("prefix"), data...))
+	}
+
+	_, err = Gzip.Decompress(nil, comp[len("prefix"):], len(data)-1)
+	assert.Error(t, err)
+
+	_, err = Gzip.Decompress(nil, []byte("garbage"), len(data))
+	assert.Error(t, err)
+}
@@ -0,0 +1,20 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package drpccompress is a registry of compressors for message data.
+//
+// A client stream with the Compression option set advertises the name of its
+// compressor in the invoke metadata. A server that has the compressor
+// registered confirms it in the response header, and both sides of the stream
+// then compress the messages they send that are larger than their
+// CompressionThreshold with it. Compressed messages are sent with a different
+// packet kind, so receiving them is transparent to the caller of MsgRecv.
+// Servers only compress the messages they send to clients that advertised a
+// compressor, and clients only compress once the server has confirmed it, so
+// remotes that do not support compression keep working.
+//
+// The gzip compressor is always registered. The zstd and snappy compressors
+// are registered by importing the drpczstd and drpcsnappy packages, which are
+// separate modules to keep their dependencies out of this one. Other
+// compressors can be added with Register.
+package drpccompress
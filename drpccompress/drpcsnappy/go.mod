@@ -0,0 +1,13 @@
+module storj.io/drpc/drpccompress/drpcsnappy
+
+go 1.19
+
+require (
+	github.com/klauspost/compress v1.11.7
+	github.com/zeebo/assert v1.3.0
+	storj.io/drpc v0.0.0-00010101000000-000000000000
+)
+
+require github.com/zeebo/errs v1.2.2 // indirect
+
+replace storj.io/drpc => ../..
@@ -0,0 +1,48 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package drpcsnappy registers a snappy compressor with drpccompress.
+//
+// It is a separate module so that the dependency on the snappy implementation
+// is only required by users of it. Importing the package registers the
+// compressor with the name "snappy".
+package drpcsnappy
+
+import (
+	"github.com/klauspost/compress/snappy"
+
+	"storj.io/drpc/drpccompress"
+)
+
+// Compressor is a drpccompress.Compressor that uses the snappy block format.
+var Compressor drpccompress.Compressor = compressor{}
+
+func init() { drpccompress.Register(Compressor) }
+
+type compressor struct{}
+
+func (compressor) Name() string { return "snappy" }
+
+func (compressor) Compress(dst, src []byte) ([]byte, error) {
+	n := snappy.MaxEncodedLen(len(src))
+	if n < 0 {
+		return nil, drpccompress.Error.New("message too large to compress")
+	}
+	buf := append(dst, make([]byte, n)...)
+	enc := snappy.Encode(buf[len(dst):], src)
+	return buf[:len(dst)+len(enc)], nil
+}
+
+func (compressor) Decompress(dst, src []byte, max int) ([]byte, error) {
+	n, err := snappy.DecodedLen(src)
+	if err != nil {
+		return nil, drpccompress.Error.Wrap(err)
+	} else if n > max {
+		return nil, drpccompress.TooLarge(max)
+	}
+	buf := append(dst, make([]byte, n)...)
+	if _, err := snappy.Decode(buf[len(dst):], src); err != nil {
+		return nil, drpccompress.Error.Wrap(err)
+	}
+	return buf, nil
+}
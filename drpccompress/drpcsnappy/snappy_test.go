@@ -0,0 +1,40 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcsnappy
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/zeebo/assert"
+
+	"storj.io/drpc/drpccompress"
+)
+
+func TestCompressor(t *testing.T) {
+	c, ok := drpccompress.Get("snappy")
+	assert.That(t, ok)
+	assert.Equal(t, c, Compressor)
+
+	data := bytes.Repeat([]byte("hello world "), 1000)
+
+	comp, err := c.Compress([]byte("prefix"), data)
+	assert.NoError(t, err)
+	assert.That(t, bytes.HasPrefix(comp, []byte("prefix")))
+	assert.That(t, len(comp) < len(data))
+
+	dec, err := c.Decompress(nil, comp[len("prefix"):], len(data))
+	assert.NoError(t, err)
+	assert.DeepEqual(t, dec, data)
+
+	dec, err = c.Decompress([]byte("prefix"), comp[len("prefix"):], len(data))
+	assert.NoError(t, err)
+	assert.DeepEqual(t, dec, append([]byte("prefix"), data...))
+
+	_, err = c.Decompress(nil, comp[len("prefix"):], len(data)-1)
+	assert.Error(t, err)
+
+	_, err = c.Decompress(nil, []byte("garbage"), len(data))
+	assert.Error(t, err)
+}
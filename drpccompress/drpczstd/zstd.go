@@ -0,0 +1,84 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package drpczstd registers a zstd compressor with drpccompress.
+//
+// It is a separate module so that the dependency on the zstd implementation
+// is only required by users of it. Importing the package registers the
+// compressor with the name "zstd".
+package drpczstd
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+
+	"storj.io/drpc/drpccompress"
+)
+
+// Compressor is a drpccompress.Compressor that uses zstd with the default
+// compression level.
+var Compressor drpccompress.Compressor = new(compressor)
+
+func init() { drpccompress.Register(Compressor) }
+
+type compressor struct {
+	once sync.Once
+	enc  *zstd.Encoder
+	err  error
+
+	decs sync.Map // map[int]*zstd.Decoder for every maximum size
+}
+
+func (c *compressor) Name() string { return "zstd" }
+
+func (c *compressor) Compress(dst, src []byte) ([]byte, error) {
+	c.once.Do(func() { c.enc, c.err = zstd.NewWriter(nil) })
+	if c.err != nil {
+		return nil, drpccompress.Error.Wrap(c.err)
+	}
+	return c.enc.EncodeAll(src, dst), nil
+}
+
+func (c *compressor) Decompress(dst, src []byte, max int) ([]byte, error) {
+	// the decoder limit includes any data already in dst, so decompress into
+	// a fresh buffer instead.
+	if len(dst) > 0 {
+		out, err := c.Decompress(nil, src, max)
+		if err != nil {
+			return nil, err
+		}
+		return append(dst, out...), nil
+	}
+
+	dec, err := c.decoder(max)
+	if err != nil {
+		return nil, drpccompress.Error.Wrap(err)
+	}
+
+	out, err := dec.DecodeAll(src, dst)
+	if errors.Is(err, zstd.ErrDecoderSizeExceeded) || len(out) > max {
+		return nil, drpccompress.TooLarge(max)
+	} else if err != nil {
+		return nil, drpccompress.Error.Wrap(err)
+	}
+	return out, nil
+}
+
+// decoder returns a decoder that decodes at most max bytes. Decoders are safe
+// to use concurrently, so one is shared for every maximum size.
+func (c *compressor) decoder(max int) (*zstd.Decoder, error) {
+	if dec, ok := c.decs.Load(max); ok {
+		return dec.(*zstd.Decoder), nil
+	}
+	dec, err := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(uint64(max)))
+	if err != nil {
+		return nil, err
+	}
+	if prev, loaded := c.decs.LoadOrStore(max, dec); loaded {
+		dec.Close()
+		return prev.(*zstd.Decoder), nil
+	}
+	return dec, nil
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpccompress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"sync"
+)
+
+// Gzip is a Compressor that uses gzip with the default compression level. It
+// is registered with the name "gzip".
+var Gzip Compressor = gzipCompressor{}
+
+type gzipCompressor struct{}
+
+var (
+	gzipWriters sync.Pool
+	gzipReaders sync.Pool
+)
+
+func (gzipCompressor) Name() string { return "gzip" }
+
+func (gzipCompressor) Compress(dst, src []byte) ([]byte, error) {
+	buf := bytes.NewBuffer(dst)
+
+	w, _ := gzipWriters.Get().(*gzip.Writer)
+	if w == nil {
+		w = gzip.NewWriter(buf)
+	} else {
+		w.Reset(buf)
+	}
+	defer gzipWriters.Put(w)
+
+	if _, err := w.Write(src); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (gzipCompressor) Decompress(dst, src []byte, max int) ([]byte, error) {
+	r, _ := gzipReaders.Get().(*gzip.Reader)
+	if r == nil {
+		var err error
+		r, err = gzip.NewReader(bytes.NewReader(src))
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+	} else if err := r.Reset(bytes.NewReader(src)); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer gzipReaders.Put(r)
+
+	// read one byte past the limit so that data that is too large is noticed.
+	buf := bytes.NewBuffer(dst)
+	n, err := buf.ReadFrom(io.LimitReader(r, int64(max)+1))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if n > int64(max) {
+		return nil, TooLarge(max)
+	}
+	return buf.Bytes(), nil
+}
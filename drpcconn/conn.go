@@ -6,10 +6,12 @@ package drpcconn
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/zeebo/errs"
 
 	"storj.io/drpc"
+	"storj.io/drpc/drpccompress"
 	"storj.io/drpc/drpcenc"
 	"storj.io/drpc/drpcmanager"
 	"storj.io/drpc/drpcmetadata"
@@ -35,6 +37,8 @@ type Conn struct {
 	man  *drpcmanager.Manager
 	mu   sync.Mutex
 	wbuf []byte
+	comp string
+	win  int
 
 	stats map[string]*drpcstats.Stats
 }
@@ -48,7 +52,9 @@ func New(tr drpc.Transport) *Conn { return NewWithOptions(tr, Options{}) }
 // The Options control details of how the conn operates.
 func NewWithOptions(tr drpc.Transport, opts Options) *Conn {
 	c := &Conn{
-		tr: tr,
+		tr:   tr,
+		comp: opts.Manager.Stream.Compression,
+		win:  opts.Manager.Stream.InitialWindowSize,
 	}
 
 	if opts.CollectStats {
@@ -101,25 +107,75 @@ func (c *Conn) Unblocked() <-chan struct{} { return c.man.Unblocked() }
 func (c *Conn) Close() (err error) { return c.man.Close() }
 
 // Invoke issues the rpc on the transport serializing in, waits for a response, and
-// deserializes it into out. Only one Invoke or Stream may be open at a time.
+// deserializes it into out. Only one Invoke or Stream may be open at a time unless
+// the manager is configured to multiplex streams. If the context was returned by
+// drpcmetadata.WithResponse, the metadata the server responds with is recorded.
 func (c *Conn) Invoke(ctx context.Context, rpc string, enc drpc.Encoding, in, out drpc.Message) (err error) {
-	var metadata []byte
-	if md, ok := drpcmetadata.Get(ctx); ok {
-		metadata, err = drpcmetadata.Encode(metadata, md)
-		if err != nil {
-			return err
-		}
-	}
-
 	stream, err := c.man.NewClientStream(ctx, rpc)
 	if err != nil {
 		return err
 	}
+
+	// the response metadata is recorded after the stream is closed so that
+	// getting the header does not block.
+	resp, record := drpcmetadata.GetResponse(ctx)
+	if record {
+		defer func() { resp.Header, resp.Trailer = stream.Header(), stream.Trailer() }()
+	}
 	defer func() { err = errs.Combine(err, stream.Close()) }()
 
+	metadata, err := c.invokeMetadata(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.sendInvoke(stream, enc, rpc, in, metadata); err != nil {
+		return err
+	}
+	if err := stream.MsgRecv(out, enc); err != nil {
+		return err
+	}
+	if record {
+		// the trailer is sent just before the remote ends the stream, so
+		// wait for the end before closing it.
+		_, _ = stream.RawRecv()
+	}
+	return nil
+}
+
+// invokeMetadata encodes the metadata attached to the context along with the
+// time remaining before the context's deadline, if it has one, the name of the
+// compressor the stream uses, if it is registered, and the flow control window
+// of the stream, if it has one. It is called once the stream is acquired so
+// that the time spent waiting for it is not part of the remaining time.
+func (c *Conn) invokeMetadata(ctx context.Context) (metadata []byte, err error) {
+	metadata, err = drpcmetadata.EncodeOutgoing(ctx, metadata)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if timeout := time.Until(deadline); timeout > 0 {
+			metadata = drpcmetadata.EncodeTimeout(metadata, timeout)
+		}
+	}
+	if c.comp != "" {
+		if _, ok := drpccompress.Get(c.comp); ok {
+			metadata = drpcmetadata.EncodeCompression(metadata, c.comp)
+		}
+	}
+	if c.win > 0 {
+		metadata = drpcmetadata.EncodeWindow(metadata, c.win)
+	}
+	return metadata, nil
+}
+
+// sendInvoke marshals in and sends the invoke sequence on the stream.
+func (c *Conn) sendInvoke(stream *drpcstream.Stream, enc drpc.Encoding, rpc string, in drpc.Message, metadata []byte) (err error) {
 	// we have to protect c.wbuf here even though the manager only allows one
 	// stream at a time because the stream may async close allowing another
-	// concurrent call to Invoke to proceed.
+	// concurrent call to Invoke to proceed. the writes copy the data, so the
+	// lock does not need to be held while waiting for the response, which
+	// allows multiplexed calls to proceed concurrently.
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -128,13 +184,10 @@ func (c *Conn) Invoke(ctx context.Context, rpc string, enc drpc.Encoding, in, ou
 		return err
 	}
 
-	if err := c.doInvoke(stream, enc, rpc, c.wbuf, metadata, out); err != nil {
-		return err
-	}
-	return nil
+	return c.doInvoke(stream, rpc, c.wbuf, metadata)
 }
 
-func (c *Conn) doInvoke(stream *drpcstream.Stream, enc drpc.Encoding, rpc string, data []byte, metadata []byte, out drpc.Message) (err error) {
+func (c *Conn) doInvoke(stream *drpcstream.Stream, rpc string, data []byte, metadata []byte) (err error) {
 	if len(metadata) > 0 {
 		if err := stream.RawWrite(drpcwire.KindInvokeMetadata, metadata); err != nil {
 			return err
@@ -149,28 +202,22 @@ func (c *Conn) doInvoke(stream *drpcstream.Stream, enc drpc.Encoding, rpc string
 	if err := stream.CloseSend(); err != nil {
 		return err
 	}
-	if err := stream.MsgRecv(out, enc); err != nil {
-		return err
-	}
 	return nil
 }
 
 // NewStream begins a streaming rpc on the connection. Only one Invoke or Stream may
-// be open at a time.
+// be open at a time unless the manager is configured to multiplex streams.
 func (c *Conn) NewStream(ctx context.Context, rpc string, enc drpc.Encoding) (_ drpc.Stream, err error) {
-	var metadata []byte
-	if md, ok := drpcmetadata.Get(ctx); ok {
-		metadata, err = drpcmetadata.Encode(metadata, md)
-		if err != nil {
-			return nil, err
-		}
-	}
-
 	stream, err := c.man.NewClientStream(ctx, rpc)
 	if err != nil {
 		return nil, err
 	}
 
+	metadata, err := c.invokeMetadata(ctx)
+	if err != nil {
+		return nil, errs.Combine(err, stream.Close())
+	}
+
 	if err := c.doNewStream(stream, rpc, metadata); err != nil {
 		return nil, errs.Combine(err, stream.Close())
 	}
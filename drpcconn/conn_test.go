@@ -6,12 +6,18 @@ package drpcconn
 import (
 	"context"
 	"net"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/zeebo/assert"
+	"github.com/zeebo/errs"
 
 	"storj.io/drpc"
+	"storj.io/drpc/drpcmanager"
+	"storj.io/drpc/drpcmetadata"
+	"storj.io/drpc/drpcstats"
+	"storj.io/drpc/drpcstream"
 	"storj.io/drpc/drpctest"
 	"storj.io/drpc/drpcwire"
 )
@@ -84,3 +90,269 @@ func TestConn_InvokeFlushesSendClose(t *testing.T) {
 		t.Fatal("took too long for conn to be closed")
 	}
 }
+
+func TestConn_InvokeSendsTimeout(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	pc, ps := net.Pipe()
+	defer func() { assert.NoError(t, pc.Close()) }()
+	defer func() { assert.NoError(t, ps.Close()) }()
+
+	timeouts := make(chan time.Duration, 1)
+
+	ctx.Run(func(ctx context.Context) {
+		rd := drpcwire.NewReader(ps)
+
+		pkt, _ := rd.ReadPacket() // InvokeMetadata
+		metadata, err := drpcmetadata.Decode(pkt.Data)
+		assert.NoError(t, err)
+
+		timeout, ok := drpcmetadata.DecodeTimeout(metadata[drpcmetadata.TimeoutKey])
+		assert.That(t, ok)
+		timeouts <- timeout
+
+		_ = ps.Close()
+	})
+
+	conn := New(pc)
+	defer func() { _ = conn.Close() }()
+
+	tctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	in, out := "baz", ""
+	assert.Error(t, conn.Invoke(tctx, "/com.example.Foo/Bar", testEncoding{}, &in, &out))
+
+	timeout := <-timeouts
+	assert.That(t, timeout > 0 && timeout <= time.Minute)
+}
+
+func TestConn_TimeoutAfterWaiting(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	pc, ps := net.Pipe()
+	defer func() { assert.NoError(t, pc.Close()) }()
+	defer func() { assert.NoError(t, ps.Close()) }()
+
+	timeouts := make(chan time.Duration, 1)
+
+	ctx.Run(func(ctx context.Context) {
+		rd := drpcwire.NewReader(ps)
+
+		for {
+			pkt, err := rd.ReadPacket()
+			if err != nil {
+				return
+			}
+			if pkt.Kind != drpcwire.KindInvokeMetadata {
+				continue
+			}
+
+			metadata, err := drpcmetadata.Decode(pkt.Data)
+			assert.NoError(t, err)
+
+			timeout, ok := drpcmetadata.DecodeTimeout(metadata[drpcmetadata.TimeoutKey])
+			assert.That(t, ok)
+			timeouts <- timeout
+
+			_ = ps.Close()
+			return
+		}
+	})
+
+	conn := New(pc)
+	defer func() { _ = conn.Close() }()
+
+	// the invoke waits for the open stream to be closed.
+	const wait = 100 * time.Millisecond
+	stream, err := conn.NewStream(ctx, "/com.example.Foo/Stream", testEncoding{})
+	assert.NoError(t, err)
+	ctx.Run(func(ctx context.Context) {
+		time.Sleep(wait)
+		_ = stream.Close()
+	})
+
+	tctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	in, out := "baz", ""
+	assert.Error(t, conn.Invoke(tctx, "/com.example.Foo/Bar", testEncoding{}, &in, &out))
+
+	// the time spent waiting is not part of the timeout.
+	timeout := <-timeouts
+	assert.That(t, timeout > 0 && timeout <= time.Minute-wait)
+}
+
+func TestConn_Compression(t *testing.T) {
+	run := func(t *testing.T, comp string) drpcstats.Stats {
+		ctx := drpctest.NewTracker(t)
+		defer ctx.Close()
+
+		pc, ps := net.Pipe()
+		defer func() { _ = pc.Close() }()
+		defer func() { _ = ps.Close() }()
+
+		msg := strings.Repeat("compressible", 1000)
+
+		man := drpcmanager.New(ps)
+		defer func() { _ = man.Close() }()
+
+		ctx.Run(func(ctx context.Context) {
+			stream, _, err := man.NewServerStream(ctx)
+			assert.NoError(t, err)
+
+			// the compressor is not visible to the handler.
+			_, ok := drpcmetadata.GetValue(stream.Context(), drpcmetadata.CompressionKey)
+			assert.That(t, !ok)
+
+			for i := 0; i < 2; i++ {
+				var in string
+				assert.NoError(t, stream.MsgRecv(&in, testEncoding{}))
+				assert.Equal(t, in, msg)
+				assert.NoError(t, stream.MsgSend(&in, testEncoding{}))
+			}
+			_ = stream.CloseSend()
+		})
+
+		conn := NewWithOptions(pc, Options{
+			Manager:      drpcmanager.Options{Stream: drpcstream.Options{Compression: comp}},
+			CollectStats: true,
+		})
+		defer func() { _ = conn.Close() }()
+
+		stream, err := conn.NewStream(ctx, "rpc", testEncoding{})
+		assert.NoError(t, err)
+		for i := 0; i < 2; i++ {
+			in, out := msg, ""
+			assert.NoError(t, stream.MsgSend(&in, testEncoding{}))
+			assert.NoError(t, stream.MsgRecv(&out, testEncoding{}))
+			assert.Equal(t, out, msg)
+		}
+		assert.NoError(t, stream.CloseSend())
+
+		return conn.Stats()["rpc"]
+	}
+
+	// with compression the responses are compressed, but the first request is
+	// not because the server has not yet confirmed the compressor.
+	stats := run(t, "gzip")
+	assert.That(t, stats.Written > 10000 && stats.Written < 20000)
+	assert.That(t, stats.Read < 2000)
+
+	// without it, or with an unknown compressor, nothing is.
+	for _, comp := range []string{"", "unknown"} {
+		stats = run(t, comp)
+		assert.That(t, stats.Written > 20000 && stats.Read > 20000)
+	}
+}
+
+func TestConn_FlowControl(t *testing.T) {
+	run := func(t *testing.T, window int) {
+		ctx := drpctest.NewTracker(t)
+		defer ctx.Close()
+
+		pc, ps := net.Pipe()
+		defer func() { _ = pc.Close() }()
+		defer func() { _ = ps.Close() }()
+
+		man := drpcmanager.NewWithOptions(ps, drpcmanager.Options{
+			Stream: drpcstream.Options{InitialWindowSize: window},
+		})
+		defer func() { _ = man.Close() }()
+
+		ctx.Run(func(ctx context.Context) {
+			stream, _, err := man.NewServerStream(ctx)
+			assert.NoError(t, err)
+
+			// the window is not visible to the handler.
+			_, ok := drpcmetadata.GetValue(stream.Context(), drpcmetadata.WindowKey)
+			assert.That(t, !ok)
+
+			var in string
+			for i := 0; i < 10; i++ {
+				assert.NoError(t, stream.MsgRecv(&in, testEncoding{}))
+			}
+			assert.NoError(t, stream.MsgSend(&in, testEncoding{}))
+			_ = stream.CloseSend()
+		})
+
+		conn := NewWithOptions(pc, Options{
+			Manager: drpcmanager.Options{Stream: drpcstream.Options{InitialWindowSize: 100}},
+		})
+		defer func() { _ = conn.Close() }()
+
+		stream, err := conn.NewStream(ctx, "rpc", testEncoding{})
+		assert.NoError(t, err)
+
+		msg := strings.Repeat("x", 50)
+		for i := 0; i < 10; i++ {
+			assert.NoError(t, stream.MsgSend(&msg, testEncoding{}))
+		}
+		assert.NoError(t, stream.CloseSend())
+
+		var out string
+		assert.NoError(t, stream.MsgRecv(&out, testEncoding{}))
+		assert.Equal(t, out, msg)
+	}
+
+	// a client can send more than its window to servers with or without flow
+	// control.
+	t.Run("Server", func(t *testing.T) { run(t, 100) })
+	t.Run("NoFlowControl", func(t *testing.T) { run(t, 0) })
+}
+
+func TestConn_ResponseMetadata(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	pc, ps := net.Pipe()
+	defer func() { _ = pc.Close() }()
+	defer func() { _ = ps.Close() }()
+
+	man := drpcmanager.New(ps)
+	defer func() { _ = man.Close() }()
+
+	ctx.Run(func(ctx context.Context) {
+		for i := 0; i < 2; i++ {
+			stream, _, err := man.NewServerStream(ctx)
+			assert.NoError(t, err)
+
+			var in string
+			assert.NoError(t, stream.MsgRecv(&in, testEncoding{}))
+			sctx := stream.Context()
+			assert.NoError(t, drpcmetadata.SetHeader(sctx, map[string]string{"version": "1"}))
+			assert.NoError(t, drpcmetadata.SetTrailer(sctx, map[string]string{"call": in}))
+
+			if in == "fail" {
+				_ = stream.SendError(errs.New("failed"))
+			} else {
+				assert.NoError(t, stream.MsgSend(&in, testEncoding{}))
+				_ = stream.CloseSend()
+			}
+			<-stream.Finished()
+		}
+	})
+
+	conn := New(pc)
+	defer func() { _ = conn.Close() }()
+
+	var resp drpcmetadata.Response
+	in, out := "ok", ""
+	assert.NoError(t, conn.Invoke(drpcmetadata.WithResponse(ctx, &resp), "rpc", testEncoding{}, &in, &out))
+	assert.Equal(t, out, "ok")
+	assert.DeepEqual(t, resp, drpcmetadata.Response{
+		Header:  map[string]string{"version": "1"},
+		Trailer: map[string]string{"call": "ok"},
+	})
+
+	// the metadata is recorded even if the rpc fails.
+	resp = drpcmetadata.Response{}
+	in = "fail"
+	assert.Error(t, conn.Invoke(drpcmetadata.WithResponse(ctx, &resp), "rpc", testEncoding{}, &in, &out))
+	assert.DeepEqual(t, resp, drpcmetadata.Response{
+		Header:  map[string]string{"version": "1"},
+		Trailer: map[string]string{"call": "fail"},
+	})
+}
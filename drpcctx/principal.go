@@ -0,0 +1,31 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcctx
+
+import "context"
+
+// Principal is the identity of an authenticated caller.
+type Principal struct {
+	// Name identifies the caller, like a user name or key id.
+	Name string
+
+	// Claims holds any other attributes of the caller provided by whatever
+	// authenticated it.
+	Claims map[string]string
+}
+
+// principalKey is used to store the Principal in the context.
+type principalKey struct{}
+
+// WithPrincipal associates the authenticated principal with the context.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// GetPrincipal returns the authenticated principal associated with the context
+// and a bool indicating if it existed.
+func GetPrincipal(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
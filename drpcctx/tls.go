@@ -0,0 +1,25 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcctx
+
+import (
+	"context"
+	"crypto/tls"
+)
+
+// tlsStateKey is used to store the TLS connection state in the context.
+type tlsStateKey struct{}
+
+// WithTLSConnectionState associates the state of the TLS connection, including
+// the negotiated protocol, server name and cipher suite, with the context.
+func WithTLSConnectionState(ctx context.Context, state tls.ConnectionState) context.Context {
+	return context.WithValue(ctx, tlsStateKey{}, state)
+}
+
+// TLSConnectionState returns the state of the TLS connection associated with
+// the context and a bool indicating if it existed.
+func TLSConnectionState(ctx context.Context) (tls.ConnectionState, bool) {
+	state, ok := ctx.Value(tlsStateKey{}).(tls.ConnectionState)
+	return state, ok
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcerr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// These codes are the canonical error codes. They have the same values and
+// meanings as the gRPC status codes.
+const (
+	// Canceled is the code used when the operation was canceled, typically
+	// by the caller.
+	Canceled = 1
+
+	// Unknown is the code used when an error has no better code.
+	Unknown = 2
+
+	// InvalidArgument is the code used when the caller specified an invalid
+	// argument.
+	InvalidArgument = 3
+
+	// DeadlineExceeded is the code used when the deadline expired before the
+	// operation could complete.
+	DeadlineExceeded = 4
+
+	// NotFound is the code used when some requested entity was not found.
+	NotFound = 5
+
+	// AlreadyExists is the code used when an entity that the caller attempted
+	// to create already exists.
+	AlreadyExists = 6
+
+	// PermissionDenied is the code used when the caller does not have
+	// permission to execute the operation.
+	PermissionDenied = 7
+
+	// ResourceExhausted is the code used when some resource has been
+	// exhausted, for example when a server rejects work because a limit has
+	// been reached.
+	ResourceExhausted = 8
+
+	// FailedPrecondition is the code used when the system is not in a state
+	// required for the operation's execution.
+	FailedPrecondition = 9
+
+	// Aborted is the code used when the operation was aborted, typically due
+	// to a concurrency issue.
+	Aborted = 10
+
+	// OutOfRange is the code used when the operation was attempted past the
+	// valid range.
+	OutOfRange = 11
+
+	// Unimplemented is the code used by the generated unimplemented
+	// servers when returning errors.
+	Unimplemented = 12
+
+	// Internal is the code used for internal errors.
+	Internal = 13
+
+	// Unavailable is the code used when the service is currently unavailable,
+	// for example because the transport was closed. It is most likely a
+	// transient condition that can be corrected by retrying.
+	Unavailable = 14
+
+	// DataLoss is the code used for unrecoverable data loss or corruption.
+	DataLoss = 15
+
+	// Unauthenticated is the code used when the request does not have valid
+	// authentication credentials.
+	Unauthenticated = 16
+)
+
+// CircuitOpen is the code used by client circuit breakers when an rpc fails
+// without being sent because too many recent rpcs failed. It is not one of the
+// canonical codes.
+const CircuitOpen = 100
+
+var codeNames = [...]string{
+	Canceled:           "Canceled",
+	Unknown:            "Unknown",
+	InvalidArgument:    "InvalidArgument",
+	DeadlineExceeded:   "DeadlineExceeded",
+	NotFound:           "NotFound",
+	AlreadyExists:      "AlreadyExists",
+	PermissionDenied:   "PermissionDenied",
+	ResourceExhausted:  "ResourceExhausted",
+	FailedPrecondition: "FailedPrecondition",
+	Aborted:            "Aborted",
+	OutOfRange:         "OutOfRange",
+	Unimplemented:      "Unimplemented",
+	Internal:           "Internal",
+	Unavailable:        "Unavailable",
+	DataLoss:           "DataLoss",
+	Unauthenticated:    "Unauthenticated",
+}
+
+// CodeName returns the name of the code, like "NotFound", or a string
+// containing the number for any unknown code.
+func CodeName(code uint64) string {
+	if code == 0 {
+		return "OK"
+	} else if code < uint64(len(codeNames)) {
+		return codeNames[code]
+	} else if code == CircuitOpen {
+		return "CircuitOpen"
+	}
+	return fmt.Sprintf("Code(%d)", code)
+}
+
+// New returns an error with the message associated with the code.
+func New(code uint64, msg string) error {
+	return WithCode(errors.New(msg), code)
+}
+
+// Newf returns an error with the formatted message associated with the code.
+func Newf(code uint64, format string, args ...interface{}) error {
+	return WithCode(fmt.Errorf(format, args...), code)
+}
+
+// Is returns true if the code is associated with the error.
+func Is(err error, code uint64) bool {
+	return err != nil && Code(err) == code
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/zeebo/assert"
+	"github.com/zeebo/errs"
+)
+
+func TestCodes(t *testing.T) {
+	err := New(NotFound, "missing")
+	assert.Equal(t, err.Error(), "missing")
+	assert.Equal(t, Code(err), NotFound)
+	assert.That(t, Is(errs.Wrap(err), NotFound))
+	assert.That(t, !Is(err, Internal))
+	assert.That(t, !Is(nil, 0))
+
+	err = Newf(InvalidArgument, "bad %d", 5)
+	assert.Equal(t, err.Error(), "bad 5")
+	assert.Equal(t, Code(err), InvalidArgument)
+
+	// context errors have codes without being wrapped
+	assert.Equal(t, Code(context.Canceled), Canceled)
+	assert.Equal(t, Code(fmt.Errorf("wrap: %w", context.DeadlineExceeded)), DeadlineExceeded)
+
+	// an explicit code wins over the context code
+	assert.Equal(t, Code(WithCode(context.Canceled, Aborted)), Aborted)
+
+	assert.Equal(t, CodeName(0), "OK")
+	assert.Equal(t, CodeName(Unavailable), "Unavailable")
+	assert.Equal(t, CodeName(CircuitOpen), "CircuitOpen")
+	assert.Equal(t, CodeName(200), "Code(200)")
+}
+
+func TestTwirpCodes(t *testing.T) {
+	for code := uint64(Canceled); code <= Unauthenticated; code++ {
+		assert.Equal(t, FromTwirpCode(TwirpCode(code)), code)
+	}
+
+	assert.Equal(t, TwirpCode(0), "unknown")
+	assert.Equal(t, TwirpCode(100), "unknown")
+	assert.Equal(t, FromTwirpCode("malformed"), InvalidArgument)
+	assert.Equal(t, FromTwirpCode("bad_route"), Unimplemented)
+	assert.Equal(t, FromTwirpCode("garbage"), Unknown)
+}
+
+func TestGRPCStatus(t *testing.T) {
+	code, msg := ToGRPCStatus(nil)
+	assert.Equal(t, code, 0)
+	assert.Equal(t, msg, "")
+
+	code, msg = ToGRPCStatus(errors.New("plain"))
+	assert.Equal(t, code, Unknown)
+	assert.Equal(t, msg, "plain")
+
+	code, _ = ToGRPCStatus(errs.Wrap(New(PermissionDenied, "nope")))
+	assert.Equal(t, code, PermissionDenied)
+
+	// errors without a status are unchanged
+	plain := errors.New("plain")
+	assert.Equal(t, FromGRPCStatus(plain), plain)
+
+	// statuses are found through wrapping and include the details
+	err := FromGRPCStatus(errs.Wrap(&fakeStatusError{st: &fakeStatus{
+		code: NotFound,
+		msg:  "missing",
+		proto: &fakeStatusProto{Details: []*fakeAny{
+			{TypeUrl: "type.googleapis.com/pkg.Message", Value: []byte("value")},
+		}},
+	}}))
+	assert.Equal(t, err.Error(), "missing")
+	assert.Equal(t, Code(err), NotFound)
+	assert.DeepEqual(t, Details(err), []Detail{{Type: "pkg.Message", Value: []byte("value")}})
+
+	// an OK status is no error
+	assert.Nil(t, FromGRPCStatus(&fakeStatusError{st: &fakeStatus{}}))
+}
+
+//
+// fakes matching the shape of the grpc status types
+//
+
+type fakeStatusError struct{ st *fakeStatus }
+
+func (e *fakeStatusError) Error() string           { return e.st.msg }
+func (e *fakeStatusError) GRPCStatus() *fakeStatus { return e.st }
+
+type fakeStatus struct {
+	code  uint32
+	msg   string
+	proto *fakeStatusProto
+}
+
+func (s *fakeStatus) Code() fakeCode          { return fakeCode(s.code) }
+func (s *fakeStatus) Message() string         { return s.msg }
+func (s *fakeStatus) Proto() *fakeStatusProto { return s.proto }
+
+type fakeCode uint32
+
+type fakeStatusProto struct{ Details []*fakeAny }
+
+type fakeAny struct {
+	TypeUrl string
+	Value   []byte
+}
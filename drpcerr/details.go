@@ -0,0 +1,76 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcerr
+
+import "storj.io/drpc"
+
+// Detail is a typed, encoded message that carries machine-readable information
+// about an error, like the details of a google.rpc.Status.
+type Detail struct {
+	// Type identifies the type of the encoded message, for example the full
+	// name of a protobuf message.
+	Type string
+
+	// Value is the encoded message.
+	Value []byte
+}
+
+// NewDetail encodes the message with the encoding into a Detail with the type.
+func NewDetail(typ string, msg drpc.Message, enc drpc.Encoding) (Detail, error) {
+	value, err := enc.Marshal(msg)
+	if err != nil {
+		return Detail{}, err
+	}
+	return Detail{Type: typ, Value: value}, nil
+}
+
+// Decode decodes the value of the detail into the message with the encoding.
+func (d Detail) Decode(msg drpc.Message, enc drpc.Encoding) error {
+	return enc.Unmarshal(d.Value, msg)
+}
+
+// Details returns the details associated with the error or nil if none are.
+func Details(err error) []Detail {
+	for i := 0; i < 100; i++ {
+		prev := err
+		switch v := err.(type) { //nolint: errorlint // this is a custom unwrap loop
+		case interface{ Details() []Detail }:
+			return v.Details()
+		case interface{ Cause() error }:
+			err = v.Cause()
+		case interface{ Unwrap() error }:
+			err = v.Unwrap()
+		default:
+			return nil
+		}
+		// short-circuit any trivial cycles
+		if shallowEqual(err, prev) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// WithDetails associates the details with the error, in addition to any it
+// already has, if it is non nil and there are any details.
+func WithDetails(err error, details ...Detail) error {
+	if err == nil || len(details) == 0 {
+		return err
+	}
+	existing := Details(err)
+	all := make([]Detail, 0, len(existing)+len(details))
+	all = append(all, existing...)
+	all = append(all, details...)
+	return &detailsErr{err: err, details: all}
+}
+
+type detailsErr struct {
+	err     error
+	details []Detail
+}
+
+func (d *detailsErr) Error() string     { return d.err.Error() }
+func (d *detailsErr) Unwrap() error     { return d.err }
+func (d *detailsErr) Cause() error      { return d.err }
+func (d *detailsErr) Details() []Detail { return d.details }
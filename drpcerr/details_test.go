@@ -0,0 +1,60 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcerr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zeebo/assert"
+	"github.com/zeebo/errs"
+
+	"storj.io/drpc"
+)
+
+type stringEncoding struct{}
+
+func (stringEncoding) Marshal(msg drpc.Message) ([]byte, error) {
+	return []byte(*msg.(*string)), nil
+}
+
+func (stringEncoding) Unmarshal(buf []byte, msg drpc.Message) error {
+	*msg.(*string) = string(buf)
+	return nil
+}
+
+func TestDetails(t *testing.T) {
+	// no error should still be nil
+	assert.Nil(t, WithDetails(nil, Detail{Type: "a"}))
+
+	// no details should leave the error alone
+	err := errors.New("test")
+	assert.Equal(t, WithDetails(err), err)
+	assert.Nil(t, Details(err))
+
+	a, b := Detail{Type: "a"}, Detail{Type: "b"}
+
+	// details should be found through wrapping and codes
+	err = errs.Wrap(WithCode(WithDetails(err, a), 5))
+	assert.DeepEqual(t, Details(err), []Detail{a})
+	assert.Equal(t, Code(err), 5)
+	assert.Equal(t, err.Error(), "test")
+
+	// attaching more details keeps the existing ones
+	assert.DeepEqual(t, Details(WithDetails(err, b)), []Detail{a, b})
+
+	// cycles should be handled ok
+	assert.Nil(t, Details(cycle{}))
+}
+
+func TestDetailEncoding(t *testing.T) {
+	in := "value"
+	detail, err := NewDetail("string", &in, stringEncoding{})
+	assert.NoError(t, err)
+	assert.Equal(t, detail.Type, "string")
+
+	var out string
+	assert.NoError(t, detail.Decode(&out, stringEncoding{}))
+	assert.Equal(t, out, in)
+}
@@ -3,15 +3,14 @@
 
 package drpcerr
 
-import "unsafe"
-
-const (
-	// Unimplemented is the code used by the generated unimplemented
-	// servers when returning errors.
-	Unimplemented = 12
+import (
+	"context"
+	"unsafe"
 )
 
-// Code returns the error code associated with the error or 0 if none is.
+// Code returns the error code associated with the error or 0 if none is. The
+// context.Canceled and context.DeadlineExceeded errors are associated with the
+// Canceled and DeadlineExceeded codes.
 func Code(err error) uint64 {
 	for i := 0; i < 100; i++ {
 		prev := err
@@ -23,7 +22,7 @@ func Code(err error) uint64 {
 		case interface{ Unwrap() error }:
 			err = v.Unwrap()
 		default:
-			return 0
+			return contextCode(err)
 		}
 		// short-circuit any trivial cycles
 		if shallowEqual(err, prev) {
@@ -33,6 +32,18 @@ func Code(err error) uint64 {
 	return 0
 }
 
+// contextCode returns the code for the error if it is a context error.
+func contextCode(err error) uint64 {
+	switch err { //nolint: errorlint // the caller unwraps
+	case context.Canceled:
+		return Canceled
+	case context.DeadlineExceeded:
+		return DeadlineExceeded
+	default:
+		return 0
+	}
+}
+
 // shallowEqual returns true if the two errors are equal without comparing
 // their values. It may return false even if the errors are equal, but if
 // returns true, then the errors are equal.
@@ -0,0 +1,110 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcerr
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// ToGRPCStatus returns the gRPC status code and message for the error. They
+// can be passed to status.New from google.golang.org/grpc/status after
+// converting the code to a codes.Code. A nil error has the OK code, and an
+// error without a code has the Unknown code.
+func ToGRPCStatus(err error) (code uint32, msg string) {
+	if err == nil {
+		return 0, ""
+	}
+	code = uint32(Code(err))
+	if code == 0 {
+		code = Unknown
+	}
+	return code, err.Error()
+}
+
+// FromGRPCStatus returns an error with the code, message and details of the
+// gRPC status carried by the error, like the errors created by the
+// google.golang.org/grpc/status package. The type of each detail is the full
+// name of the message in its type url. It uses reflect to find the status
+// without having to import and depend on the gRPC module. If the error does
+// not carry a status, it is returned unchanged.
+func FromGRPCStatus(err error) error {
+	for i, cur := 0, err; i < 100 && cur != nil; i++ {
+		if out, ok := fromGRPCStatus(cur); ok {
+			return out
+		}
+		switch v := cur.(type) { //nolint: errorlint // this is a custom unwrap loop
+		case interface{ Cause() error }:
+			cur = v.Cause()
+		case interface{ Unwrap() error }:
+			cur = v.Unwrap()
+		default:
+			return err
+		}
+	}
+	return err
+}
+
+// fromGRPCStatus converts the error if it has a GRPCStatus method.
+func fromGRPCStatus(err error) (error, bool) {
+	st, ok := call(reflect.ValueOf(err), "GRPCStatus", reflect.Ptr)
+	if !ok || st.IsNil() {
+		return nil, false
+	}
+	code, ok := call(st, "Code", reflect.Uint32)
+	if !ok {
+		return nil, false
+	}
+	msg, ok := call(st, "Message", reflect.String)
+	if !ok {
+		return nil, false
+	}
+	if code.Uint() == 0 {
+		return nil, true
+	}
+
+	out := WithCode(errors.New(msg.String()), code.Uint())
+
+	// the details are in the Details field of the status protobuf message as
+	// a slice of pointers to anypb.Any messages.
+	if proto, ok := call(st, "Proto", reflect.Ptr); ok && !proto.IsNil() {
+		if anys := proto.Elem().FieldByName("Details"); anys.Kind() == reflect.Slice {
+			details := make([]Detail, 0, anys.Len())
+			for i := 0; i < anys.Len(); i++ {
+				elem := reflect.Indirect(anys.Index(i))
+				if elem.Kind() != reflect.Struct {
+					continue
+				}
+				url, value := elem.FieldByName("TypeUrl"), elem.FieldByName("Value")
+				if url.Kind() != reflect.String || value.Kind() != reflect.Slice {
+					continue
+				}
+				details = append(details, Detail{
+					Type:  url.String()[strings.LastIndexByte(url.String(), '/')+1:],
+					Value: value.Bytes(),
+				})
+			}
+			out = WithDetails(out, details...)
+		}
+	}
+
+	return out, true
+}
+
+// call calls the method with no arguments and a single result of the kind
+// on the value, returning false if it does not exist.
+func call(v reflect.Value, name string, kind reflect.Kind) (reflect.Value, bool) {
+	if !v.IsValid() {
+		return reflect.Value{}, false
+	}
+	m := v.MethodByName(name)
+	if !m.IsValid() {
+		return reflect.Value{}, false
+	}
+	if mt := m.Type(); mt.NumIn() != 0 || mt.NumOut() != 1 || mt.Out(0).Kind() != kind {
+		return reflect.Value{}, false
+	}
+	return m.Call(nil)[0], true
+}
@@ -0,0 +1,49 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcerr
+
+var twirpCodes = [...]string{
+	Canceled:           "canceled",
+	Unknown:            "unknown",
+	InvalidArgument:    "invalid_argument",
+	DeadlineExceeded:   "deadline_exceeded",
+	NotFound:           "not_found",
+	AlreadyExists:      "already_exists",
+	PermissionDenied:   "permission_denied",
+	ResourceExhausted:  "resource_exhausted",
+	FailedPrecondition: "failed_precondition",
+	Aborted:            "aborted",
+	OutOfRange:         "out_of_range",
+	Unimplemented:      "unimplemented",
+	Internal:           "internal",
+	Unavailable:        "unavailable",
+	DataLoss:           "dataloss",
+	Unauthenticated:    "unauthenticated",
+}
+
+// TwirpCode returns the Twirp error code string for the code. It returns
+// "unknown" for codes that have no Twirp equivalent.
+func TwirpCode(code uint64) string {
+	if code > 0 && code < uint64(len(twirpCodes)) {
+		return twirpCodes[code]
+	}
+	return "unknown"
+}
+
+// FromTwirpCode returns the code for the Twirp error code string. It returns
+// Unknown for strings that are not Twirp error codes.
+func FromTwirpCode(code string) uint64 {
+	switch code {
+	case "malformed":
+		return InvalidArgument
+	case "bad_route":
+		return Unimplemented
+	}
+	for c, name := range twirpCodes {
+		if c > 0 && name == code {
+			return uint64(c)
+		}
+	}
+	return Unknown
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpchealth
+
+import (
+	"context"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcpool"
+)
+
+// Check asks the server on the conn for the serving status of the service. It
+// returns nil if the service is serving, and otherwise an error with the
+// Unavailable code, or with the code of the error the check failed with if it
+// has one.
+func Check(ctx context.Context, cc drpc.Conn, service string) error {
+	resp, err := NewDRPCHealthClient(cc).Check(ctx, &HealthCheckRequest{Service: service})
+	if err != nil {
+		if drpcerr.Code(err) == 0 {
+			err = drpcerr.WithCode(errs.Wrap(err), drpcerr.Unavailable)
+		}
+		return err
+	}
+	if status := resp.GetStatus(); status != HealthCheckResponse_SERVING {
+		return drpcerr.Newf(drpcerr.Unavailable, "service %q is %v", service, status)
+	}
+	return nil
+}
+
+// Checker returns a function that calls Check for the service, like the
+// HealthCheck option of a drpcbalancer.Conn expects.
+func Checker(service string) func(ctx context.Context, cc drpc.Conn) error {
+	return func(ctx context.Context, cc drpc.Conn) error {
+		return Check(ctx, cc, service)
+	}
+}
+
+// CheckPool checks the service on the connection cached by the pool for the
+// key, if there is one. Connections that are not serving are closed instead of
+// returned to the pool, so that the next rpc dials a new one.
+func CheckPool[K comparable, V drpcpool.Conn](ctx context.Context, pool *drpcpool.Pool[K, V], key K, service string) error {
+	conn, ok := pool.Take(key)
+	if !ok {
+		return nil
+	}
+	if err := Check(ctx, conn, service); err != nil {
+		_ = conn.Close()
+		return err
+	}
+	pool.Put(key, conn)
+	return nil
+}
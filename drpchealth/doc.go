@@ -0,0 +1,22 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package drpchealth is a health checking service compatible with
+// grpc.health.v1, so that load balancers and orchestrators can probe drpc
+// servers.
+//
+// A Server keeps the serving status of every service and implements the Check
+// and Watch rpcs. Register it on a mux with DRPCRegisterHealth. The status of
+// the empty service is the status of the server as a whole.
+//
+// On the client side, Check returns an error with the Unavailable code when a
+// backend is not serving, and can be used as the HealthCheck of a
+// drpcbalancer.Conn through Checker, or with CheckPool to evict unhealthy
+// connections from a drpcpool.Pool.
+//
+// The messages are written by hand instead of by protoc-gen-go so that they do
+// not conflict in the global protobuf registry with the ones generated for
+// gRPC, which have the same names.
+package drpchealth
+
+//go:generate protoc --go-drpc_out=paths=source_relative,protolib=storj.io/drpc/drpchealth/internal/codec,grpc=false,errors=errors,descriptors=true:. health.proto
@@ -0,0 +1,159 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpchealth
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/zeebo/errs"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// HealthCheckResponse_ServingStatus is the serving status of a service.
+type HealthCheckResponse_ServingStatus int32
+
+// The serving statuses of a service.
+const (
+	HealthCheckResponse_UNKNOWN         HealthCheckResponse_ServingStatus = 0
+	HealthCheckResponse_SERVING         HealthCheckResponse_ServingStatus = 1
+	HealthCheckResponse_NOT_SERVING     HealthCheckResponse_ServingStatus = 2
+	HealthCheckResponse_SERVICE_UNKNOWN HealthCheckResponse_ServingStatus = 3 // used only by Watch
+)
+
+var servingStatusNames = map[HealthCheckResponse_ServingStatus]string{
+	HealthCheckResponse_UNKNOWN:         "UNKNOWN",
+	HealthCheckResponse_SERVING:         "SERVING",
+	HealthCheckResponse_NOT_SERVING:     "NOT_SERVING",
+	HealthCheckResponse_SERVICE_UNKNOWN: "SERVICE_UNKNOWN",
+}
+
+// String returns the name of the status, like "SERVING".
+func (x HealthCheckResponse_ServingStatus) String() string {
+	if name, ok := servingStatusNames[x]; ok {
+		return name
+	}
+	return strconv.Itoa(int(x))
+}
+
+// MarshalJSON returns the name of the status as a json string.
+func (x HealthCheckResponse_ServingStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(x.String())
+}
+
+// UnmarshalJSON reads the status from its name or number.
+func (x *HealthCheckResponse_ServingStatus) UnmarshalJSON(buf []byte) error {
+	var num int32
+	if err := json.Unmarshal(buf, &num); err == nil {
+		*x = HealthCheckResponse_ServingStatus(num)
+		return nil
+	}
+	var name string
+	if err := json.Unmarshal(buf, &name); err != nil {
+		return errs.Wrap(err)
+	}
+	for status, sname := range servingStatusNames {
+		if sname == name {
+			*x = status
+			return nil
+		}
+	}
+	return errs.New("unknown serving status: %q", name)
+}
+
+// HealthCheckRequest is the request for the health of a service. The empty
+// service is the health of the server as a whole.
+type HealthCheckRequest struct {
+	Service string `json:"service,omitempty"`
+}
+
+// GetService returns the Service field or the empty string if x is nil.
+func (x *HealthCheckRequest) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+// Marshal returns the protobuf encoded form of the request.
+func (x *HealthCheckRequest) Marshal() ([]byte, error) {
+	var buf []byte
+	if x.Service != "" {
+		buf = protowire.AppendTag(buf, 1, protowire.BytesType)
+		buf = protowire.AppendString(buf, x.Service)
+	}
+	return buf, nil
+}
+
+// Unmarshal reads the protobuf encoded form of the request.
+func (x *HealthCheckRequest) Unmarshal(buf []byte) error {
+	*x = HealthCheckRequest{}
+	return unmarshalFields(buf, func(num protowire.Number, typ protowire.Type, buf []byte) int {
+		if num != 1 || typ != protowire.BytesType {
+			return 0
+		}
+		v, n := protowire.ConsumeString(buf)
+		x.Service = v
+		return n
+	})
+}
+
+// HealthCheckResponse is the health of a service.
+type HealthCheckResponse struct {
+	Status HealthCheckResponse_ServingStatus `json:"status,omitempty"`
+}
+
+// GetStatus returns the Status field or UNKNOWN if x is nil.
+func (x *HealthCheckResponse) GetStatus() HealthCheckResponse_ServingStatus {
+	if x != nil {
+		return x.Status
+	}
+	return HealthCheckResponse_UNKNOWN
+}
+
+// Marshal returns the protobuf encoded form of the response.
+func (x *HealthCheckResponse) Marshal() ([]byte, error) {
+	var buf []byte
+	if x.Status != 0 {
+		buf = protowire.AppendTag(buf, 1, protowire.VarintType)
+		buf = protowire.AppendVarint(buf, uint64(x.Status))
+	}
+	return buf, nil
+}
+
+// Unmarshal reads the protobuf encoded form of the response.
+func (x *HealthCheckResponse) Unmarshal(buf []byte) error {
+	*x = HealthCheckResponse{}
+	return unmarshalFields(buf, func(num protowire.Number, typ protowire.Type, buf []byte) int {
+		if num != 1 || typ != protowire.VarintType {
+			return 0
+		}
+		v, n := protowire.ConsumeVarint(buf)
+		x.Status = HealthCheckResponse_ServingStatus(v)
+		return n
+	})
+}
+
+// unmarshalFields calls field with every field in the encoded message and the
+// data after its tag. The field function returns how much of the data it
+// consumed, or zero if the field is unknown and should be skipped.
+func unmarshalFields(buf []byte, field func(num protowire.Number, typ protowire.Type, buf []byte) int) error {
+	for len(buf) > 0 {
+		num, typ, n := protowire.ConsumeTag(buf)
+		if n < 0 {
+			return errs.Wrap(protowire.ParseError(n))
+		}
+		buf = buf[n:]
+
+		n = field(num, typ, buf)
+		if n == 0 {
+			n = protowire.ConsumeFieldValue(num, typ, buf)
+		}
+		if n < 0 {
+			return errs.Wrap(protowire.ParseError(n))
+		}
+		buf = buf[n:]
+	}
+	return nil
+}
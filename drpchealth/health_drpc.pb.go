@@ -0,0 +1,256 @@
+// Code generated by protoc-gen-go-drpc. DO NOT EDIT.
+// protoc-gen-go-drpc version: (devel)
+// source: health.proto
+
+package drpchealth
+
+import (
+	context "context"
+	errors "errors"
+	drpc "storj.io/drpc"
+	drpcerr "storj.io/drpc/drpcerr"
+	codec "storj.io/drpc/drpchealth/internal/codec"
+)
+
+type drpcEncoding_File_health_proto struct{}
+
+func (drpcEncoding_File_health_proto) Marshal(msg drpc.Message) ([]byte, error) {
+	return codec.Marshal(msg)
+}
+
+func (drpcEncoding_File_health_proto) Unmarshal(buf []byte, msg drpc.Message) error {
+	return codec.Unmarshal(buf, msg)
+}
+
+func (drpcEncoding_File_health_proto) JSONMarshal(msg drpc.Message) ([]byte, error) {
+	return codec.JSONMarshal(msg)
+}
+
+func (drpcEncoding_File_health_proto) JSONUnmarshal(buf []byte, msg drpc.Message) error {
+	return codec.JSONUnmarshal(buf, msg)
+}
+
+var drpcFileDescriptor_File_health_proto = []byte{
+	0x0a, 0x0c, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0e,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x22, 0x2e,
+	0x0a, 0x12, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x22, 0xb1,
+	0x01, 0x0a, 0x13, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x31, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x68, 0x65,
+	0x61, 0x6c, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68,
+	0x65, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x22, 0x4f, 0x0a, 0x0d, 0x53, 0x65, 0x72, 0x76, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12,
+	0x0b, 0x0a, 0x07, 0x53, 0x45, 0x52, 0x56, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b,
+	0x4e, 0x4f, 0x54, 0x5f, 0x53, 0x45, 0x52, 0x56, 0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x13, 0x0a,
+	0x0f, 0x53, 0x45, 0x52, 0x56, 0x49, 0x43, 0x45, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e,
+	0x10, 0x03, 0x32, 0xae, 0x01, 0x0a, 0x06, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x12, 0x50, 0x0a,
+	0x05, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x12, 0x22, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x68, 0x65,
+	0x61, 0x6c, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68,
+	0x65, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x67, 0x72, 0x70,
+	0x63, 0x2e, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x65, 0x61, 0x6c,
+	0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x52, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x22, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68,
+	0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x65,
+	0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x30, 0x01, 0x42, 0x1a, 0x5a, 0x18, 0x73, 0x74, 0x6f, 0x72, 0x6a, 0x2e, 0x69, 0x6f, 0x2f,
+	0x64, 0x72, 0x70, 0x63, 0x2f, 0x64, 0x72, 0x70, 0x63, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+type DRPCHealthClient interface {
+	DRPCConn() drpc.Conn
+
+	Check(ctx context.Context, in *HealthCheckRequest) (*HealthCheckResponse, error)
+	Watch(ctx context.Context, in *HealthCheckRequest) (DRPCHealth_WatchClient, error)
+}
+
+type drpcHealthClient struct {
+	cc drpc.Conn
+}
+
+func NewDRPCHealthClient(cc drpc.Conn) DRPCHealthClient {
+	return &drpcHealthClient{cc}
+}
+
+func (c *drpcHealthClient) DRPCConn() drpc.Conn { return c.cc }
+
+func (c *drpcHealthClient) Check(ctx context.Context, in *HealthCheckRequest) (*HealthCheckResponse, error) {
+	out := new(HealthCheckResponse)
+	err := c.cc.Invoke(ctx, "/grpc.health.v1.Health/Check", drpcEncoding_File_health_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcHealthClient) Watch(ctx context.Context, in *HealthCheckRequest) (DRPCHealth_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, "/grpc.health.v1.Health/Watch", drpcEncoding_File_health_proto{})
+	if err != nil {
+		return nil, err
+	}
+	x := &drpcHealth_WatchClient{stream}
+	if err := x.MsgSend(in, drpcEncoding_File_health_proto{}); err != nil {
+		return nil, err
+	}
+	if err := x.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DRPCHealth_WatchClient interface {
+	drpc.Stream
+	Recv() (*HealthCheckResponse, error)
+}
+
+type RPCHealth_WatchClient interface {
+	Context() context.Context
+	CloseSend() error
+	Recv() (*HealthCheckResponse, error)
+}
+
+type drpcHealth_WatchClient struct {
+	drpc.Stream
+}
+
+func (x *drpcHealth_WatchClient) GetStream() drpc.Stream {
+	return x.Stream
+}
+
+func (x *drpcHealth_WatchClient) Recv() (*HealthCheckResponse, error) {
+	m := new(HealthCheckResponse)
+	if err := x.MsgRecv(m, drpcEncoding_File_health_proto{}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *drpcHealth_WatchClient) RecvMsg(m *HealthCheckResponse) error {
+	return x.MsgRecv(m, drpcEncoding_File_health_proto{})
+}
+
+type DRPCHealthServer interface {
+	Check(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error)
+	Watch(*HealthCheckRequest, DRPCHealth_WatchStream) error
+}
+
+type DRPCHealthUnimplementedServer struct{}
+
+func (s *DRPCHealthUnimplementedServer) Check(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCHealthUnimplementedServer) Watch(*HealthCheckRequest, DRPCHealth_WatchStream) error {
+	return drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+type DRPCHealthDescription struct{}
+
+func (DRPCHealthDescription) NumMethods() int { return 2 }
+
+func (DRPCHealthDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver, interface{}, bool) {
+	switch n {
+	case 0:
+		return "/grpc.health.v1.Health/Check", drpcEncoding_File_health_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCHealthServer).
+					Check(
+						ctx,
+						in1.(*HealthCheckRequest),
+					)
+			}, DRPCHealthServer.Check, true
+	case 1:
+		return "/grpc.health.v1.Health/Watch", drpcEncoding_File_health_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return nil, srv.(DRPCHealthServer).
+					Watch(
+						in1.(*HealthCheckRequest),
+						&drpcHealth_WatchStream{in2.(drpc.Stream)},
+					)
+			}, DRPCHealthServer.Watch, true
+	default:
+		return "", nil, nil, nil, false
+	}
+}
+
+func (DRPCHealthDescription) FileDescriptor() []byte { return drpcFileDescriptor_File_health_proto }
+
+func DRPCRegisterHealth(mux drpc.Mux, impl DRPCHealthServer) error {
+	return mux.Register(impl, DRPCHealthDescription{})
+}
+
+type DRPCHealth_CheckStream interface {
+	drpc.Stream
+	SendAndClose(*HealthCheckResponse) error
+}
+
+type RPCHealth_CheckStream interface {
+	Context() context.Context
+	SendAndClose(*HealthCheckResponse) error
+}
+
+type drpcHealth_CheckStream struct {
+	drpc.Stream
+}
+
+func (x *drpcHealth_CheckStream) GetStream() drpc.Stream {
+	return x.Stream
+}
+
+func (x *drpcHealth_CheckStream) SendAndClose(m *HealthCheckResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_health_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCHealth_WatchStream interface {
+	drpc.Stream
+	Send(*HealthCheckResponse) error
+}
+
+type RPCHealth_WatchStream interface {
+	Context() context.Context
+	Send(*HealthCheckResponse) error
+}
+
+type drpcHealth_WatchStream struct {
+	drpc.Stream
+}
+
+func (x *drpcHealth_WatchStream) GetStream() drpc.Stream {
+	return x.Stream
+}
+
+func (x *drpcHealth_WatchStream) Send(m *HealthCheckResponse) error {
+	return x.MsgSend(m, drpcEncoding_File_health_proto{})
+}
+
+type RPCHealthClient interface {
+	Check(ctx context.Context, in *HealthCheckRequest) (*HealthCheckResponse, error)
+	Watch(ctx context.Context, in *HealthCheckRequest) (RPCHealth_WatchClient, error)
+}
+
+// Health DRPC -> RPC adapter
+type drpcHealthClientAdapter drpcHealthClient
+
+func NewDRPCHealthClientAdapter(conn drpc.Conn) RPCHealthClient {
+	return (*drpcHealthClientAdapter)(&drpcHealthClient{conn})
+}
+
+func (a *drpcHealthClientAdapter) Check(ctx context.Context, in *HealthCheckRequest) (*HealthCheckResponse, error) {
+	return (*drpcHealthClient)(a).Check(ctx, in)
+}
+
+func (a *drpcHealthClientAdapter) Watch(ctx context.Context, in *HealthCheckRequest) (RPCHealth_WatchClient, error) {
+	return (*drpcHealthClient)(a).Watch(ctx, in)
+}
+
+// compile-time assertion
+var _ RPCHealthClient = (*drpcHealthClientAdapter)(nil)
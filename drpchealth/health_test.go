@@ -0,0 +1,42 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpchealth
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/zeebo/assert"
+)
+
+func TestMessages(t *testing.T) {
+	req := &HealthCheckRequest{Service: "svc"}
+	buf, err := req.Marshal()
+	assert.NoError(t, err)
+	assert.DeepEqual(t, buf, []byte{0x0a, 3, 's', 'v', 'c'})
+
+	// unknown fields are skipped.
+	var req2 HealthCheckRequest
+	assert.NoError(t, req2.Unmarshal(append([]byte{0x10, 0x05}, buf...)))
+	assert.Equal(t, req2.Service, "svc")
+
+	resp := &HealthCheckResponse{Status: HealthCheckResponse_NOT_SERVING}
+	buf, err = resp.Marshal()
+	assert.NoError(t, err)
+	assert.DeepEqual(t, buf, []byte{0x08, 2})
+
+	var resp2 HealthCheckResponse
+	assert.NoError(t, resp2.Unmarshal(buf))
+	assert.Equal(t, resp2.Status, HealthCheckResponse_NOT_SERVING)
+	assert.Error(t, resp2.Unmarshal([]byte{0x08}))
+
+	// statuses are json encoded by name, like protojson does.
+	buf, err = json.Marshal(resp)
+	assert.NoError(t, err)
+	assert.Equal(t, string(buf), `{"status":"NOT_SERVING"}`)
+	assert.NoError(t, json.Unmarshal([]byte(`{"status":1}`), &resp2))
+	assert.Equal(t, resp2.Status, HealthCheckResponse_SERVING)
+	assert.NoError(t, json.Unmarshal([]byte(`{"status":"SERVICE_UNKNOWN"}`), &resp2))
+	assert.Equal(t, resp2.Status, HealthCheckResponse_SERVICE_UNKNOWN)
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package codec is the encoding used by the code generated for drpchealth. It
+// marshals messages that know how to marshal themselves so that the health
+// messages do not need to be registered with the global protobuf registry,
+// where they would conflict with the ones generated for gRPC.
+package codec
+
+import (
+	"encoding/json"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/drpc"
+)
+
+// message is a Message that knows how to marshal itself.
+type message interface {
+	Marshal() ([]byte, error)
+	Unmarshal(buf []byte) error
+}
+
+// Marshal returns the encoded form of msg.
+func Marshal(msg drpc.Message) ([]byte, error) {
+	m, ok := msg.(message)
+	if !ok {
+		return nil, errs.New("unable to marshal %T", msg)
+	}
+	return m.Marshal()
+}
+
+// Unmarshal reads the encoded form of some Message into msg.
+func Unmarshal(buf []byte, msg drpc.Message) error {
+	m, ok := msg.(message)
+	if !ok {
+		return errs.New("unable to unmarshal %T", msg)
+	}
+	return m.Unmarshal(buf)
+}
+
+// JSONMarshal returns the json encoded form of msg.
+func JSONMarshal(msg drpc.Message) ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+// JSONUnmarshal reads the json encoded form of some Message into msg.
+func JSONUnmarshal(buf []byte, msg drpc.Message) error {
+	return json.Unmarshal(buf, msg)
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpchealth
+
+import (
+	"context"
+	"sync"
+
+	"storj.io/drpc/drpcerr"
+)
+
+// Server implements the grpc.health.v1.Health service with a serving status
+// for every service.
+type Server struct {
+	mu       sync.Mutex
+	shutdown bool
+	statuses map[string]HealthCheckResponse_ServingStatus
+	watchers map[string]map[chan HealthCheckResponse_ServingStatus]struct{}
+}
+
+var _ DRPCHealthServer = (*Server)(nil)
+
+// NewServer returns a Server where the server as a whole, the empty service,
+// is serving.
+func NewServer() *Server {
+	return &Server{
+		statuses: map[string]HealthCheckResponse_ServingStatus{"": HealthCheckResponse_SERVING},
+		watchers: make(map[string]map[chan HealthCheckResponse_ServingStatus]struct{}),
+	}
+}
+
+// SetServingStatus sets the serving status of the service and notifies the
+// watchers of it if it changed. It does nothing after Shutdown until Resume.
+func (s *Server) SetServingStatus(service string, status HealthCheckResponse_ServingStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.shutdown {
+		return
+	}
+	s.setLocked(service, status)
+}
+
+// Shutdown sets every service to NOT_SERVING and ignores further calls to
+// SetServingStatus until Resume is called. It is meant to be called when the
+// server starts to shut down so that clients stop sending it rpcs.
+func (s *Server) Shutdown() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.shutdown = true
+	for service := range s.statuses {
+		s.setLocked(service, HealthCheckResponse_NOT_SERVING)
+	}
+}
+
+// Resume sets every service to SERVING and allows SetServingStatus to be
+// called again.
+func (s *Server) Resume() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.shutdown = false
+	for service := range s.statuses {
+		s.setLocked(service, HealthCheckResponse_SERVING)
+	}
+}
+
+// setLocked sets the status of the service and notifies its watchers. It must
+// be called with the mutex held.
+func (s *Server) setLocked(service string, status HealthCheckResponse_ServingStatus) {
+	if old, ok := s.statuses[service]; ok && old == status {
+		return
+	}
+	s.statuses[service] = status
+	for ch := range s.watchers[service] {
+		notify(ch, status)
+	}
+}
+
+// notify replaces any status in the channel that has not been received with
+// the status, so that watchers only see the latest one.
+func notify(ch chan HealthCheckResponse_ServingStatus, status HealthCheckResponse_ServingStatus) {
+	select {
+	case <-ch:
+	default:
+	}
+	ch <- status
+}
+
+// Check returns the serving status of the service. It fails with an error with
+// the NotFound code if the service is unknown.
+func (s *Server) Check(ctx context.Context, req *HealthCheckRequest) (*HealthCheckResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status, ok := s.statuses[req.GetService()]
+	if !ok {
+		return nil, drpcerr.Newf(drpcerr.NotFound, "unknown service: %q", req.GetService())
+	}
+	return &HealthCheckResponse{Status: status}, nil
+}
+
+// Watch sends the serving status of the service, and again every time it
+// changes, until the stream is done. The status of an unknown service is
+// SERVICE_UNKNOWN.
+func (s *Server) Watch(req *HealthCheckRequest, stream DRPCHealth_WatchStream) error {
+	service := req.GetService()
+	ch := make(chan HealthCheckResponse_ServingStatus, 1)
+
+	s.mu.Lock()
+	if s.watchers[service] == nil {
+		s.watchers[service] = make(map[chan HealthCheckResponse_ServingStatus]struct{})
+	}
+	s.watchers[service][ch] = struct{}{}
+	status, ok := s.statuses[service]
+	if !ok {
+		status = HealthCheckResponse_SERVICE_UNKNOWN
+	}
+	notify(ch, status)
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		delete(s.watchers[service], ch)
+		if len(s.watchers[service]) == 0 {
+			delete(s.watchers, service)
+		}
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case status := <-ch:
+			if err := stream.Send(&HealthCheckResponse{Status: status}); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpchealth
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/zeebo/assert"
+
+	"storj.io/drpc/drpcconn"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcmux"
+	"storj.io/drpc/drpcpool"
+	"storj.io/drpc/drpcserver"
+	"storj.io/drpc/drpctest"
+)
+
+func newConn(t *testing.T, ctx *drpctest.Tracker, srv *Server) *drpcconn.Conn {
+	mux := drpcmux.New()
+	assert.NoError(t, DRPCRegisterHealth(mux, srv))
+
+	pc, ps := net.Pipe()
+	ctx.Run(func(ctx context.Context) { _ = drpcserver.New(mux).ServeOne(ctx, ps) })
+
+	conn := drpcconn.New(pc)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestServer_Check(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	srv := NewServer()
+	conn := newConn(t, ctx, srv)
+	client := NewDRPCHealthClient(conn)
+
+	// the server as a whole is serving, and unknown services are not found.
+	resp, err := client.Check(ctx, &HealthCheckRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, resp.Status, HealthCheckResponse_SERVING)
+
+	_, err = client.Check(ctx, &HealthCheckRequest{Service: "svc"})
+	assert.Equal(t, drpcerr.Code(err), uint64(drpcerr.NotFound))
+	assert.Equal(t, drpcerr.Code(Check(ctx, conn, "svc")), uint64(drpcerr.NotFound))
+
+	srv.SetServingStatus("svc", HealthCheckResponse_NOT_SERVING)
+	assert.Equal(t, drpcerr.Code(Check(ctx, conn, "svc")), uint64(drpcerr.Unavailable))
+
+	srv.SetServingStatus("svc", HealthCheckResponse_SERVING)
+	assert.NoError(t, Check(ctx, conn, "svc"))
+
+	// shutting down marks everything as not serving until resumed.
+	srv.Shutdown()
+	srv.SetServingStatus("svc", HealthCheckResponse_SERVING)
+	assert.Error(t, Check(ctx, conn, ""))
+	assert.Error(t, Check(ctx, conn, "svc"))
+
+	srv.Resume()
+	assert.NoError(t, Check(ctx, conn, ""))
+	assert.NoError(t, Checker("svc")(ctx, conn))
+}
+
+func TestServer_Watch(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	srv := NewServer()
+	client := NewDRPCHealthClient(newConn(t, ctx, srv))
+
+	wctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.Watch(wctx, &HealthCheckRequest{Service: "svc"})
+	assert.NoError(t, err)
+
+	recv := func() HealthCheckResponse_ServingStatus {
+		t.Helper()
+		resp, err := stream.Recv()
+		assert.NoError(t, err)
+		return resp.Status
+	}
+
+	// the current status is sent right away, and again when it changes.
+	assert.Equal(t, recv(), HealthCheckResponse_SERVICE_UNKNOWN)
+	srv.SetServingStatus("svc", HealthCheckResponse_SERVING)
+	assert.Equal(t, recv(), HealthCheckResponse_SERVING)
+	srv.SetServingStatus("svc", HealthCheckResponse_SERVING)
+	srv.SetServingStatus("svc", HealthCheckResponse_NOT_SERVING)
+	assert.Equal(t, recv(), HealthCheckResponse_NOT_SERVING)
+
+	// canceling the watch removes the watcher.
+	cancel()
+	_, err = stream.Recv()
+	assert.Error(t, err)
+	assert.NoError(t, stream.Close())
+
+	for {
+		srv.mu.Lock()
+		watching := len(srv.watchers)
+		srv.mu.Unlock()
+		if watching == 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCheckPool(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	srv := NewServer()
+	conn := newConn(t, ctx, srv)
+	pool := drpcpool.New[string, *drpcconn.Conn](drpcpool.Options{})
+	defer func() { _ = pool.Close() }()
+
+	// keys without a cached connection are fine.
+	assert.NoError(t, CheckPool(ctx, pool, "missing", ""))
+
+	// healthy connections stay in the pool.
+	pool.Put("addr", conn)
+	assert.NoError(t, CheckPool(ctx, pool, "addr", ""))
+	cached, ok := pool.Take("addr")
+	assert.That(t, ok)
+	assert.Equal(t, cached, conn)
+
+	// and unhealthy ones are closed.
+	pool.Put("addr", conn)
+	srv.Shutdown()
+	assert.Error(t, CheckPool(ctx, pool, "addr", ""))
+	_, ok = pool.Take("addr")
+	assert.That(t, !ok)
+	<-conn.Closed()
+}
@@ -5,6 +5,7 @@ package drpchttp
 
 import (
 	"context"
+	"encoding/base64"
 	"net/http"
 	"strings"
 
@@ -18,15 +19,23 @@ import (
 //
 
 // Context returns the context.Context from the http.Request with any metadata
-// sent using the X-Drpc-Metadata header set as values.
+// sent using the X-Drpc-Metadata header set as values. The metadata is
+// available with drpcmetadata.FromIncomingContext, and with the last value for
+// each key, with drpcmetadata.Get.
 func Context(req *http.Request) (context.Context, error) {
 	// header string we look up must already be canonicalized
 	return buildContext(req.Context(), req.Header["X-Drpc-Metadata"])
 }
 
 // buildContext adds key/value pairs in entries that are of the form
-// `urlencode(key)=urlencode(value)` to the passed in context.
+// `urlencode(key)=urlencode(value)` to the passed in context. The values of
+// binary keys are additionally base64 encoded.
 func buildContext(ctx context.Context, entries []string) (context.Context, error) {
+	if len(entries) == 0 {
+		return ctx, nil
+	}
+
+	md := make(drpcmetadata.MD)
 	for _, entry := range entries {
 		var key, value string
 		var err error
@@ -45,10 +54,32 @@ func buildContext(ctx context.Context, entries []string) (context.Context, error
 			return nil, err
 		}
 
-		ctx = drpcmetadata.Add(ctx, key, value)
+		if drpcmetadata.IsBinaryKey(key) {
+			value, err = decodeBinary(value)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		md.Append(key, value)
 	}
 
-	return ctx, nil
+	ctx = drpcmetadata.NewIncomingContext(ctx, md)
+	return drpcmetadata.AddPairs(ctx, md.Flatten()), nil
+}
+
+// decodeBinary decodes the base64 value of a binary key, with or without
+// padding.
+func decodeBinary(value string) (string, error) {
+	enc := base64.StdEncoding
+	if len(value)%4 != 0 {
+		enc = base64.RawStdEncoding
+	}
+	data, err := enc.DecodeString(value)
+	if err != nil {
+		return "", errs.New("error decoding binary value %q: %v", value, err)
+	}
+	return string(data), nil
 }
 
 // unhex adds to the accumulator c the numeric value of the hex digit v
@@ -41,6 +41,26 @@ func TestBuildContext(t *testing.T) {
 		})
 	}
 
+	{ // multiple values and binary values are kept as incoming metadata
+		ctx, err := buildContext(context.Background(), []string{
+			"key=a",
+			"key=b",
+			"trace-bin=AP8=", // padded
+			"trace-bin=AP8",  // unpadded
+		})
+		assert.NoError(t, err)
+
+		md, ok := drpcmetadata.FromIncomingContext(ctx)
+		assert.That(t, ok)
+		assert.DeepEqual(t, md, drpcmetadata.MD{
+			"key":       {"a", "b"},
+			"trace-bin": {"\x00\xff", "\x00\xff"},
+		})
+
+		_, err = buildContext(context.Background(), []string{"trace-bin=!"})
+		assert.Error(t, err)
+	}
+
 	{ // no entries associates no metadata
 		ctx, err := buildContext(context.Background(), nil)
 		assert.NoError(t, err)
@@ -28,7 +28,13 @@ func New(handler drpc.Handler) http.Handler {
 //	X-Drpc-Metadata: percentEncode(key)=percentEncode(value)
 //
 // where percentEncode is the encoding used for query strings. Only the '%' and '='
-// characters are necessary to be escaped.
+// characters are necessary to be escaped. Values for binary keys, which end in
+// "-bin", are base64 encoded before being percent encoded.
+//
+// Response metadata set by the handler with drpcmetadata.SetHeader is sent back in
+// "X-Drpc-Metadata" response headers with the same format, and metadata set with
+// drpcmetadata.SetTrailer is sent in HTTP trailers with the same name, or for
+// the grpc-web content types, in the trailers at the end of the body.
 //
 // The specific protocol for the request and response used is chosen by the
 // request's Content-Type. By default the content types "application/json" and
@@ -87,12 +93,15 @@ func (w wrapper) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 }
 
 // getCode returns a string code for the provided error, or "unknown" if it
-// cannot find one. It uses reflect to pull Twirp codes out of the error
-// without having to import and depend on the Twirp module.
+// cannot find one. Canonical drpcerr codes are converted to their Twirp code.
+// It uses reflect to pull Twirp codes out of the error without having to
+// import and depend on the Twirp module.
 func getCode(err error) string {
 	code := "unknown"
-	if dcode := drpcerr.Code(err); dcode != 0 {
+	if dcode := drpcerr.Code(err); dcode > drpcerr.Unauthenticated {
 		code = fmt.Sprintf("drpcerr(%d)", dcode)
+	} else if dcode != 0 {
+		code = drpcerr.TwirpCode(dcode)
 	}
 	for i := 0; i < 100; i++ {
 		if m := reflect.ValueOf(err).MethodByName("Code"); m.IsValid() {
@@ -17,6 +17,7 @@ import (
 
 	"storj.io/drpc"
 	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcmetadata"
 )
 
 //
@@ -33,12 +34,13 @@ type grpcWebProtocol struct {
 
 func (gwp grpcWebProtocol) NewStream(rw http.ResponseWriter, req *http.Request) Stream {
 	rw.Header().Set("Content-Type", gwp.ct)
-	return &grpcWebStream{
-		ctx: req.Context(),
+	gws := &grpcWebStream{
 		gwp: gwp,
 		in:  req.Body,
 		rw:  rw,
 	}
+	gws.ctx = drpcmetadata.WithResponseWriter(req.Context(), gws)
+	return gws
 }
 
 func (gwp grpcWebProtocol) framedWrite(rw http.ResponseWriter, hdr byte, buf []byte) error {
@@ -52,6 +54,8 @@ func (gwp grpcWebProtocol) framedWrite(rw http.ResponseWriter, hdr byte, buf []b
 //
 
 type grpcWebStream struct {
+	responseMetadata
+
 	ctx context.Context
 	gwp grpcWebProtocol
 	in  io.ReadCloser
@@ -68,7 +72,9 @@ func (gws *grpcWebStream) MsgSend(msg drpc.Message, enc drpc.Encoding) (err erro
 		return err
 	} else if len(data) >= maxSize {
 		return errs.New("message too large")
-	} else if err := gws.gwp.framedWrite(gws.rw, 0, data); err != nil {
+	}
+	gws.writeHeader(gws.rw.Header())
+	if err := gws.gwp.framedWrite(gws.rw, 0, data); err != nil {
 		return err
 	} else if fl, ok := gws.rw.(http.Flusher); ok {
 		fl.Flush()
@@ -108,6 +114,11 @@ func (gws *grpcWebStream) Finish(err error) {
 		write("grpc-code", getCode(err))
 		write("grpc-message", err.Error())
 	}
+	for _, entry := range gws.takeTrailer() {
+		write(strings.ToLower(metadataHeader), entry)
+	}
+
+	gws.writeHeader(gws.rw.Header())
 
 	_ = gws.gwp.framedWrite(gws.rw, 128, buf.Bytes())
 }
@@ -10,6 +10,7 @@ import (
 	"net/http"
 
 	"storj.io/drpc"
+	"storj.io/drpc/drpcmetadata"
 )
 
 //
@@ -24,12 +25,13 @@ type twirpProtocol struct {
 
 func (tp twirpProtocol) NewStream(rw http.ResponseWriter, req *http.Request) Stream {
 	rw.Header().Set("Content-Type", tp.ct)
-	return &twirpStream{
-		ctx:  req.Context(),
+	ts := &twirpStream{
 		tp:   tp,
 		body: req.Body,
 		rw:   rw,
 	}
+	ts.ctx = drpcmetadata.WithResponseWriter(req.Context(), ts)
+	return ts
 }
 
 //
@@ -37,6 +39,8 @@ func (tp twirpProtocol) NewStream(rw http.ResponseWriter, req *http.Request) Str
 //
 
 type twirpStream struct {
+	responseMetadata
+
 	ctx  context.Context
 	tp   twirpProtocol
 	body io.ReadCloser
@@ -80,6 +84,13 @@ func (ts *twirpStream) MsgRecv(msg drpc.Message, enc drpc.Encoding) (err error)
 }
 
 func (ts *twirpStream) Finish(err error) {
+	ts.writeHeader(ts.rw.Header())
+	defer func() {
+		for _, entry := range ts.takeTrailer() {
+			ts.rw.Header().Add(http.TrailerPrefix+metadataHeader, entry)
+		}
+	}()
+
 	if err == nil {
 		ts.rw.WriteHeader(http.StatusOK)
 		_, _ = ts.rw.Write(ts.response)
@@ -0,0 +1,142 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpchttp
+
+import (
+	"encoding/base64"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/drpc/drpcmetadata"
+)
+
+//
+// code to collect and escape the response metadata
+//
+
+// metadataHeader is the header used for metadata in both directions.
+const metadataHeader = "X-Drpc-Metadata"
+
+// responseMetadata implements drpcmetadata.ResponseWriter for the streams by
+// collecting the metadata until the response is written.
+type responseMetadata struct {
+	mu      sync.Mutex
+	header  map[string]string
+	trailer map[string]string
+	hdrSent bool
+	trlSent bool
+}
+
+// SetHeader adds the metadata to the header.
+func (rm *responseMetadata) SetHeader(md map[string]string) error {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	if rm.hdrSent {
+		return errs.New("header already sent")
+	}
+	rm.header = merge(rm.header, md)
+	return nil
+}
+
+// SetTrailer adds the metadata to the trailer.
+func (rm *responseMetadata) SetTrailer(md map[string]string) error {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	if rm.trlSent {
+		return errs.New("trailer already sent")
+	}
+	rm.trailer = merge(rm.trailer, md)
+	return nil
+}
+
+// writeHeader adds the header metadata entries to the http headers, after
+// which the header can no longer be changed. It does nothing if they were
+// already added.
+func (rm *responseMetadata) writeHeader(h http.Header) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	if rm.hdrSent {
+		return
+	}
+	rm.hdrSent = true
+	for _, entry := range entries(rm.header) {
+		h.Add(metadataHeader, entry)
+	}
+}
+
+// takeTrailer returns the trailer metadata entries, after which the trailer
+// can no longer be changed.
+func (rm *responseMetadata) takeTrailer() []string {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.trlSent = true
+	return entries(rm.trailer)
+}
+
+// merge adds the entries of md into into, allocating it if necessary.
+func merge(into, md map[string]string) map[string]string {
+	if into == nil && len(md) > 0 {
+		into = make(map[string]string, len(md))
+	}
+	for key, value := range md {
+		into[key] = value
+	}
+	return into
+}
+
+// entries returns the metadata in the form `escape(key)=escape(value)`, sorted
+// so that responses are deterministic. The values of binary keys are base64
+// encoded first.
+func entries(md map[string]string) []string {
+	out := make([]string, 0, len(md))
+	for key, value := range md {
+		if drpcmetadata.IsBinaryKey(key) {
+			value = base64.StdEncoding.EncodeToString([]byte(value))
+		}
+		out = append(out, escape(key)+"="+escape(value))
+	}
+	sort.Strings(out)
+	return out
+}
+
+// shouldEscape returns true if the byte must be percent encoded.
+func shouldEscape(c byte) bool { return c == '%' || c == '=' || c < 0x20 || c >= 0x7f }
+
+// escape percent encodes the '%' and '=' characters along with any bytes that
+// are not allowed in http header values. It is the inverse of unescape.
+func escape(s string) string {
+	const hex = "0123456789ABCDEF"
+
+	count := 0
+	for i := 0; i < len(s); i++ {
+		if shouldEscape(s[i]) {
+			count++
+		}
+	}
+	if count == 0 {
+		return s
+	}
+
+	var t strings.Builder
+	t.Grow(len(s) + 2*count)
+
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; shouldEscape(c) {
+			_ = t.WriteByte('%')
+			_ = t.WriteByte(hex[c>>4])
+			_ = t.WriteByte(hex[c&15])
+		} else {
+			_ = t.WriteByte(c)
+		}
+	}
+	return t.String()
+}
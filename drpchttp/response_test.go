@@ -0,0 +1,62 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpchttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zeebo/assert"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcmetadata"
+)
+
+func TestEscape(t *testing.T) {
+	for _, s := range []string{"", "plain", "a=b", "100%", "line\nbreak", "café"} {
+		escaped := escape(s)
+		assert.That(t, !strings.ContainsAny(escaped, "=\n"))
+		unescaped, err := unescape(escaped)
+		assert.NoError(t, err)
+		assert.Equal(t, unescaped, s)
+	}
+}
+
+// metadataHandler is a drpc.Handler that sets response metadata.
+type metadataHandler struct{}
+
+func (metadataHandler) HandleRPC(stream drpc.Stream, rpc string) error {
+	ctx := stream.Context()
+	if err := drpcmetadata.SetHeader(ctx, map[string]string{"version": "1", "a=b": "c", "trace-bin": "\x00\xff"}); err != nil {
+		return err
+	}
+	return drpcmetadata.SetTrailer(ctx, map[string]string{"remaining": "10"})
+}
+
+func TestResponseMetadata(t *testing.T) {
+	handler := New(metadataHandler{})
+
+	// twirp responses have the header in headers and the trailer in trailers.
+	req := httptest.NewRequest("POST", "/service.Service/Method", strings.NewReader(""))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	res := rec.Result()
+	assert.Equal(t, res.StatusCode, http.StatusOK)
+	assert.DeepEqual(t, res.Header["X-Drpc-Metadata"], []string{"a%3Db=c", "trace-bin=AP8%3D", "version=1"})
+	assert.DeepEqual(t, res.Trailer["X-Drpc-Metadata"], []string{"remaining=10"})
+
+	// grpc-web responses have the trailer in the trailers frame.
+	req = httptest.NewRequest("POST", "/service.Service/Method", strings.NewReader(""))
+	req.Header.Set("Content-Type", "application/grpc-web+json")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	res = rec.Result()
+	assert.DeepEqual(t, res.Header["X-Drpc-Metadata"], []string{"a%3Db=c", "trace-bin=AP8%3D", "version=1"})
+	assert.That(t, strings.Contains(rec.Body.String(), "x-drpc-metadata: remaining=10\r\n"))
+}
@@ -10,6 +10,8 @@ import (
 	"io"
 	"net"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -17,6 +19,7 @@ import (
 
 	"storj.io/drpc"
 	"storj.io/drpc/drpcdebug"
+	"storj.io/drpc/drpcerr"
 	"storj.io/drpc/drpcmetadata"
 	"storj.io/drpc/drpcsignal"
 	"storj.io/drpc/drpcstream"
@@ -52,6 +55,42 @@ type Options struct {
 	// no timeout is used.
 	InactivityTimeout time.Duration
 
+	// MaxConcurrentStreams, if larger than 1, enables multiplexing streams on
+	// the transport and limits how many of them may be active at once. Packets
+	// are routed to streams by their stream id, and each stream buffers the
+	// packets sent to it so that a slow stream does not block the others. The
+	// amount buffered can be bounded with the flow control windows in the
+	// Stream options. Multiplexed streams are always soft canceled, and
+	// SoftCancel is ignored.
+	//
+	// Both sides of a transport must enable multiplexing, because a manager
+	// without it cancels the active stream whenever a new one is invoked.
+	// Streams invoked by the remote beyond the limit are refused with an
+	// Unavailable error.
+	MaxConcurrentStreams int
+
+	// MaxQueuedSize is the largest amount of packet data that may be buffered
+	// for a multiplexed stream. If the remote sends more than that before the
+	// stream reads it, the stream fails with a ResourceExhausted error. Flow
+	// control windows keep a remote that supports them below the limit. If
+	// zero, four times the largest packet the Reader allows is used.
+	MaxQueuedSize int
+
+	// KeepaliveInterval, if positive, causes the manager to ping the remote
+	// whenever it has not read anything from the transport for that long. If
+	// the remote does not respond within KeepaliveTimeout, the transport is
+	// closed. The remote must be using a version of drpc that responds to
+	// pings, which it always does regardless of its own options.
+	KeepaliveInterval time.Duration
+
+	// KeepaliveTimeout is the amount of time to wait for a response to a ping
+	// before closing the transport. If zero or negative, 20 seconds is used.
+	KeepaliveTimeout time.Duration
+
+	// KeepalivePermitWithoutStream allows pings to be sent even when there
+	// are no active streams. Otherwise, an idle transport is not checked.
+	KeepalivePermitWithoutStream bool
+
 	// Internal contains options that are for internal use only.
 	Internal drpcopts.Manager
 }
@@ -73,16 +112,30 @@ type Manager struct {
 	sfin    chan struct{}        // shared signal for stream finished
 	streams chan streamInfo      // channel to signal that a stream should start
 
+	wmu   sync.Mutex    // held while writing connection level packets or resetting the writer
+	cid   uint64        // message id of the last connection level packet
+	pings chan struct{} // signals that a ping needs a response
+	last  atomic.Int64  // unix nanoseconds of the last packet read
+
+	gmu      sync.Mutex    // protects invoked and drainID
+	invoked  uint64        // largest stream id the remote has invoked
+	drainID  uint64        // largest stream id that will be accepted once draining
+	accepted atomic.Uint64 // largest stream id accepted by NewServerStream
+
 	sigs struct {
 		term   drpcsignal.Signal // set when the manager should start terminating
 		stream drpcsignal.Signal // set when the manage streams goroutine is done
 		read   drpcsignal.Signal // set after the goroutine reading from the transport is done
+		keep   drpcsignal.Signal // set when the manage keepalive goroutine is done
 		tport  drpcsignal.Signal // set after the transport has been closed
+		drain  drpcsignal.Signal // set when a go away has been sent to the remote
+		away   drpcsignal.Signal // set when a go away has been received from the remote
 	}
 }
 
 type streamInfo struct {
 	ctx    context.Context
+	cancel context.CancelFunc // called once the stream is finished, if not nil
 	stream *drpcstream.Stream
 }
 
@@ -94,6 +147,12 @@ func New(tr drpc.Transport) *Manager {
 // NewWithOptions returns a new manager for the transport. It uses the provided
 // options to manage details of how it uses it.
 func NewWithOptions(tr drpc.Transport, opts Options) *Manager {
+	// multiplexed streams can have their packets interleaved on the wire.
+	if opts.MaxConcurrentStreams > 1 {
+		opts.Reader.Multiplexed = true
+		drpcopts.SetStreamMultiplexed(&opts.Stream.Internal, true)
+	}
+
 	m := &Manager{
 		tr:   tr,
 		wr:   drpcwire.NewWriter(tr, opts.WriterBufferSize),
@@ -103,13 +162,27 @@ func NewWithOptions(tr drpc.Transport, opts Options) *Manager {
 		pkts:    make(chan drpcwire.Packet),
 		sfin:    make(chan struct{}, 1),
 		streams: make(chan streamInfo),
+		pings:   make(chan struct{}, 1),
 	}
 
 	// initialize the stream buffer
-	m.sbuf.init()
-
-	// this semaphore controls the number of concurrent streams. it MUST be 1.
-	m.sem.Make(1)
+	queued := opts.MaxQueuedSize
+	if queued <= 0 {
+		queued = opts.Reader.MaximumBufferSize
+		if queued <= 0 {
+			queued = 4 << 20
+		}
+		queued *= 4
+	}
+	m.sbuf.init(opts.MaxConcurrentStreams, queued)
+
+	// this semaphore controls the number of concurrent streams. it MUST be 1
+	// unless the streams are multiplexed.
+	if m.multiplexed() {
+		m.sem.Make(uint(opts.MaxConcurrentStreams))
+	} else {
+		m.sem.Make(1)
+	}
 
 	// a buffer of size 1 allows the consumer of the packet to signal it is done
 	// without having to coordinate with the sender of the packet.
@@ -121,6 +194,7 @@ func NewWithOptions(tr drpc.Transport, opts Options) *Manager {
 
 	go m.manageReader()
 	go m.manageStreams()
+	go m.manageKeepalive()
 
 	return m
 }
@@ -138,6 +212,18 @@ func (m *Manager) log(what string, cb func() string) {
 // helpers
 //
 
+// multiplexed returns true if many streams may be active at once.
+func (m *Manager) multiplexed() bool { return m.opts.MaxConcurrentStreams > 1 }
+
+// activeStreams returns true if there are any streams that are not finished.
+func (m *Manager) activeStreams() bool {
+	if m.multiplexed() {
+		return m.sbuf.Active() > 0
+	}
+	curr := m.sbuf.Get()
+	return curr != nil && !curr.IsFinished()
+}
+
 // acquireSemaphore attempts to acquire the semaphore protecting streams. If the
 // context is canceled or the manager is terminated, it returns an error.
 func (m *Manager) acquireSemaphore(ctx context.Context) error {
@@ -168,6 +254,11 @@ func (m *Manager) acquireSemaphore(ctx context.Context) error {
 // longer make any reads or writes on the transport. It exits early if the
 // context is canceled or the manager is terminated.
 func (m *Manager) waitForPreviousStream(ctx context.Context) (err error) {
+	// multiplexed streams do not wait for each other.
+	if m.multiplexed() {
+		return nil
+	}
+
 	prev := m.sbuf.Get()
 	if prev == nil {
 		return nil
@@ -193,6 +284,34 @@ func (m *Manager) waitForPreviousStream(ctx context.Context) (err error) {
 	}
 }
 
+// unavailable associates errors from using the transport, which mean that it
+// can no longer be used, with the Unavailable code. The code is attached to the
+// innermost error so that the classes of any wrapping errors can be checked.
+func unavailable(err error) error {
+	return drpcerr.WithCode(err, drpcerr.Unavailable)
+}
+
+// admitInvoke records that the remote is invoking the stream id and returns
+// false if the stream must be dropped because it was invoked after a go away.
+func (m *Manager) admitInvoke(sid uint64) bool {
+	m.gmu.Lock()
+	defer m.gmu.Unlock()
+
+	if m.sigs.drain.IsSet() && sid > m.drainID {
+		return false
+	}
+	if sid > m.invoked {
+		m.invoked = sid
+	}
+	return true
+}
+
+// drained returns true if a go away has been sent and every stream invoked
+// before it has been accepted.
+func (m *Manager) drained() bool {
+	return m.sigs.drain.IsSet() && m.accepted.Load() >= m.drainID
+}
+
 // terminate puts the Manager into a terminal state and closes any resources
 // that need to be closed to signal the state change.
 func (m *Manager) terminate(err error) {
@@ -229,6 +348,9 @@ func (m *Manager) manageReader() {
 
 		pkt, err = m.rd.ReadPacketUsing(pkt.Data[:0])
 		if err != nil {
+			if !drpc.ProtocolError.Has(err) {
+				err = unavailable(err)
+			}
 			if isConnectionReset(err) {
 				err = drpc.ClosedError.Wrap(err)
 			}
@@ -236,14 +358,68 @@ func (m *Manager) manageReader() {
 			return
 		}
 
+		m.log("READ", pkt.String)
+
+		if m.opts.KeepaliveInterval > 0 {
+			m.last.Store(time.Now().UnixNano())
+		}
+
+		// connection level packets are not part of any stream.
+		if pkt.ID.Stream == 0 {
+			switch pkt.Kind {
+			case drpcwire.KindPing:
+				select {
+				case m.pings <- struct{}{}:
+				default:
+				}
+
+			case drpcwire.KindGoAway:
+				_, last, ok, err := drpcwire.ReadVarint(pkt.Data)
+				if !ok || err != nil {
+					m.terminate(managerClosed.Wrap(drpc.ProtocolError.New("invalid go away")))
+					return
+				}
+				if m.sigs.away.Set(drpc.ClosedError.Wrap(unavailable(errs.New("remote is going away")))) {
+					m.log("GOAWAY", func() string { return fmt.Sprint(last) })
+					go m.manageGoAway(last)
+				}
+			}
+			continue
+		}
+
+		// once a go away has been sent, streams invoked after it are dropped.
+		if pkt.Kind == drpcwire.KindInvoke || pkt.Kind == drpcwire.KindInvokeMetadata {
+			if !m.admitInvoke(pkt.ID.Stream) {
+				continue
+			}
+		} else if m.sigs.drain.IsSet() && pkt.ID.Stream > m.drainID {
+			continue
+		}
+
+		// multiplexed packets are queued for their stream, which then owns the
+		// packet's buffer, so the next read must use a fresh one.
+		if m.multiplexed() {
+			if stream, err := m.sbuf.Deliver(pkt); err != nil {
+				if err := m.failStream(pkt.ID.Stream, stream, err); err != nil {
+					m.terminate(managerClosed.Wrap(unavailable(err)))
+					return
+				}
+			} else if stream != nil {
+				if err := stream.HandlePacket(pkt); err != nil {
+					m.terminate(managerClosed.Wrap(err))
+					return
+				}
+			}
+			pkt.Data = nil
+			continue
+		}
+
 		if len(pkt.Data) < cap(pkt.Data)/4 {
 			run++
 		} else {
 			run = 0
 		}
 
-		m.log("READ", pkt.String)
-
 	again:
 		switch curr := m.sbuf.Get(); {
 		// if the packet is for the current stream, deliver it.
@@ -291,18 +467,31 @@ func (m *Manager) manageReader() {
 // manage streams
 //
 
-// newStream creates a stream value with the appropriate configuration for this manager.
-func (m *Manager) newStream(ctx context.Context, sid uint64, kind, rpc string) (*drpcstream.Stream, error) {
+// streamOptions returns the options for a new stream of the given kind and rpc
+// that uses the settings advertised in the invoke metadata.
+func (m *Manager) streamOptions(kind, rpc string, adv advertised) drpcstream.Options {
 	opts := m.opts.Stream
+	opts.Compression = adv.comp
+	drpcopts.SetStreamWindow(&opts.Internal, adv.window)
 	drpcopts.SetStreamKind(&opts.Internal, kind)
 	drpcopts.SetStreamRPC(&opts.Internal, rpc)
+	drpcopts.SetStreamServer(&opts.Internal, kind == "srv")
 	if cb := drpcopts.GetManagerStatsCB(&m.opts.Internal); cb != nil {
 		drpcopts.SetStreamStats(&opts.Internal, cb(rpc))
 	}
+	return opts
+}
 
-	stream := drpcstream.NewWithOptions(ctx, sid, m.wr, opts)
+// newStream creates a stream value with the appropriate configuration for this
+// manager. If cancel is not nil, it is called once the stream is finished.
+func (m *Manager) newStream(ctx context.Context, cancel context.CancelFunc, sid uint64, kind, rpc string, adv advertised) (*drpcstream.Stream, error) {
+	// creating the stream resets the writer, so it must not happen while a
+	// connection level packet is being written.
+	m.wmu.Lock()
+	stream := drpcstream.NewWithOptions(ctx, sid, m.wr, m.streamOptions(kind, rpc, adv))
+	m.wmu.Unlock()
 	select {
-	case m.streams <- streamInfo{ctx: ctx, stream: stream}:
+	case m.streams <- streamInfo{ctx: ctx, cancel: cancel, stream: stream}:
 		m.sbuf.Set(stream)
 		m.log("STREAM", stream.String)
 		return stream, nil
@@ -321,6 +510,9 @@ func (m *Manager) manageStreams() {
 		select {
 		case si := <-m.streams:
 			m.manageStream(si.ctx, si.stream)
+			if si.cancel != nil {
+				si.cancel()
+			}
 
 		case <-m.sigs.term.Signal():
 			return
@@ -329,7 +521,8 @@ func (m *Manager) manageStreams() {
 }
 
 // manageStream watches the context and the stream and returns when the stream
-// is finished, canceling the stream if the context is canceled.
+// is finished, canceling the stream if the context is canceled. The stream is
+// soft canceled if the manager uses soft cancels or the context requires it.
 func (m *Manager) manageStream(ctx context.Context, stream *drpcstream.Stream) {
 	select {
 	case <-m.sigs.term.Signal():
@@ -347,7 +540,7 @@ func (m *Manager) manageStream(ctx context.Context, stream *drpcstream.Stream) {
 	case <-ctx.Done():
 		m.log("CANCEL", stream.String)
 
-		if m.opts.SoftCancel {
+		if m.opts.SoftCancel || drpcopts.GetSoftCancel(ctx) {
 			// allow a new stream to begin.
 			m.sem.Recv()
 
@@ -383,6 +576,323 @@ func (m *Manager) manageStream(ctx context.Context, stream *drpcstream.Stream) {
 	}
 }
 
+//
+// manage multiplexed streams
+//
+
+// newMultiplexedStream creates a stream that receives packets from the queue
+// and launches the goroutines that manage it. The semaphore is released and,
+// if not nil, cancel is called once the stream is finished.
+func (m *Manager) newMultiplexedStream(ctx context.Context, cancel context.CancelFunc, q *streamQueue, kind, rpc string, adv advertised) *drpcstream.Stream {
+	fin := make(chan struct{}, 1)
+	opts := m.streamOptions(kind, rpc, adv)
+	drpcopts.SetStreamFin(&opts.Internal, fin)
+
+	stream := drpcstream.NewWithOptions(ctx, q.sid, m.wr, opts)
+	q.SetStream(stream)
+	m.log("STREAM", stream.String)
+
+	go m.manageQueue(stream, q)
+	go m.manageMultiplexedStream(ctx, cancel, stream, fin)
+
+	return stream
+}
+
+// manageQueue delivers the packets from the queue to the stream in order until
+// the queue is closed.
+func (m *Manager) manageQueue(stream *drpcstream.Stream, q *streamQueue) {
+	for {
+		pkt, ok := q.Get()
+		if !ok {
+			return
+		}
+		if err := stream.HandlePacket(pkt); err != nil {
+			m.terminate(managerClosed.Wrap(err))
+			return
+		}
+	}
+}
+
+// manageMultiplexedStream is like manageStream for a multiplexed stream. It
+// never terminates the transport to cancel the stream because that would also
+// cancel every other stream, so it always attempts a soft cancel.
+func (m *Manager) manageMultiplexedStream(ctx context.Context, cancel context.CancelFunc, stream *drpcstream.Stream, fin <-chan struct{}) {
+	if cancel != nil {
+		defer cancel()
+	}
+	defer m.sem.Recv()
+	defer m.sbuf.Remove(stream.ID())
+
+	select {
+	case <-m.sigs.term.Signal():
+		err := m.sigs.term.Err()
+		if errors.Is(err, io.EOF) {
+			err = context.Canceled
+		}
+		stream.Cancel(err)
+
+	case <-fin:
+		return
+
+	case <-ctx.Done():
+		m.log("CANCEL", stream.String)
+
+		// a multiplexed stream is never busy: it waits for any write in
+		// progress to complete before sending the cancel.
+		if _, err := stream.SendCancel(ctx.Err()); err != nil {
+			m.terminate(err)
+		}
+		stream.Cancel(ctx.Err())
+	}
+
+	// wait for the stream to signal that it is finished.
+	<-fin
+}
+
+// failStream fails the multiplexed stream with the error. If the stream has not
+// been created yet, the error is sent to the remote directly, and any error
+// doing that is returned.
+func (m *Manager) failStream(sid uint64, stream *drpcstream.Stream, err error) error {
+	m.log("FAIL", func() string { return fmt.Sprintf("%d: %v", sid, err) })
+
+	if stream != nil {
+		// the stream may have to wait for a write that needs this goroutine to
+		// read from the transport before it can finish.
+		go func() { _ = stream.SendError(err) }()
+		return nil
+	}
+
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	fr := drpcwire.Frame{
+		ID:   drpcwire.ID{Stream: sid, Message: 1},
+		Kind: drpcwire.KindError,
+		Data: drpcwire.MarshalError(err),
+		Done: true,
+	}
+
+	m.log("SEND", fr.String)
+
+	if err := m.wr.WriteFrame(fr); err != nil {
+		return err
+	}
+	return m.wr.Flush()
+}
+
+// acceptStream waits for the remote to invoke a multiplexed stream and returns
+// it along with the rpc that was invoked. The InactivityTimeout only applies
+// while there are no other active streams.
+func (m *Manager) acceptStream(ctx context.Context) (*drpcstream.Stream, string, error) {
+	var timer *time.Timer
+	var timeoutCh <-chan time.Time
+
+	// set up the timeout on the context if necessary.
+	if timeout := m.opts.InactivityTimeout; timeout > 0 {
+		timer = time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+
+	drainCh := m.sigs.drain.Signal()
+
+	for {
+		if q, ok := m.sbuf.Incoming(); ok {
+			if stream, rpc, err := m.acceptQueue(ctx, q); stream != nil || err != nil {
+				return stream, rpc, err
+			}
+			continue
+		}
+
+		select {
+		case <-drainCh:
+			if m.drained() {
+				return nil, "", m.sigs.drain.Err()
+			}
+			drainCh = nil
+
+		case <-timeoutCh:
+			if m.sbuf.Active() == 0 {
+				return nil, "", context.DeadlineExceeded
+			}
+			timer.Reset(m.opts.InactivityTimeout)
+
+		case <-ctx.Done():
+			return nil, "", ctx.Err()
+
+		case <-m.sigs.term.Signal():
+			return nil, "", m.sigs.term.Err()
+
+		case <-m.sbuf.Notify():
+		}
+	}
+}
+
+// acceptQueue consumes the invoke sequence from the queue of an announced
+// stream and creates the stream for it. It returns no stream and no error if
+// the stream failed before it could be created.
+func (m *Manager) acceptQueue(ctx context.Context, q *streamQueue) (*drpcstream.Stream, string, error) {
+	var meta drpcmetadata.MD
+
+	for {
+		pkt, ok := q.Get()
+		if !ok {
+			if err, ok := m.sigs.term.Get(); ok {
+				return nil, "", err
+			}
+			return nil, "", nil
+		}
+
+		switch pkt.Kind {
+		case drpcwire.KindInvokeMetadata:
+			var err error
+			meta, err = drpcmetadata.DecodeMD(pkt.Data)
+			if err != nil {
+				m.sbuf.Remove(q.sid)
+				return nil, "", err
+			}
+
+		case drpcwire.KindInvoke:
+			rpc := string(pkt.Data)
+			ctx, cancel, adv := serverContext(ctx, meta)
+			return m.newMultiplexedStream(ctx, cancel, q, "srv", rpc, adv), rpc, nil
+		}
+	}
+}
+
+//
+// manage go away
+//
+
+// manageGoAway handles a go away from the remote. Streams with an id larger
+// than last were never handled by the remote and are canceled so that they may
+// be retried. Once the remaining streams are finished, the manager is
+// terminated.
+func (m *Manager) manageGoAway(last uint64) {
+	err := m.sigs.away.Err()
+
+	var streams []*drpcstream.Stream
+	if m.multiplexed() {
+		streams = m.sbuf.Streams()
+	} else if curr := m.sbuf.Get(); curr != nil {
+		streams = append(streams, curr)
+	}
+
+	for _, stream := range streams {
+		if stream.ID() > last {
+			stream.Cancel(err)
+		}
+	}
+
+	for _, stream := range streams {
+		select {
+		case <-stream.Finished():
+		case <-m.sigs.term.Signal():
+			return
+		}
+	}
+
+	m.terminate(managerClosed.Wrap(err))
+}
+
+//
+// manage keepalive
+//
+
+// manageKeepalive responds to any pings from the remote and, if configured,
+// pings the remote when the transport is idle, terminating the manager if the
+// remote does not respond in time. It sets the keep signal when it exits.
+func (m *Manager) manageKeepalive() {
+	defer m.sigs.keep.Set(nil)
+
+	var tickCh <-chan time.Time
+	if interval := m.opts.KeepaliveInterval; interval > 0 {
+		m.last.Store(time.Now().UnixNano())
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tickCh = ticker.C
+	}
+
+	timeout := m.opts.KeepaliveTimeout
+	if timeout <= 0 {
+		timeout = 20 * time.Second
+	}
+
+	var timer *time.Timer
+	var timeoutCh <-chan time.Time
+	var sent int64
+
+	for {
+		select {
+		case <-m.sigs.term.Signal():
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+
+		case <-m.pings:
+			if err := m.writeConnPacket(drpcwire.KindPong, nil); err != nil {
+				m.terminate(managerClosed.Wrap(unavailable(err)))
+				return
+			}
+
+		case <-tickCh:
+			// nothing needs to be sent if a ping is outstanding, if the remote
+			// was recently heard from, or if there is nothing to keep alive.
+			if timeoutCh != nil {
+				continue
+			} else if time.Since(time.Unix(0, m.last.Load())) < m.opts.KeepaliveInterval {
+				continue
+			} else if !m.opts.KeepalivePermitWithoutStream && !m.activeStreams() {
+				continue
+			}
+
+			sent = time.Now().UnixNano()
+			if err := m.writeConnPacket(drpcwire.KindPing, nil); err != nil {
+				m.terminate(managerClosed.Wrap(unavailable(err)))
+				return
+			}
+
+			timer = time.NewTimer(timeout)
+			timeoutCh = timer.C
+
+		case <-timeoutCh:
+			timeoutCh = nil
+
+			// any packet read after the ping was sent is good enough.
+			if m.last.Load() < sent {
+				m.log("KEEPALIVE", func() string { return "timed out" })
+				m.terminate(managerClosed.Wrap(drpc.ClosedError.Wrap(unavailable(errs.New("keepalive timed out")))))
+				return
+			}
+		}
+	}
+}
+
+// writeConnPacket writes and flushes a connection level packet of the given
+// kind and body.
+func (m *Manager) writeConnPacket(kind drpcwire.Kind, data []byte) error {
+	m.wmu.Lock()
+	defer m.wmu.Unlock()
+
+	m.cid++
+	fr := drpcwire.Frame{
+		ID:      drpcwire.ID{Stream: 0, Message: m.cid},
+		Kind:    kind,
+		Data:    data,
+		Done:    true,
+		Control: true,
+	}
+
+	m.log("SEND", fr.String)
+
+	if err := m.wr.WriteFrame(fr); err != nil {
+		return err
+	}
+	return m.wr.Flush()
+}
+
 //
 // exported interface
 //
@@ -398,6 +908,9 @@ func (m *Manager) Closed() <-chan struct{} {
 // the return result is only valid until the next call to NewClientStream or
 // NewServerStream.
 func (m *Manager) Unblocked() <-chan struct{} {
+	if m.multiplexed() {
+		return closedCh
+	}
 	if prev := m.sbuf.Get(); prev != nil {
 		return prev.Context().Done()
 	}
@@ -406,27 +919,64 @@ func (m *Manager) Unblocked() <-chan struct{} {
 
 // Close closes the transport the manager is using.
 func (m *Manager) Close() error {
-	m.terminate(managerClosed.New("Close called"))
+	m.terminate(managerClosed.Wrap(unavailable(errs.New("Close called"))))
 
 	m.sigs.stream.Wait()
 	m.sigs.read.Wait()
+	m.sigs.keep.Wait()
 	m.sigs.tport.Wait()
 
 	return m.sigs.tport.Err()
 }
 
+// GoAway tells the remote that it must not start any new streams. Streams that
+// the remote invoked before the go away are still returned by NewServerStream,
+// and once all of them have been, NewServerStream returns an error. Active
+// streams are unaffected. The remote must be using a version of drpc that
+// understands go away packets, as older versions treat them as a protocol
+// error and close the transport.
+func (m *Manager) GoAway() error {
+	m.gmu.Lock()
+	if m.sigs.drain.IsSet() {
+		m.gmu.Unlock()
+		return nil
+	}
+	last := m.invoked
+	m.drainID = last
+	m.sigs.drain.Set(managerClosed.Wrap(unavailable(errs.New("go away sent"))))
+	m.gmu.Unlock()
+
+	m.log("GOAWAY", func() string { return fmt.Sprint(last) })
+
+	return m.writeConnPacket(drpcwire.KindGoAway, drpcwire.AppendVarint(nil, last))
+}
+
 // NewClientStream starts a stream on the managed transport for use by a client.
+// It returns an error if the remote has sent a go away.
 func (m *Manager) NewClientStream(ctx context.Context, rpc string) (stream *drpcstream.Stream, err error) {
+	if err, ok := m.sigs.away.Get(); ok {
+		return nil, err
+	}
 	if err := m.acquireSemaphore(ctx); err != nil {
 		return nil, err
 	}
 
-	return m.newStream(ctx, m.sbuf.Get().ID()+1, "cli", rpc)
+	if m.multiplexed() {
+		q, ok := m.sbuf.NewQueue()
+		if !ok {
+			m.sem.Recv()
+			return nil, m.sigs.term.Err()
+		}
+		return m.newMultiplexedStream(ctx, nil, q, "cli", rpc, m.clientAdvertised()), nil
+	}
+
+	return m.newStream(ctx, nil, m.sbuf.Get().ID()+1, "cli", rpc, m.clientAdvertised())
 }
 
 // NewServerStream starts a stream on the managed transport for use by a server.
 // It does this by waiting for the client to issue an invoke message and
-// returning the details.
+// returning the details. After GoAway has been called, it returns an error
+// once every stream invoked before the go away has been returned.
 func (m *Manager) NewServerStream(ctx context.Context) (stream *drpcstream.Stream, rpc string, err error) {
 	if err := m.acquireSemaphore(ctx); err != nil {
 		return nil, "", err
@@ -434,10 +984,20 @@ func (m *Manager) NewServerStream(ctx context.Context) (stream *drpcstream.Strea
 	defer func() {
 		if err != nil {
 			m.sem.Recv()
+		} else {
+			m.recordAccepted(stream.ID())
 		}
 	}()
 
-	var meta map[string]string
+	if m.drained() {
+		return nil, "", m.sigs.drain.Err()
+	}
+
+	if m.multiplexed() {
+		return m.acceptStream(ctx)
+	}
+
+	var meta drpcmetadata.MD
 	var metaID uint64
 	var timeoutCh <-chan time.Time
 
@@ -448,6 +1008,8 @@ func (m *Manager) NewServerStream(ctx context.Context) (stream *drpcstream.Strea
 		timeoutCh = timer.C
 	}
 
+	drainCh := m.sigs.drain.Signal()
+
 	for {
 		select {
 		case <-timeoutCh:
@@ -459,12 +1021,18 @@ func (m *Manager) NewServerStream(ctx context.Context) (stream *drpcstream.Strea
 		case <-m.sigs.term.Signal():
 			return nil, "", m.sigs.term.Err()
 
+		case <-drainCh:
+			if m.drained() {
+				return nil, "", m.sigs.drain.Err()
+			}
+			drainCh = nil
+
 		case pkt := <-m.pkts:
 			switch pkt.Kind {
 			// keep track of any metadata being sent before an invoke so that we
 			// can include it if the stream id matches the eventual invoke.
 			case drpcwire.KindInvokeMetadata:
-				meta, err = drpcmetadata.Decode(pkt.Data)
+				meta, err = drpcmetadata.DecodeMD(pkt.Data)
 				m.pdone.Send()
 
 				if err != nil {
@@ -476,11 +1044,15 @@ func (m *Manager) NewServerStream(ctx context.Context) (stream *drpcstream.Strea
 				rpc = string(pkt.Data)
 				m.pdone.Send()
 
-				if metaID == pkt.ID.Stream {
-					ctx = drpcmetadata.AddPairs(ctx, meta)
+				if metaID != pkt.ID.Stream {
+					meta = nil
 				}
 
-				stream, err := m.newStream(ctx, pkt.ID.Stream, "srv", rpc)
+				ctx, cancel, adv := serverContext(ctx, meta)
+				stream, err := m.newStream(ctx, cancel, pkt.ID.Stream, "srv", rpc, adv)
+				if err != nil {
+					cancel()
+				}
 				return stream, rpc, err
 
 			default:
@@ -491,6 +1063,60 @@ func (m *Manager) NewServerStream(ctx context.Context) (stream *drpcstream.Strea
 	}
 }
 
+// serverContext attaches the invoke metadata to the context of a new server
+// stream, both as incoming metadata and, with the last value for each key, as
+// metadata added by drpcmetadata.Add for handlers that use drpcmetadata.Get.
+// If the metadata carries a timeout from the client, it is removed and applied
+// to the context, and the returned cancel function must be called once the
+// stream is finished. If it carries the compressor or the flow control window
+// the client is using, they are removed and returned so the stream can use
+// them too.
+func serverContext(ctx context.Context, meta drpcmetadata.MD) (_ context.Context, _ context.CancelFunc, adv advertised) {
+	cancel := func() {}
+	if values := meta[drpcmetadata.CompressionKey]; len(values) > 0 {
+		delete(meta, drpcmetadata.CompressionKey)
+		adv.comp = values[len(values)-1]
+	}
+	if values := meta[drpcmetadata.WindowKey]; len(values) > 0 {
+		delete(meta, drpcmetadata.WindowKey)
+		adv.window, _ = drpcmetadata.DecodeWindow(values[len(values)-1])
+	}
+	if values := meta[drpcmetadata.TimeoutKey]; len(values) > 0 {
+		delete(meta, drpcmetadata.TimeoutKey)
+		if timeout, ok := drpcmetadata.DecodeTimeout(values[len(values)-1]); ok {
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+		}
+	}
+	if len(meta) > 0 {
+		ctx = drpcmetadata.NewIncomingContext(ctx, meta)
+		ctx = drpcmetadata.AddPairs(ctx, meta.Flatten())
+	}
+	return ctx, cancel, adv
+}
+
+// advertised contains the settings a client advertises in the invoke metadata
+// of a stream.
+type advertised struct {
+	comp   string // name of the compressor
+	window int    // size of the flow control window
+}
+
+// clientAdvertised returns the settings that client streams advertise.
+func (m *Manager) clientAdvertised() advertised {
+	return advertised{comp: m.opts.Stream.Compression}
+}
+
+// recordAccepted keeps track of the largest stream id accepted by
+// NewServerStream so that it knows when it has been drained.
+func (m *Manager) recordAccepted(sid uint64) {
+	for {
+		accepted := m.accepted.Load()
+		if sid <= accepted || m.accepted.CompareAndSwap(accepted, sid) {
+			return
+		}
+	}
+}
+
 func isConnectionReset(err error) bool {
 	var operr *net.OpError
 	if !errors.As(err, &operr) {
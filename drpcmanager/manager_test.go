@@ -14,6 +14,10 @@ import (
 
 	"github.com/zeebo/assert"
 
+	"storj.io/drpc"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcmetadata"
+	"storj.io/drpc/drpcstream"
 	"storj.io/drpc/drpctest"
 	"storj.io/drpc/drpcwire"
 )
@@ -172,3 +176,613 @@ func (b *blockedTransport) wait(p int, rw *bool) (int, error) {
 func (b *blockedTransport) Read(p []byte) (n int, err error)  { return b.wait(len(p), &b.ro) }
 func (b *blockedTransport) Write(p []byte) (n int, err error) { return b.wait(len(p), &b.wo) }
 func (b *blockedTransport) Close() error                      { return nil }
+
+func TestMultiplexed_Interleaved(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	cconn, sconn := net.Pipe()
+	defer func() { _ = cconn.Close() }()
+	defer func() { _ = sconn.Close() }()
+
+	opts := Options{MaxConcurrentStreams: 4}
+
+	cman := NewWithOptions(cconn, opts)
+	defer func() { _ = cman.Close() }()
+
+	sman := NewWithOptions(sconn, opts)
+	defer func() { _ = sman.Close() }()
+
+	const streams, messages = 4, 100
+
+	for i := 0; i < streams; i++ {
+		ctx.Run(func(ctx context.Context) {
+			stream, err := cman.NewClientStream(ctx, "rpc")
+			assert.NoError(t, err)
+			defer func() { _ = stream.Close() }()
+
+			assert.NoError(t, stream.RawWrite(drpcwire.KindInvoke, []byte("rpc")))
+			for j := 0; j < messages; j++ {
+				assert.NoError(t, stream.RawWrite(drpcwire.KindMessage, []byte{byte(j)}))
+				assert.NoError(t, stream.RawFlush())
+			}
+			assert.NoError(t, stream.CloseSend())
+
+			for j := 0; j < messages; j++ {
+				data, err := stream.RawRecv()
+				assert.NoError(t, err)
+				assert.DeepEqual(t, data, []byte{byte(j)})
+			}
+			_, err = stream.RawRecv()
+			assert.That(t, errors.Is(err, io.EOF))
+		})
+	}
+
+	for i := 0; i < streams; i++ {
+		stream, rpc, err := sman.NewServerStream(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, rpc, "rpc")
+
+		ctx.Run(func(ctx context.Context) {
+			defer func() { _ = stream.Close() }()
+
+			for {
+				data, err := stream.RawRecv()
+				if errors.Is(err, io.EOF) {
+					return
+				}
+				assert.NoError(t, err)
+				assert.NoError(t, stream.RawWrite(drpcwire.KindMessage, data))
+				assert.NoError(t, stream.RawFlush())
+			}
+		})
+	}
+
+	ctx.Wait()
+}
+
+func TestMultiplexed_CancelOne(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	cconn, sconn := net.Pipe()
+	defer func() { _ = cconn.Close() }()
+	defer func() { _ = sconn.Close() }()
+
+	opts := Options{MaxConcurrentStreams: 2}
+
+	cman := NewWithOptions(cconn, opts)
+	defer func() { _ = cman.Close() }()
+
+	sman := NewWithOptions(sconn, opts)
+	defer func() { _ = sman.Close() }()
+
+	subctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	canceled, err := cman.NewClientStream(subctx, "canceled")
+	assert.NoError(t, err)
+	assert.NoError(t, canceled.RawWrite(drpcwire.KindInvoke, []byte("canceled")))
+	assert.NoError(t, canceled.RawFlush())
+
+	live, err := cman.NewClientStream(ctx, "live")
+	assert.NoError(t, err)
+	defer func() { _ = live.Close() }()
+	assert.NoError(t, live.RawWrite(drpcwire.KindInvoke, []byte("live")))
+	assert.NoError(t, live.RawFlush())
+
+	sstream1, rpc, err := sman.NewServerStream(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, rpc, "canceled")
+
+	sstream2, rpc, err := sman.NewServerStream(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, rpc, "live")
+
+	// canceling the first stream should only cancel it on the remote side.
+	cancel()
+	<-canceled.Finished()
+	<-sstream1.Context().Done()
+
+	// the transport is still usable by the other stream.
+	assert.That(t, !closed(cman.Closed()))
+	assert.NoError(t, live.RawWrite(drpcwire.KindMessage, []byte("hello")))
+	assert.NoError(t, live.RawFlush())
+
+	data, err := sstream2.RawRecv()
+	assert.NoError(t, err)
+	assert.DeepEqual(t, data, []byte("hello"))
+
+	// a new stream can take the place of the canceled one.
+	next, err := cman.NewClientStream(ctx, "next")
+	assert.NoError(t, err)
+	defer func() { _ = next.Close() }()
+	assert.NoError(t, next.RawWrite(drpcwire.KindInvoke, []byte("next")))
+	assert.NoError(t, next.RawFlush())
+
+	sstream3, rpc, err := sman.NewServerStream(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, rpc, "next")
+	_ = sstream3.Close()
+}
+
+func TestMultiplexed_OutOfOrder(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	cconn, sconn := net.Pipe()
+	defer func() { _ = cconn.Close() }()
+	defer func() { _ = sconn.Close() }()
+
+	opts := Options{MaxConcurrentStreams: 2}
+
+	cman := NewWithOptions(cconn, opts)
+	defer func() { _ = cman.Close() }()
+
+	sman := NewWithOptions(sconn, opts)
+	defer func() { _ = sman.Close() }()
+
+	first, err := cman.NewClientStream(ctx, "first")
+	assert.NoError(t, err)
+	defer func() { _ = first.Close() }()
+
+	second, err := cman.NewClientStream(ctx, "second")
+	assert.NoError(t, err)
+	defer func() { _ = second.Close() }()
+
+	// the invoke for the second stream id is written before the first.
+	assert.NoError(t, second.RawWrite(drpcwire.KindInvoke, []byte("second")))
+	assert.NoError(t, second.RawFlush())
+
+	sstream, rpc, err := sman.NewServerStream(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, rpc, "second")
+	defer func() { _ = sstream.Close() }()
+
+	assert.NoError(t, first.RawWrite(drpcwire.KindInvoke, []byte("first")))
+	assert.NoError(t, first.RawWrite(drpcwire.KindMessage, []byte("hello")))
+	assert.NoError(t, first.RawFlush())
+
+	sstream, rpc, err = sman.NewServerStream(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, rpc, "first")
+	defer func() { _ = sstream.Close() }()
+
+	data, err := sstream.RawRecv()
+	assert.NoError(t, err)
+	assert.DeepEqual(t, data, []byte("hello"))
+}
+
+func TestMultiplexed_FlowControl(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	cconn, sconn := net.Pipe()
+	defer func() { _ = cconn.Close() }()
+	defer func() { _ = sconn.Close() }()
+
+	opts := Options{
+		MaxConcurrentStreams: 2,
+		Stream:               drpcstream.Options{InitialWindowSize: 100},
+	}
+
+	cman := NewWithOptions(cconn, opts)
+	defer func() { _ = cman.Close() }()
+
+	sman := NewWithOptions(sconn, opts)
+	defer func() { _ = sman.Close() }()
+
+	// invoke starts a stream that advertises its window and waits for the
+	// server to respond with its own.
+	invoke := func(rpc string) (*drpcstream.Stream, *drpcstream.Stream) {
+		stream, err := cman.NewClientStream(ctx, rpc)
+		assert.NoError(t, err)
+		assert.NoError(t, stream.RawWrite(drpcwire.KindInvokeMetadata, drpcmetadata.EncodeWindow(nil, 100)))
+		assert.NoError(t, stream.RawWrite(drpcwire.KindInvoke, []byte(rpc)))
+		assert.NoError(t, stream.RawFlush())
+
+		sstream, _, err := sman.NewServerStream(ctx)
+		assert.NoError(t, err)
+		assert.NoError(t, sstream.RawWrite(drpcwire.KindMessage, []byte("ready")))
+		assert.NoError(t, sstream.RawFlush())
+		_, err = stream.RawRecv()
+		assert.NoError(t, err)
+
+		return stream, sstream
+	}
+
+	slow, sslow := invoke("slow")
+	defer func() { _ = slow.Close() }()
+	defer func() { _ = sslow.Close() }()
+
+	// the slow stream can send an entire window without being read.
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, slow.RawWrite(drpcwire.KindMessage, make([]byte, 10)))
+		assert.NoError(t, slow.RawFlush())
+	}
+
+	// but the next message has to wait for the server to read.
+	sent := make(chan error, 1)
+	ctx.Run(func(ctx context.Context) {
+		sent <- slow.RawWrite(drpcwire.KindMessage, make([]byte, 10))
+	})
+
+	// meanwhile, other streams are unaffected.
+	fast, sfast := invoke("fast")
+	defer func() { _ = fast.Close() }()
+	defer func() { _ = sfast.Close() }()
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, fast.RawWrite(drpcwire.KindMessage, make([]byte, 10)))
+		assert.NoError(t, fast.RawFlush())
+	}
+	for i := 0; i < 10; i++ {
+		_, err := sfast.RawRecv()
+		assert.NoError(t, err)
+	}
+
+	select {
+	case err := <-sent:
+		t.Fatal("send did not wait for the window:", err)
+	default:
+	}
+
+	// reading from the slow stream grants it more credit.
+	for i := 0; i < 5; i++ {
+		_, err := sslow.RawRecv()
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, <-sent)
+}
+
+func TestMultiplexed_FlowControlExceeded(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	cconn, sconn := net.Pipe()
+	defer func() { _ = cconn.Close() }()
+	defer func() { _ = sconn.Close() }()
+
+	// the client does not use flow control itself, so it ignores the window.
+	cman := NewWithOptions(cconn, Options{MaxConcurrentStreams: 2})
+	defer func() { _ = cman.Close() }()
+
+	sman := NewWithOptions(sconn, Options{
+		MaxConcurrentStreams: 2,
+		Stream:               drpcstream.Options{InitialWindowSize: 100},
+	})
+	defer func() { _ = sman.Close() }()
+
+	stream, err := cman.NewClientStream(ctx, "rpc")
+	assert.NoError(t, err)
+	defer func() { _ = stream.Close() }()
+	assert.NoError(t, stream.RawWrite(drpcwire.KindInvokeMetadata, drpcmetadata.EncodeWindow(nil, 100)))
+	assert.NoError(t, stream.RawWrite(drpcwire.KindInvoke, []byte("rpc")))
+	assert.NoError(t, stream.RawFlush())
+
+	sstream, _, err := sman.NewServerStream(ctx)
+	assert.NoError(t, err)
+	defer func() { _ = sstream.Close() }()
+
+	// claim to be using the window and then send more than it allows.
+	update := drpcwire.AppendVarint(drpcwire.AppendVarint(nil, 0), 0)
+	assert.NoError(t, stream.RawWrite(drpcwire.KindWindowUpdate, update))
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, stream.RawWrite(drpcwire.KindMessage, make([]byte, 60)))
+		assert.NoError(t, stream.RawFlush())
+	}
+
+	<-sstream.Terminated()
+	_, err = sstream.RawRecv()
+	assert.Equal(t, drpcerr.Code(err), uint64(drpcerr.ResourceExhausted))
+
+	// only the stream is failed and not the transport.
+	assert.That(t, !closed(sman.Closed()))
+}
+
+func TestMultiplexed_RefusesStreams(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	cconn, sconn := net.Pipe()
+	defer func() { _ = cconn.Close() }()
+	defer func() { _ = sconn.Close() }()
+
+	cman := NewWithOptions(cconn, Options{MaxConcurrentStreams: 4})
+	defer func() { _ = cman.Close() }()
+
+	sman := NewWithOptions(sconn, Options{MaxConcurrentStreams: 2})
+	defer func() { _ = sman.Close() }()
+
+	invoke := func(rpc string) *drpcstream.Stream {
+		stream, err := cman.NewClientStream(ctx, rpc)
+		assert.NoError(t, err)
+		assert.NoError(t, stream.RawWrite(drpcwire.KindInvoke, []byte(rpc)))
+		assert.NoError(t, stream.RawFlush())
+		return stream
+	}
+
+	first := invoke("first")
+	defer func() { _ = first.Close() }()
+	second := invoke("second")
+	defer func() { _ = second.Close() }()
+
+	// the server only allows two streams, even if they are not accepted yet.
+	refused := invoke("refused")
+	defer func() { _ = refused.Close() }()
+	_, err := refused.RawRecv()
+	assert.Equal(t, drpcerr.Code(err), uint64(drpcerr.Unavailable))
+
+	sfirst, rpc, err := sman.NewServerStream(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, rpc, "first")
+
+	// once a stream is finished, another may take its place.
+	assert.NoError(t, sfirst.Close())
+	_, err = first.RawRecv()
+	assert.Error(t, err)
+
+	next := invoke("next")
+	defer func() { _ = next.Close() }()
+
+	for _, exp := range []string{"second", "next"} {
+		sstream, rpc, err := sman.NewServerStream(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, rpc, exp)
+		_ = sstream.Close()
+	}
+}
+
+func TestMultiplexed_MaxQueuedSize(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	cconn, sconn := net.Pipe()
+	defer func() { _ = cconn.Close() }()
+	defer func() { _ = sconn.Close() }()
+
+	cman := NewWithOptions(cconn, Options{MaxConcurrentStreams: 2})
+	defer func() { _ = cman.Close() }()
+
+	sman := NewWithOptions(sconn, Options{MaxConcurrentStreams: 2, MaxQueuedSize: 100})
+	defer func() { _ = sman.Close() }()
+
+	stream, err := cman.NewClientStream(ctx, "rpc")
+	assert.NoError(t, err)
+	defer func() { _ = stream.Close() }()
+	assert.NoError(t, stream.RawWrite(drpcwire.KindInvoke, []byte("rpc")))
+	assert.NoError(t, stream.RawFlush())
+
+	sstream, _, err := sman.NewServerStream(ctx)
+	assert.NoError(t, err)
+	defer func() { _ = sstream.Close() }()
+
+	// the server does not read, so the messages are queued until there are
+	// too many of them. the error may arrive before the last one is sent.
+	for i := 0; i < 3; i++ {
+		_ = stream.RawWrite(drpcwire.KindMessage, make([]byte, 60))
+		_ = stream.RawFlush()
+	}
+
+	_, err = stream.RawRecv()
+	assert.Equal(t, drpcerr.Code(err), uint64(drpcerr.ResourceExhausted))
+	<-sstream.Terminated()
+
+	// only the stream is failed and not the transport.
+	assert.That(t, !closed(sman.Closed()))
+	assert.That(t, !closed(cman.Closed()))
+}
+
+func TestKeepalive_Timeout(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	cconn, sconn := net.Pipe()
+	defer func() { _ = cconn.Close() }()
+	defer func() { _ = sconn.Close() }()
+
+	// the remote reads everything but never responds.
+	ctx.Run(func(ctx context.Context) { _, _ = io.Copy(io.Discard, sconn) })
+
+	man := NewWithOptions(cconn, Options{
+		KeepaliveInterval:            time.Millisecond,
+		KeepaliveTimeout:             10 * time.Millisecond,
+		KeepalivePermitWithoutStream: true,
+	})
+	defer func() { _ = man.Close() }()
+
+	<-man.Closed()
+	assert.That(t, drpc.ClosedError.Has(man.sigs.term.Err()))
+	assert.Equal(t, drpcerr.Code(man.sigs.term.Err()), drpcerr.Unavailable)
+}
+
+func TestKeepalive_Responds(t *testing.T) {
+	run := func(t *testing.T, opts Options) {
+		ctx := drpctest.NewTracker(t)
+		defer ctx.Close()
+
+		cconn, sconn := net.Pipe()
+		defer func() { _ = cconn.Close() }()
+		defer func() { _ = sconn.Close() }()
+
+		opts.KeepaliveInterval = time.Millisecond
+		opts.KeepaliveTimeout = 50 * time.Millisecond
+		opts.KeepalivePermitWithoutStream = true
+
+		cman := NewWithOptions(cconn, opts)
+		defer func() { _ = cman.Close() }()
+
+		// the remote only responds to pings because of its default options.
+		sman := NewWithOptions(sconn, Options{MaxConcurrentStreams: opts.MaxConcurrentStreams})
+		defer func() { _ = sman.Close() }()
+
+		// pings must not interfere with streams.
+		ctx.Run(func(ctx context.Context) {
+			stream, _, err := sman.NewServerStream(ctx)
+			assert.NoError(t, err)
+			defer func() { _ = stream.Close() }()
+
+			for i := 0; i < 100; i++ {
+				data, err := stream.RawRecv()
+				assert.NoError(t, err)
+				assert.NoError(t, stream.RawWrite(drpcwire.KindMessage, data))
+				assert.NoError(t, stream.RawFlush())
+			}
+		})
+
+		stream, err := cman.NewClientStream(ctx, "rpc")
+		assert.NoError(t, err)
+		assert.NoError(t, stream.RawWrite(drpcwire.KindInvoke, []byte("rpc")))
+		for i := 0; i < 100; i++ {
+			assert.NoError(t, stream.RawWrite(drpcwire.KindMessage, []byte{byte(i)}))
+			assert.NoError(t, stream.RawFlush())
+
+			data, err := stream.RawRecv()
+			assert.NoError(t, err)
+			assert.DeepEqual(t, data, []byte{byte(i)})
+			time.Sleep(time.Millisecond / 10)
+		}
+		assert.NoError(t, stream.Close())
+		ctx.Wait()
+
+		// stay idle for a while.
+		time.Sleep(100 * time.Millisecond)
+		assert.That(t, !closed(cman.Closed()))
+		assert.That(t, !closed(sman.Closed()))
+	}
+
+	t.Run("Single", func(t *testing.T) { run(t, Options{}) })
+	t.Run("Multiplexed", func(t *testing.T) { run(t, Options{MaxConcurrentStreams: 2}) })
+}
+
+func TestErrorCodes(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	cconn, sconn := net.Pipe()
+	defer func() { _ = sconn.Close() }()
+
+	man := New(cconn)
+
+	// a canceled context is reported as canceled.
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err := man.NewClientStream(cctx, "rpc")
+	assert.Equal(t, drpcerr.Code(err), drpcerr.Canceled)
+
+	// a closed manager is reported as unavailable.
+	assert.NoError(t, man.Close())
+	_, err = man.NewClientStream(ctx, "rpc")
+	assert.Error(t, err)
+	assert.Equal(t, drpcerr.Code(err), drpcerr.Unavailable)
+}
+
+func TestGoAway(t *testing.T) {
+	run := func(t *testing.T, opts Options) {
+		ctx := drpctest.NewTracker(t)
+		defer ctx.Close()
+
+		cconn, sconn := net.Pipe()
+		defer func() { _ = cconn.Close() }()
+		defer func() { _ = sconn.Close() }()
+
+		cman := NewWithOptions(cconn, opts)
+		defer func() { _ = cman.Close() }()
+
+		sman := NewWithOptions(sconn, opts)
+		defer func() { _ = sman.Close() }()
+
+		ctx.Run(func(ctx context.Context) {
+			stream, _, err := sman.NewServerStream(ctx)
+			assert.NoError(t, err)
+			defer func() { _ = stream.Close() }()
+
+			// the go away is sent while the stream is active.
+			assert.NoError(t, sman.GoAway())
+
+			data, err := stream.RawRecv()
+			assert.NoError(t, err)
+			assert.NoError(t, stream.RawWrite(drpcwire.KindMessage, data))
+			assert.NoError(t, stream.RawFlush())
+		})
+
+		stream, err := cman.NewClientStream(ctx, "rpc")
+		assert.NoError(t, err)
+		assert.NoError(t, stream.RawWrite(drpcwire.KindInvoke, []byte("rpc")))
+		assert.NoError(t, stream.RawWrite(drpcwire.KindMessage, []byte("data")))
+		assert.NoError(t, stream.RawFlush())
+
+		// the active stream is unaffected.
+		data, err := stream.RawRecv()
+		assert.NoError(t, err)
+		assert.DeepEqual(t, data, []byte("data"))
+		ctx.Wait()
+
+		// the go away was read before the response, so no new streams start.
+		_, err = cman.NewClientStream(ctx, "rpc")
+		assert.That(t, drpc.ClosedError.Has(err))
+		assert.Equal(t, drpcerr.Code(err), drpcerr.Unavailable)
+
+		// the server has no more streams to accept.
+		_, _, err = sman.NewServerStream(ctx)
+		assert.Error(t, err)
+
+		// the client closes the transport once its streams are finished.
+		assert.NoError(t, stream.Close())
+		<-cman.Closed()
+	}
+
+	t.Run("Single", func(t *testing.T) { run(t, Options{}) })
+	t.Run("Multiplexed", func(t *testing.T) { run(t, Options{MaxConcurrentStreams: 2}) })
+}
+
+func TestDeadlinePropagation(t *testing.T) {
+	run := func(t *testing.T, opts Options) {
+		ctx := drpctest.NewTracker(t)
+		defer ctx.Close()
+
+		cconn, sconn := net.Pipe()
+		defer func() { _ = cconn.Close() }()
+		defer func() { _ = sconn.Close() }()
+
+		cman := NewWithOptions(cconn, opts)
+		defer func() { _ = cman.Close() }()
+
+		sman := NewWithOptions(sconn, opts)
+		defer func() { _ = sman.Close() }()
+
+		ctx.Run(func(ctx context.Context) {
+			stream, err := cman.NewClientStream(ctx, "rpc")
+			assert.NoError(t, err)
+
+			metadata, err := drpcmetadata.Encode(nil, map[string]string{"foo": "bar"})
+			assert.NoError(t, err)
+			metadata = drpcmetadata.EncodeTimeout(metadata, 50*time.Millisecond)
+
+			assert.NoError(t, stream.RawWrite(drpcwire.KindInvokeMetadata, metadata))
+			assert.NoError(t, stream.RawWrite(drpcwire.KindInvoke, []byte("rpc")))
+			assert.NoError(t, stream.RawFlush())
+		})
+
+		stream, _, err := sman.NewServerStream(ctx)
+		assert.NoError(t, err)
+
+		// the timeout is applied to the stream and hidden from the handler.
+		deadline, ok := stream.Context().Deadline()
+		assert.That(t, ok)
+		assert.That(t, time.Until(deadline) <= 50*time.Millisecond)
+
+		metadata, ok := drpcmetadata.Get(stream.Context())
+		assert.That(t, ok)
+		assert.Equal(t, metadata, map[string]string{"foo": "bar"})
+
+		incoming, ok := drpcmetadata.FromIncomingContext(stream.Context())
+		assert.That(t, ok)
+		assert.DeepEqual(t, incoming, drpcmetadata.MD{"foo": {"bar"}})
+
+		<-stream.Context().Done()
+		assert.Equal(t, stream.Context().Err(), context.DeadlineExceeded)
+	}
+
+	t.Run("Single", func(t *testing.T) { run(t, Options{}) })
+	t.Run("Multiplexed", func(t *testing.T) { run(t, Options{MaxConcurrentStreams: 2}) })
+}
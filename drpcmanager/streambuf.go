@@ -7,7 +7,9 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"storj.io/drpc/drpcerr"
 	"storj.io/drpc/drpcstream"
+	"storj.io/drpc/drpcwire"
 )
 
 type streamBuffer struct {
@@ -15,10 +17,24 @@ type streamBuffer struct {
 	cond   sync.Cond
 	stream atomic.Pointer[drpcstream.Stream]
 	closed bool
+
+	// the following fields are only used when streams are multiplexed.
+	limit    int                     // largest number of live streams the remote may invoke
+	queued   int                     // largest amount of packet data queued for a stream
+	last     uint64                  // largest stream id created or seen
+	skipped  map[uint64]struct{}     // stream ids below last that were not seen
+	queues   map[uint64]*streamQueue // packet queues for the active streams
+	incoming []*streamQueue          // invoked streams that are not accepted
+	notify   chan struct{}           // signaled when incoming grows
 }
 
-func (sb *streamBuffer) init() {
+func (sb *streamBuffer) init(limit, queued int) {
 	sb.cond.L = &sb.mu
+	sb.limit = limit
+	sb.queued = queued
+	sb.queues = make(map[uint64]*streamQueue)
+	sb.skipped = make(map[uint64]struct{})
+	sb.notify = make(chan struct{}, 1)
 }
 
 func (sb *streamBuffer) Close() {
@@ -27,6 +43,12 @@ func (sb *streamBuffer) Close() {
 
 	sb.closed = true
 	sb.cond.Broadcast()
+
+	for sid, q := range sb.queues {
+		q.Close()
+		delete(sb.queues, sid)
+	}
+	sb.incoming = nil
 }
 
 func (sb *streamBuffer) Get() *drpcstream.Stream {
@@ -55,3 +77,213 @@ func (sb *streamBuffer) Wait(sid uint64) bool {
 
 	return !sb.closed
 }
+
+//
+// multiplexed streams
+//
+
+// NewQueue allocates the next stream id and a queue for packets sent to it. It
+// returns false if the buffer is closed.
+func (sb *streamBuffer) NewQueue() (*streamQueue, bool) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	if sb.closed {
+		return nil, false
+	}
+
+	sb.last++
+	q := newStreamQueue(sb.last)
+	q.announced = true // locally created streams are never accepted
+	sb.queues[q.sid] = q
+	return q, true
+}
+
+// maxSkipped is how far below the largest stream id seen a stream id may be and
+// still be invoked. A remote allocates stream ids before it writes the invokes
+// for them, so concurrent streams can have their invokes arrive out of order.
+const maxSkipped = 1024
+
+// Deliver queues the packet for the stream it is addressed to. Invoke packets
+// for a stream id that has not been seen before create a new queue, and the
+// stream is announced as incoming once the Invoke itself arrives. Packets for
+// any other stream are dropped. Window updates are not queued behind messages:
+// if the stream exists, it is returned so that the caller can handle the
+// packet immediately. Messages that exceed the flow control window of the
+// stream are dropped and fail the stream.
+//
+// If it returns an error, the stream must be failed with it: the stream is
+// returned if it exists, and otherwise, the remote has to be sent the error
+// directly. That happens when the remote invokes more live streams than the
+// limit, or when it sends more packet data than may be queued for a stream.
+func (sb *streamBuffer) Deliver(pkt drpcwire.Packet) (*drpcstream.Stream, error) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	if sb.closed {
+		return nil, nil
+	}
+
+	q := sb.queues[pkt.ID.Stream]
+	if q == nil {
+		if pkt.Kind != drpcwire.KindInvoke && pkt.Kind != drpcwire.KindInvokeMetadata {
+			return nil, nil
+		} else if !sb.see(pkt.ID.Stream) {
+			return nil, nil
+		} else if sb.live() >= sb.limit {
+			return nil, drpcerr.New(drpcerr.Unavailable, "too many concurrent streams")
+		}
+
+		q = newStreamQueue(pkt.ID.Stream)
+		q.remote = true
+		sb.queues[q.sid] = q
+	}
+
+	switch pkt.Kind {
+	case drpcwire.KindWindowUpdate:
+		if stream := q.Stream(); stream != nil {
+			return stream, nil
+		}
+
+	case drpcwire.KindMessage, drpcwire.KindCompressedMessage:
+		// the flow control window limits what may be queued for the stream,
+		// so it is checked as messages arrive.
+		if stream := q.Stream(); stream != nil && !stream.CheckWindow(len(pkt.Data)) {
+			return nil, nil
+		}
+
+	case drpcwire.KindCloseSend, drpcwire.KindClose, drpcwire.KindCancel, drpcwire.KindError:
+		q.done = true
+	}
+
+	if !q.Put(pkt, sb.queued) {
+		q.done = true
+		stream := q.Stream()
+		if stream == nil {
+			sb.removeLocked(q)
+		}
+		return stream, drpcerr.New(drpcerr.ResourceExhausted, "too much data queued for the stream")
+	}
+
+	if pkt.Kind == drpcwire.KindInvoke && !q.announced {
+		q.announced = true
+		sb.incoming = append(sb.incoming, q)
+
+		select {
+		case sb.notify <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil, nil
+}
+
+// live returns the number of streams invoked by the remote that it may still
+// send messages on. It must be called while holding the mutex.
+func (sb *streamBuffer) live() (n int) {
+	for _, q := range sb.queues {
+		if !q.remote || q.done {
+			continue
+		} else if stream := q.Stream(); stream != nil && stream.IsTerminated() {
+			continue
+		}
+		n++
+	}
+	return n
+}
+
+// removeLocked closes and forgets the queue, including if it has been
+// announced but not accepted. It must be called while holding the mutex.
+func (sb *streamBuffer) removeLocked(q *streamQueue) {
+	q.Close()
+	delete(sb.queues, q.sid)
+	for i, inc := range sb.incoming {
+		if inc == q {
+			sb.incoming = append(sb.incoming[:i], sb.incoming[i+1:]...)
+			break
+		}
+	}
+}
+
+// see records that the stream id has been invoked and returns false if it was
+// seen before or is too old to be invoked.
+func (sb *streamBuffer) see(sid uint64) bool {
+	if sid <= sb.last {
+		if _, ok := sb.skipped[sid]; !ok {
+			return false
+		}
+		delete(sb.skipped, sid)
+		return true
+	}
+
+	// remember the ids that were skipped over so that their invokes are still
+	// accepted if they arrive later, and forget the ones that are too old.
+	from := sb.last + 1
+	if sid-from > maxSkipped {
+		from = sid - maxSkipped
+	}
+	for skipped := from; skipped < sid; skipped++ {
+		sb.skipped[skipped] = struct{}{}
+	}
+	sb.last = sid
+
+	if len(sb.skipped) > maxSkipped {
+		for skipped := range sb.skipped {
+			if sid-skipped > maxSkipped {
+				delete(sb.skipped, skipped)
+			}
+		}
+	}
+	return true
+}
+
+// Incoming returns the oldest invoked stream that has not been accepted yet.
+func (sb *streamBuffer) Incoming() (*streamQueue, bool) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	if len(sb.incoming) == 0 {
+		return nil, false
+	}
+
+	q := sb.incoming[0]
+	sb.incoming[0] = nil
+	sb.incoming = sb.incoming[1:]
+	return q, true
+}
+
+// Active returns the number of streams with a queue.
+func (sb *streamBuffer) Active() int {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	return len(sb.queues)
+}
+
+// Streams returns the streams that have been created for the active queues.
+func (sb *streamBuffer) Streams() []*drpcstream.Stream {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	streams := make([]*drpcstream.Stream, 0, len(sb.queues))
+	for _, q := range sb.queues {
+		if stream := q.Stream(); stream != nil {
+			streams = append(streams, stream)
+		}
+	}
+	return streams
+}
+
+// Notify returns a channel that is signaled when a stream is announced.
+func (sb *streamBuffer) Notify() <-chan struct{} { return sb.notify }
+
+// Remove closes and forgets the queue for the stream id.
+func (sb *streamBuffer) Remove(sid uint64) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	if q := sb.queues[sid]; q != nil {
+		q.Close()
+		delete(sb.queues, sid)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmanager
+
+import (
+	"sync"
+
+	"storj.io/drpc/drpcstream"
+	"storj.io/drpc/drpcwire"
+)
+
+// streamQueue buffers the packets for a single multiplexed stream so that the
+// goroutine reading from the transport never has to wait for a slow consumer.
+type streamQueue struct {
+	sid       uint64
+	mu        sync.Mutex
+	cond      sync.Cond
+	pkts      []drpcwire.Packet
+	size      int // amount of packet data queued
+	closed    bool
+	stream    *drpcstream.Stream
+	announced bool // protected by the streamBuffer mutex
+	remote    bool // set if the remote created the stream, protected by the streamBuffer mutex
+	done      bool // set when the remote will send no more messages, protected by the streamBuffer mutex
+}
+
+func newStreamQueue(sid uint64) *streamQueue {
+	q := &streamQueue{sid: sid}
+	q.cond.L = &q.mu
+	return q
+}
+
+// Put appends the packet to the queue. It never blocks. It returns false if
+// the packet would cause more than max bytes of packet data to be queued, in
+// which case the queue is closed.
+func (q *streamQueue) Put(pkt drpcwire.Packet, max int) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return true
+	}
+
+	if q.size+len(pkt.Data) > max {
+		q.closeLocked()
+		return false
+	}
+
+	q.pkts = append(q.pkts, pkt)
+	q.size += len(pkt.Data)
+	q.cond.Broadcast()
+	return true
+}
+
+// Get blocks until a packet is available and returns it. It returns false if
+// the queue has been closed, discarding any packets still queued.
+func (q *streamQueue) Get() (drpcwire.Packet, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.pkts) == 0 && !q.closed {
+		q.cond.Wait()
+	}
+	if q.closed {
+		return drpcwire.Packet{}, false
+	}
+
+	pkt := q.pkts[0]
+	q.pkts[0] = drpcwire.Packet{}
+	q.pkts = q.pkts[1:]
+	q.size -= len(pkt.Data)
+	return pkt, true
+}
+
+// SetStream records the stream that the queue is delivering packets to.
+func (q *streamQueue) SetStream(stream *drpcstream.Stream) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.stream = stream
+}
+
+// Stream returns the stream that the queue is delivering packets to, if any.
+func (q *streamQueue) Stream() *drpcstream.Stream {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.stream
+}
+
+// Close causes any current or future calls to Get to return false.
+func (q *streamQueue) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.closeLocked()
+}
+
+// closeLocked is like Close but must be called while holding the mutex.
+func (q *streamQueue) closeLocked() {
+	q.closed = true
+	q.pkts = nil
+	q.size = 0
+	q.cond.Broadcast()
+}
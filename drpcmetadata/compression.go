@@ -0,0 +1,18 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmetadata
+
+// CompressionKey is the reserved metadata key used to carry the name of the
+// compressor used by a stream. Clients add it to the invoke metadata and
+// servers remove it, compressing the messages they send with the same
+// compressor. Servers that have the compressor add it to the response header
+// to confirm it, and clients remove it, only compressing the messages they
+// send once it has been confirmed.
+const CompressionKey = "drpc-compression"
+
+// EncodeCompression appends the compressor name under CompressionKey onto the
+// passed in buffer of encoded metadata.
+func EncodeCompression(buf []byte, name string) []byte {
+	return appendEntry(buf, CompressionKey, name)
+}
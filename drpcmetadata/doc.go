@@ -2,4 +2,11 @@
 // See LICENSE for copying information.
 
 // Package drpcmetadata define the structure of the metadata supported by drpc library.
+//
+// Metadata added to a context with Add or AddPairs is sent on rpcs made with
+// the context, and servers add the metadata they receive the same way, so it
+// is also sent on any rpcs the handler makes with its context. The MD type
+// supports multiple values per key, and keeps the metadata a server received
+// separate from the metadata a client sends with NewIncomingContext and
+// NewOutgoingContext.
 package drpcmetadata
@@ -0,0 +1,217 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmetadata
+
+import (
+	"context"
+	"strings"
+
+	"github.com/zeebo/errs"
+)
+
+// BinarySuffix is the suffix of keys whose values hold arbitrary bytes, like
+// serialized trace contexts or protobuf messages. Values for other keys should
+// be printable so that they can be carried by text protocols like HTTP. The
+// drpc wire format carries every value as is, and text protocols encode the
+// values of binary keys with base64.
+const BinarySuffix = "-bin"
+
+// IsBinaryKey returns true if the values of the key hold arbitrary bytes.
+func IsBinaryKey(key string) bool { return strings.HasSuffix(key, BinarySuffix) }
+
+// MD is metadata that maps keys to any number of values. Keys are case
+// sensitive, like the rest of drpc metadata.
+type MD map[string][]string
+
+// New returns an MD with a single value for each key in the map.
+func New(m map[string]string) MD {
+	md := make(MD, len(m))
+	for key, value := range m {
+		md[key] = []string{value}
+	}
+	return md
+}
+
+// Pairs returns an MD from alternating keys and values. Values for the same
+// key are appended in order. It panics if there are an odd number of strings.
+func Pairs(kv ...string) MD {
+	if len(kv)%2 == 1 {
+		panic("drpcmetadata: Pairs got an odd number of strings")
+	}
+	md := make(MD, len(kv)/2)
+	for i := 0; i < len(kv); i += 2 {
+		md[kv[i]] = append(md[kv[i]], kv[i+1])
+	}
+	return md
+}
+
+// Join returns an MD with the values for every key of all of the mds appended
+// in order.
+func Join(mds ...MD) MD {
+	out := make(MD)
+	for _, md := range mds {
+		for key, values := range md {
+			out[key] = append(out[key], values...)
+		}
+	}
+	return out
+}
+
+// Len returns the number of keys in the metadata.
+func (md MD) Len() int { return len(md) }
+
+// Get returns the values for the key.
+func (md MD) Get(key string) []string { return md[key] }
+
+// Set replaces the values for the key. If there are no values, the key is
+// deleted.
+func (md MD) Set(key string, values ...string) {
+	if len(values) == 0 {
+		delete(md, key)
+		return
+	}
+	md[key] = append([]string(nil), values...)
+}
+
+// Append adds the values to the end of the values for the key.
+func (md MD) Append(key string, values ...string) {
+	if len(values) == 0 {
+		return
+	}
+	md[key] = append(md[key], values...)
+}
+
+// Delete removes the key and all of its values.
+func (md MD) Delete(key string) { delete(md, key) }
+
+// Copy returns a deep copy of the metadata.
+func (md MD) Copy() MD {
+	out := make(MD, len(md))
+	for key, values := range md {
+		out[key] = append([]string(nil), values...)
+	}
+	return out
+}
+
+// Flatten returns a map with the last value for every key, which is what
+// remotes that only support a single value per key receive.
+func (md MD) Flatten() map[string]string {
+	out := make(map[string]string, len(md))
+	for key, values := range md {
+		if len(values) > 0 {
+			out[key] = values[len(values)-1]
+		}
+	}
+	return out
+}
+
+// EncodeMD generates the byte form of the metadata and appends it onto the
+// passed in buffer. Every value is encoded as a separate entry in the same
+// format as Encode, so remotes that only support a single value per key
+// receive the last value.
+func EncodeMD(buf []byte, md MD) ([]byte, error) {
+	for key, values := range md {
+		for _, value := range values {
+			buf = appendEntry(buf, key, value)
+		}
+	}
+	return buf, nil
+}
+
+// DecodeMD translates the byte form of metadata into an MD, keeping every
+// value for keys that were sent more than once.
+func DecodeMD(buf []byte) (MD, error) {
+	var out MD
+	var key, value []byte
+	var ok bool
+	var err error
+
+	for len(buf) > 0 {
+		buf, key, value, ok, err = readEntry(buf)
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, errs.New("invalid data")
+		}
+		if out == nil {
+			out = make(MD)
+		}
+		out[string(key)] = append(out[string(key)], string(value))
+	}
+
+	return out, nil
+}
+
+//
+// incoming and outgoing contexts
+//
+
+type incomingKey struct{}
+
+type outgoingKey struct{}
+
+// NewIncomingContext returns a context with the metadata received by a
+// server. It is used by transports and does not need to be called by
+// handlers.
+func NewIncomingContext(ctx context.Context, md MD) context.Context {
+	return context.WithValue(ctx, incomingKey{}, md)
+}
+
+// FromIncomingContext returns a copy of the metadata received by a server and
+// a bool indicating if it existed. Unlike the metadata added by Add, it is
+// never sent on rpcs made with the context.
+func FromIncomingContext(ctx context.Context) (MD, bool) {
+	md, ok := ctx.Value(incomingKey{}).(MD)
+	if !ok {
+		return nil, false
+	}
+	return md.Copy(), true
+}
+
+// ValueFromIncomingContext returns the values for the key in the metadata
+// received by a server without copying all of it.
+func ValueFromIncomingContext(ctx context.Context, key string) []string {
+	md, _ := ctx.Value(incomingKey{}).(MD)
+	return append([]string(nil), md[key]...)
+}
+
+// NewOutgoingContext returns a context with the metadata to send on rpcs made
+// with it, replacing any previous outgoing metadata.
+func NewOutgoingContext(ctx context.Context, md MD) context.Context {
+	return context.WithValue(ctx, outgoingKey{}, md)
+}
+
+// AppendToOutgoingContext returns a context with the alternating keys and
+// values appended to its outgoing metadata. The metadata of the parent context
+// is not modified. It panics if there are an odd number of strings.
+func AppendToOutgoingContext(ctx context.Context, kv ...string) context.Context {
+	md, _ := ctx.Value(outgoingKey{}).(MD)
+	return NewOutgoingContext(ctx, Join(md, Pairs(kv...)))
+}
+
+// FromOutgoingContext returns a copy of the metadata to send on rpcs made with
+// the context and a bool indicating if it existed.
+func FromOutgoingContext(ctx context.Context) (MD, bool) {
+	md, ok := ctx.Value(outgoingKey{}).(MD)
+	if !ok {
+		return nil, false
+	}
+	return md.Copy(), true
+}
+
+// EncodeOutgoing appends the byte form of the metadata to send on rpcs made
+// with the context onto the passed in buffer: the metadata added by Add, then
+// the outgoing metadata.
+func EncodeOutgoing(ctx context.Context, buf []byte) ([]byte, error) {
+	if md, ok := Get(ctx); ok {
+		var err error
+		if buf, err = Encode(buf, md); err != nil {
+			return nil, err
+		}
+	}
+	if md, ok := ctx.Value(outgoingKey{}).(MD); ok {
+		return EncodeMD(buf, md)
+	}
+	return buf, nil
+}
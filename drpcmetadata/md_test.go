@@ -0,0 +1,93 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmetadata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zeebo/assert"
+)
+
+func TestMD(t *testing.T) {
+	md := Pairs("a", "1", "b", "2", "a", "3")
+	assert.DeepEqual(t, md, MD{"a": {"1", "3"}, "b": {"2"}})
+	assert.Equal(t, md.Len(), 2)
+
+	md.Append("b", "4")
+	md.Set("c", "5", "6")
+	assert.DeepEqual(t, md.Get("b"), []string{"2", "4"})
+	assert.DeepEqual(t, md.Get("c"), []string{"5", "6"})
+
+	cp := md.Copy()
+	md.Delete("a")
+	md.Set("b")
+	cp.Append("c", "7")
+	assert.DeepEqual(t, md, MD{"c": {"5", "6"}})
+	assert.DeepEqual(t, cp, MD{"a": {"1", "3"}, "b": {"2", "4"}, "c": {"5", "6", "7"}})
+
+	assert.DeepEqual(t, cp.Flatten(), map[string]string{"a": "3", "b": "4", "c": "7"})
+	assert.DeepEqual(t, Join(New(map[string]string{"a": "0"}), cp), MD{
+		"a": {"0", "1", "3"}, "b": {"2", "4"}, "c": {"5", "6", "7"},
+	})
+
+	assert.That(t, IsBinaryKey("trace-bin"))
+	assert.That(t, !IsBinaryKey("trace"))
+}
+
+func TestEncodeMD(t *testing.T) {
+	md := MD{"multi": {"a", "b"}, "trace-bin": {"\x00\xff"}}
+	data, err := EncodeMD(nil, md)
+	assert.NoError(t, err)
+
+	decoded, err := DecodeMD(data)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, decoded, md)
+
+	// the format is compatible with the single value format in both
+	// directions, with the last value winning.
+	legacy, err := Decode(data)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, legacy, map[string]string{"multi": "b", "trace-bin": "\x00\xff"})
+
+	data, err = Encode(nil, map[string]string{"key": "value"})
+	assert.NoError(t, err)
+	decoded, err = DecodeMD(data)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, decoded, MD{"key": {"value"}})
+
+	_, err = DecodeMD([]byte{1, 2, 3})
+	assert.Error(t, err)
+}
+
+func TestIncomingOutgoing(t *testing.T) {
+	ctx := context.Background()
+
+	// incoming metadata is not sent on rpcs made with the context.
+	ctx = NewIncomingContext(ctx, Pairs("in", "1"))
+	data, err := EncodeOutgoing(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, len(data), 0)
+
+	in, ok := FromIncomingContext(ctx)
+	assert.That(t, ok)
+	assert.DeepEqual(t, in, MD{"in": {"1"}})
+	assert.DeepEqual(t, ValueFromIncomingContext(ctx, "in"), []string{"1"})
+
+	// appending to the outgoing metadata does not modify the parent.
+	parent := AppendToOutgoingContext(ctx, "out", "1")
+	child := AppendToOutgoingContext(parent, "out", "2")
+
+	out, _ := FromOutgoingContext(parent)
+	assert.DeepEqual(t, out, MD{"out": {"1"}})
+	out, _ = FromOutgoingContext(child)
+	assert.DeepEqual(t, out, MD{"out": {"1", "2"}})
+
+	// the outgoing metadata is sent along with the metadata from Add.
+	data, err = EncodeOutgoing(Add(child, "added", "x"), nil)
+	assert.NoError(t, err)
+	decoded, err := DecodeMD(data)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, decoded, MD{"added": {"x"}, "out": {"1", "2"}})
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmetadata
+
+import (
+	"context"
+
+	"github.com/zeebo/errs"
+)
+
+// ResponseWriter sends metadata from a server back to the client in response
+// to an rpc. Header metadata is sent before the first response message, and
+// trailer metadata is sent when the rpc ends.
+type ResponseWriter interface {
+	// SetHeader adds the metadata to the header. It returns an error if the
+	// header has already been sent.
+	SetHeader(md map[string]string) error
+
+	// SetTrailer adds the metadata to the trailer. It returns an error if the
+	// trailer has already been sent.
+	SetTrailer(md map[string]string) error
+}
+
+// ResponseWriterKey is used to store the ResponseWriter of an rpc with the
+// context handlers are called with.
+type ResponseWriterKey struct{}
+
+// WithResponseWriter associates the ResponseWriter with the context.
+func WithResponseWriter(ctx context.Context, rw ResponseWriter) context.Context {
+	return context.WithValue(ctx, ResponseWriterKey{}, rw)
+}
+
+// SetHeader adds the metadata to the header sent in response to the rpc the
+// context is for.
+func SetHeader(ctx context.Context, md map[string]string) error {
+	rw, ok := ctx.Value(ResponseWriterKey{}).(ResponseWriter)
+	if !ok {
+		return errs.New("no response writer for context")
+	}
+	return rw.SetHeader(md)
+}
+
+// SetTrailer adds the metadata to the trailer sent in response to the rpc the
+// context is for.
+func SetTrailer(ctx context.Context, md map[string]string) error {
+	rw, ok := ctx.Value(ResponseWriterKey{}).(ResponseWriter)
+	if !ok {
+		return errs.New("no response writer for context")
+	}
+	return rw.SetTrailer(md)
+}
+
+// Response is the metadata a server sent in response to an rpc.
+type Response struct {
+	// Header is the header metadata sent before the first response message.
+	Header map[string]string
+
+	// Trailer is the trailer metadata sent when the rpc ended.
+	Trailer map[string]string
+}
+
+type responseKey struct{}
+
+// WithResponse returns a context that, when used to invoke a unitary rpc,
+// causes the metadata the server sends in response to be stored into resp
+// once the rpc is done. Streams return the metadata from their Header and
+// Trailer methods instead.
+func WithResponse(ctx context.Context, resp *Response) context.Context {
+	return context.WithValue(ctx, responseKey{}, resp)
+}
+
+// GetResponse returns the Response associated with the context by WithResponse
+// and a bool indicating if it existed.
+func GetResponse(ctx context.Context) (*Response, bool) {
+	resp, ok := ctx.Value(responseKey{}).(*Response)
+	return resp, ok
+}
@@ -0,0 +1,30 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmetadata
+
+import (
+	"strconv"
+	"time"
+)
+
+// TimeoutKey is the reserved metadata key used to carry the amount of time
+// remaining before the client's deadline. Clients add it to the invoke
+// metadata and servers remove it, applying the timeout to the stream context.
+const TimeoutKey = "drpc-timeout"
+
+// EncodeTimeout appends the timeout under TimeoutKey onto the passed in buffer
+// of encoded metadata.
+func EncodeTimeout(buf []byte, timeout time.Duration) []byte {
+	return appendEntry(buf, TimeoutKey, strconv.FormatInt(int64(timeout), 10))
+}
+
+// DecodeTimeout parses the value stored under TimeoutKey. It returns false if
+// the value is not a valid timeout.
+func DecodeTimeout(value string) (time.Duration, bool) {
+	timeout, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || timeout <= 0 {
+		return 0, false
+	}
+	return time.Duration(timeout), true
+}
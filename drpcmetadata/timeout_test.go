@@ -0,0 +1,30 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmetadata
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zeebo/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	buf, err := Encode(nil, map[string]string{"foo": "bar"})
+	assert.NoError(t, err)
+	buf = EncodeTimeout(buf, 1500*time.Millisecond)
+
+	metadata, err := Decode(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, metadata["foo"], "bar")
+
+	timeout, ok := DecodeTimeout(metadata[TimeoutKey])
+	assert.That(t, ok)
+	assert.Equal(t, timeout, 1500*time.Millisecond)
+
+	for _, value := range []string{"", "abc", "0", "-5"} {
+		_, ok := DecodeTimeout(value)
+		assert.That(t, !ok)
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmetadata
+
+import "strconv"
+
+// WindowKey is the reserved metadata key used to carry the flow control window
+// of a client stream. Clients add it to the invoke metadata and servers remove
+// it, limiting the message data they send to the window. A server only uses
+// flow control with clients that advertise a window.
+const WindowKey = "drpc-window"
+
+// EncodeWindow appends the window size under WindowKey onto the passed in
+// buffer of encoded metadata.
+func EncodeWindow(buf []byte, size int) []byte {
+	return appendEntry(buf, WindowKey, strconv.Itoa(size))
+}
+
+// DecodeWindow parses the value stored under WindowKey. It returns false if
+// the value is not a valid window size.
+func DecodeWindow(value string) (int, bool) {
+	size, err := strconv.Atoi(value)
+	if err != nil || size <= 0 {
+		return 0, false
+	}
+	return size, true
+}
@@ -0,0 +1,24 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmetadata
+
+import (
+	"testing"
+
+	"github.com/zeebo/assert"
+)
+
+func TestWindow(t *testing.T) {
+	metadata, err := Decode(EncodeWindow(nil, 65536))
+	assert.NoError(t, err)
+
+	size, ok := DecodeWindow(metadata[WindowKey])
+	assert.That(t, ok)
+	assert.Equal(t, size, 65536)
+
+	for _, value := range []string{"", "abc", "0", "-5"} {
+		_, ok := DecodeWindow(value)
+		assert.That(t, !ok)
+	}
+}
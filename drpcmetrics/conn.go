@@ -0,0 +1,54 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmetrics
+
+import (
+	"net"
+	"sync"
+)
+
+// WrapListener returns a net.Listener that records the connections accepted
+// by the listener as opened on the side, and records them as closed when they
+// are closed. Wrap the listener before any TLS listener so that servers can
+// still find the TLS state of the connections.
+func WrapListener(lis net.Listener, side Side, rec Recorder) net.Listener {
+	return &listener{Listener: lis, side: side, rec: rec}
+}
+
+// listener wraps the connections it accepts.
+type listener struct {
+	net.Listener
+	side Side
+	rec  Recorder
+}
+
+// Accept accepts a connection and records it as opened.
+func (l *listener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return WrapConn(conn, l.side, l.rec), nil
+}
+
+// WrapConn records the connection as opened on the side and returns a
+// net.Conn that records it as closed the first time it is closed.
+func WrapConn(conn net.Conn, side Side, rec Recorder) net.Conn {
+	rec.ConnOpened(side)
+	return &recordedConn{Conn: conn, side: side, rec: rec}
+}
+
+// recordedConn records when it is closed.
+type recordedConn struct {
+	net.Conn
+	side Side
+	rec  Recorder
+	once sync.Once
+}
+
+// Close closes the connection and records it as closed.
+func (c *recordedConn) Close() error {
+	c.once.Do(func() { c.rec.ConnClosed(c.side) })
+	return c.Conn.Close()
+}
@@ -0,0 +1,27 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package drpcmetrics records metrics about rpcs, connections and pools.
+//
+// Metrics are sent to a Recorder, which is an interface so that any metrics
+// system can be plugged in. An Interceptor records the calls, latencies,
+// in flight rpcs and messages of a server or client, WrapListener and WrapConn
+// record the connections, and a Recorder can be used as the Recorder of a
+// drpcpool.Pool to record its cache hits, misses and evictions.
+//
+// Prometheus is a Recorder that keeps the metrics in memory and serves them
+// in the Prometheus text format as an http.Handler:
+//
+//	metrics := drpcmetrics.NewPrometheus()
+//	ints := drpcmetrics.NewInterceptor(metrics)
+//
+//	mux := drpcmux.NewWithInterceptors(
+//		[]drpcmux.UnaryServerInterceptor{ints.UnaryServerInterceptor},
+//		[]drpcmux.StreamServerInterceptor{ints.StreamServerInterceptor},
+//	)
+//	srv := drpcserver.NewWithOptions(mux, drpcserver.Options{CollectStats: true})
+//	metrics.AddStats(drpcmetrics.Server, srv.Stats)
+//
+//	go func() { _ = srv.Serve(ctx, drpcmetrics.WrapListener(lis, drpcmetrics.Server, metrics)) }()
+//	go func() { _ = http.ListenAndServe(":9090", metrics) }()
+package drpcmetrics
@@ -0,0 +1,143 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmetrics
+
+import (
+	"context"
+	"time"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcclient"
+	"storj.io/drpc/drpcmux"
+)
+
+// Interceptor records the rpcs of a server or client with a Recorder. Its
+// server interceptors are added to a drpcmux.Mux and its client interceptors
+// are added to a drpcclient.ClientConn.
+type Interceptor struct {
+	rec Recorder
+}
+
+// NewInterceptor returns an Interceptor that records with the Recorder.
+func NewInterceptor(rec Recorder) *Interceptor {
+	return &Interceptor{rec: rec}
+}
+
+// UnaryServerInterceptor is a drpcmux.UnaryServerInterceptor that records the
+// unary rpcs served.
+func (i *Interceptor) UnaryServerInterceptor(ctx context.Context, req interface{}, rpc string, handler drpcmux.UnaryHandler) (out interface{}, err error) {
+	start := i.start(Server, rpc)
+	defer func() { i.finish(Server, rpc, start, err, out) }()
+
+	// the request has already been received by the mux.
+	i.rec.MessageReceived(Server, rpc)
+	return handler(ctx, req)
+}
+
+// StreamServerInterceptor is a drpcmux.StreamServerInterceptor that records
+// the streaming rpcs served.
+func (i *Interceptor) StreamServerInterceptor(stream drpc.Stream, rpc string, handler drpcmux.StreamHandler) (out interface{}, err error) {
+	start := i.start(Server, rpc)
+	defer func() { i.finish(Server, rpc, start, err, out) }()
+
+	return handler(&serverStream{Stream: stream, rec: i.rec, rpc: rpc})
+}
+
+// UnaryClientInterceptor is a drpcclient.UnaryClientInterceptor that records
+// the unary rpcs invoked.
+func (i *Interceptor) UnaryClientInterceptor(ctx context.Context, rpc string, enc drpc.Encoding, in, out drpc.Message, cc *drpcclient.ClientConn, next drpcclient.UnaryInvoker) (err error) {
+	start := i.start(Client, rpc)
+	defer func() { i.rec.RPCFinished(Client, rpc, Code(err), time.Since(start)) }()
+
+	err = next(ctx, rpc, enc, in, out, cc)
+	if err == nil {
+		i.rec.MessageSent(Client, rpc)
+		i.rec.MessageReceived(Client, rpc)
+	}
+	return err
+}
+
+// StreamClientInterceptor is a drpcclient.StreamClientInterceptor that
+// records the streaming rpcs invoked. A stream finishes when receiving from it
+// fails, which is with io.EOF if it was successful, or when its context is done
+// otherwise.
+func (i *Interceptor) StreamClientInterceptor(ctx context.Context, rpc string, enc drpc.Encoding, cc *drpcclient.ClientConn, streamer drpcclient.Streamer) (drpc.Stream, error) {
+	start := i.start(Client, rpc)
+
+	stream, err := streamer(ctx, rpc, enc, cc)
+	if err != nil {
+		i.rec.RPCFinished(Client, rpc, Code(err), time.Since(start))
+		return nil, err
+	}
+
+	cs := &clientStream{Stream: stream, rec: i.rec, rpc: rpc}
+	return drpcclient.OnFinish(cs, func(err error) {
+		i.rec.RPCFinished(Client, rpc, Code(err), time.Since(start))
+	}), nil
+}
+
+// start records that the rpc started, returning when it did.
+func (i *Interceptor) start(side Side, rpc string) time.Time {
+	i.rec.RPCStarted(side, rpc)
+	return time.Now()
+}
+
+// finish records that a server rpc finished with the error, and that the
+// mux will send its output if it has one.
+func (i *Interceptor) finish(side Side, rpc string, start time.Time, err error, out interface{}) {
+	if err == nil && drpcmux.SendsOutput(out) {
+		i.rec.MessageSent(side, rpc)
+	}
+	i.rec.RPCFinished(side, rpc, Code(err), time.Since(start))
+}
+
+//
+// streams
+//
+
+// serverStream counts the messages on a stream being served.
+type serverStream struct {
+	drpc.Stream
+	rec Recorder
+	rpc string
+}
+
+func (s *serverStream) MsgSend(msg drpc.Message, enc drpc.Encoding) error {
+	err := s.Stream.MsgSend(msg, enc)
+	if err == nil {
+		s.rec.MessageSent(Server, s.rpc)
+	}
+	return err
+}
+
+func (s *serverStream) MsgRecv(msg drpc.Message, enc drpc.Encoding) error {
+	err := s.Stream.MsgRecv(msg, enc)
+	if err == nil {
+		s.rec.MessageReceived(Server, s.rpc)
+	}
+	return err
+}
+
+// clientStream counts the messages on a stream being invoked.
+type clientStream struct {
+	drpc.Stream
+	rec Recorder
+	rpc string
+}
+
+func (s *clientStream) MsgSend(msg drpc.Message, enc drpc.Encoding) error {
+	err := s.Stream.MsgSend(msg, enc)
+	if err == nil {
+		s.rec.MessageSent(Client, s.rpc)
+	}
+	return err
+}
+
+func (s *clientStream) MsgRecv(msg drpc.Message, enc drpc.Encoding) error {
+	err := s.Stream.MsgRecv(msg, enc)
+	if err == nil {
+		s.rec.MessageReceived(Client, s.rpc)
+	}
+	return err
+}
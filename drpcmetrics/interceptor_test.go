@@ -0,0 +1,172 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmetrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/zeebo/assert"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcclient"
+	"storj.io/drpc/drpcconn"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcmux"
+	"storj.io/drpc/drpcserver"
+	"storj.io/drpc/drpctest"
+)
+
+type stringEncoding struct{}
+
+func (stringEncoding) Marshal(msg drpc.Message) ([]byte, error) {
+	return []byte(*msg.(*string)), nil
+}
+
+func (stringEncoding) Unmarshal(buf []byte, msg drpc.Message) error {
+	*msg.(*string) = string(buf)
+	return nil
+}
+
+type testServer interface {
+	Unary(ctx context.Context, in *string) (*string, error)
+	Stream(stream drpc.Stream) error
+}
+
+type testDescription struct{}
+
+func (testDescription) NumMethods() int { return 2 }
+
+func (testDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver, interface{}, bool) {
+	switch n {
+	case 0:
+		return "/test/Unary", stringEncoding{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(testServer).Unary(ctx, in1.(*string))
+			}, testServer.Unary, true
+	case 1:
+		return "/test/Stream", stringEncoding{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return nil, srv.(testServer).Stream(in1.(drpc.Stream))
+			}, testServer.Stream, true
+	default:
+		return "", nil, nil, nil, false
+	}
+}
+
+// echoServer echoes messages, failing for the "fail" message.
+type echoServer struct{}
+
+func (echoServer) Unary(ctx context.Context, in *string) (*string, error) {
+	if *in == "fail" {
+		return nil, drpcerr.New(drpcerr.NotFound, "not found")
+	}
+	return in, nil
+}
+
+func (echoServer) Stream(stream drpc.Stream) error {
+	for {
+		var msg string
+		if err := stream.MsgRecv(&msg, stringEncoding{}); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		} else if msg == "fail" {
+			return drpcerr.New(drpcerr.Internal, "internal")
+		}
+		if err := stream.MsgSend(&msg, stringEncoding{}); err != nil {
+			return err
+		}
+	}
+}
+
+func TestInterceptor(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	metrics := NewPrometheus()
+	ints := NewInterceptor(metrics)
+
+	mux := drpcmux.NewWithInterceptors(
+		[]drpcmux.UnaryServerInterceptor{ints.UnaryServerInterceptor},
+		[]drpcmux.StreamServerInterceptor{ints.StreamServerInterceptor},
+	)
+	assert.NoError(t, mux.Register(echoServer{}, testDescription{}))
+
+	pc, ps := net.Pipe()
+	srv := drpcserver.New(mux)
+	ctx.Run(func(ctx context.Context) { _ = srv.ServeOne(ctx, WrapConn(ps, Server, metrics)) })
+
+	conn := drpcconn.New(WrapConn(pc, Client, metrics))
+	cc, err := drpcclient.NewClientConnWithOptions(ctx, conn,
+		drpcclient.WithChainUnaryInterceptor(ints.UnaryClientInterceptor),
+		drpcclient.WithChainStreamInterceptor(ints.StreamClientInterceptor),
+	)
+	assert.NoError(t, err)
+
+	// a successful and a failed unary rpc.
+	in, out := "hello", ""
+	assert.NoError(t, cc.Invoke(ctx, "/test/Unary", stringEncoding{}, &in, &out))
+	in = "fail"
+	assert.Error(t, cc.Invoke(ctx, "/test/Unary", stringEncoding{}, &in, &out))
+
+	// a successful stream.
+	stream, err := cc.NewStream(ctx, "/test/Stream", stringEncoding{})
+	assert.NoError(t, err)
+	for _, msg := range []string{"a", "b"} {
+		assert.NoError(t, stream.MsgSend(&msg, stringEncoding{}))
+		assert.NoError(t, stream.MsgRecv(&out, stringEncoding{}))
+	}
+	assert.NoError(t, stream.CloseSend())
+	assert.That(t, errors.Is(stream.MsgRecv(&out, stringEncoding{}), io.EOF))
+
+	// a failed stream.
+	stream, err = cc.NewStream(ctx, "/test/Stream", stringEncoding{})
+	assert.NoError(t, err)
+	in = "fail"
+	assert.NoError(t, stream.MsgSend(&in, stringEncoding{}))
+	err = stream.MsgRecv(&out, stringEncoding{})
+	assert.Equal(t, drpcerr.Code(err), drpcerr.Internal)
+
+	assert.NoError(t, conn.Close())
+
+	var buf bytes.Buffer
+	assert.NoError(t, metrics.Write(&buf))
+	output := buf.String()
+
+	for _, line := range []string{
+		`drpc_rpcs_started_total{side="client",rpc="/test/Unary"} 2`,
+		`drpc_rpcs_started_total{side="server",rpc="/test/Stream"} 2`,
+		`drpc_rpcs_handled_total{side="client",rpc="/test/Unary",code="OK"} 1`,
+		`drpc_rpcs_handled_total{side="client",rpc="/test/Unary",code="NotFound"} 1`,
+		`drpc_rpcs_handled_total{side="client",rpc="/test/Stream",code="OK"} 1`,
+		`drpc_rpcs_handled_total{side="client",rpc="/test/Stream",code="Internal"} 1`,
+		`drpc_rpcs_handled_total{side="server",rpc="/test/Unary",code="OK"} 1`,
+		`drpc_rpcs_handled_total{side="server",rpc="/test/Unary",code="NotFound"} 1`,
+		`drpc_rpcs_handled_total{side="server",rpc="/test/Stream",code="OK"} 1`,
+		`drpc_rpcs_handled_total{side="server",rpc="/test/Stream",code="Internal"} 1`,
+		`drpc_rpcs_in_flight{side="client",rpc="/test/Stream"} 0`,
+		`drpc_rpcs_in_flight{side="server",rpc="/test/Unary"} 0`,
+		`drpc_rpc_duration_seconds_count{side="client",rpc="/test/Stream"} 2`,
+		`drpc_rpc_duration_seconds_count{side="server",rpc="/test/Unary"} 2`,
+		`drpc_messages_sent_total{side="client",rpc="/test/Stream"} 3`,
+		`drpc_messages_received_total{side="client",rpc="/test/Stream"} 2`,
+		`drpc_messages_sent_total{side="server",rpc="/test/Stream"} 2`,
+		`drpc_messages_received_total{side="server",rpc="/test/Stream"} 3`,
+		`drpc_messages_sent_total{side="server",rpc="/test/Unary"} 1`,
+		`drpc_messages_received_total{side="server",rpc="/test/Unary"} 2`,
+		`drpc_connections_opened_total{side="client"} 1`,
+		`drpc_connections_opened_total{side="server"} 1`,
+		`drpc_connections_open{side="client"} 0`,
+	} {
+		if !strings.Contains(output, line+"\n") {
+			t.Errorf("missing %q in output:\n%s", line, output)
+		}
+	}
+}
@@ -0,0 +1,380 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmetrics
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"storj.io/drpc/drpcstats"
+)
+
+// DefaultBuckets are the upper bounds in seconds of the buckets of the rpc
+// latency histograms when none are provided.
+var DefaultBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// PrometheusOptions controls configuration settings for a Prometheus.
+type PrometheusOptions struct {
+	// Namespace is the prefix of the names of the metrics. If empty, "drpc"
+	// is used.
+	Namespace string
+
+	// Buckets are the upper bounds in seconds of the buckets of the rpc
+	// latency histograms, in increasing order. If nil, DefaultBuckets is
+	// used.
+	Buckets []float64
+}
+
+// Prometheus is a Recorder that keeps the metrics in memory and serves them in
+// the Prometheus text exposition format. It has these metrics, where the side
+// label is "server" or "client":
+//
+//	drpc_rpcs_started_total{side,rpc}         counter
+//	drpc_rpcs_handled_total{side,rpc,code}    counter
+//	drpc_rpcs_in_flight{side,rpc}             gauge
+//	drpc_rpc_duration_seconds{side,rpc}       histogram
+//	drpc_messages_sent_total{side,rpc}        counter
+//	drpc_messages_received_total{side,rpc}    counter
+//	drpc_connections_opened_total{side}       counter
+//	drpc_connections_open{side}               gauge
+//	drpc_pool_hits_total                      counter
+//	drpc_pool_misses_total                    counter
+//	drpc_pool_evictions_total                 counter
+//	drpc_bytes_read_total{side,rpc}           counter
+//	drpc_bytes_written_total{side,rpc}        counter
+//
+// The bytes metrics are read from the stats added with AddStats every time
+// the metrics are written.
+type Prometheus struct {
+	opts PrometheusOptions
+
+	mu       sync.Mutex
+	families []*family
+	sources  []statsSource
+
+	started     *family
+	handled     *family
+	inFlight    *family
+	duration    *family
+	sent        *family
+	received    *family
+	connsOpened *family
+	connsOpen   *family
+	poolHits    *family
+	poolMisses  *family
+	poolEvicted *family
+}
+
+var _ Recorder = (*Prometheus)(nil)
+
+// statsSource is a function that returns stats for the rpcs on a side.
+type statsSource struct {
+	side  Side
+	stats func() map[string]drpcstats.Stats
+}
+
+// NewPrometheus returns a Prometheus with default options.
+func NewPrometheus() *Prometheus {
+	return NewPrometheusWithOptions(PrometheusOptions{})
+}
+
+// NewPrometheusWithOptions returns a Prometheus with the options.
+func NewPrometheusWithOptions(opts PrometheusOptions) *Prometheus {
+	if opts.Namespace == "" {
+		opts.Namespace = "drpc"
+	}
+	if opts.Buckets == nil {
+		opts.Buckets = DefaultBuckets
+	}
+
+	p := &Prometheus{opts: opts}
+	p.started = p.newFamily("rpcs_started_total", "counter", "Number of rpcs started.")
+	p.handled = p.newFamily("rpcs_handled_total", "counter", "Number of rpcs finished, by result code.")
+	p.inFlight = p.newFamily("rpcs_in_flight", "gauge", "Number of rpcs started but not finished.")
+	p.duration = p.newFamily("rpc_duration_seconds", "histogram", "Latency of finished rpcs in seconds.")
+	p.sent = p.newFamily("messages_sent_total", "counter", "Number of messages sent on rpcs.")
+	p.received = p.newFamily("messages_received_total", "counter", "Number of messages received on rpcs.")
+	p.connsOpened = p.newFamily("connections_opened_total", "counter", "Number of connections opened.")
+	p.connsOpen = p.newFamily("connections_open", "gauge", "Number of connections open.")
+	p.poolHits = p.newFamily("pool_hits_total", "counter", "Number of connections taken from a pool.")
+	p.poolMisses = p.newFamily("pool_misses_total", "counter", "Number of times a pool had no connection to take.")
+	p.poolEvicted = p.newFamily("pool_evictions_total", "counter", "Number of connections removed from a pool without being taken.")
+	return p
+}
+
+// newFamily adds a family of metrics with the name prefixed by the namespace.
+func (p *Prometheus) newFamily(name, typ, help string) *family {
+	f := &family{
+		name:   p.opts.Namespace + "_" + name,
+		typ:    typ,
+		help:   help,
+		series: make(map[string]*series),
+	}
+	p.families = append(p.families, f)
+	return f
+}
+
+// AddStats adds a function that returns the stats for the rpcs on the side,
+// like the Stats method of a drpcserver.Server or drpcconn.Conn that collects
+// stats. The bytes read and written by the rpcs are exported from it.
+func (p *Prometheus) AddStats(side Side, stats func() map[string]drpcstats.Stats) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.sources = append(p.sources, statsSource{side: side, stats: stats})
+}
+
+// RPCStarted implements Recorder.
+func (p *Prometheus) RPCStarted(side Side, rpc string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	lbls := labels("side", string(side), "rpc", rpc)
+	p.started.get(lbls).value++
+	p.inFlight.get(lbls).value++
+}
+
+// RPCFinished implements Recorder.
+func (p *Prometheus) RPCFinished(side Side, rpc string, code string, duration time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	lbls := labels("side", string(side), "rpc", rpc)
+	p.handled.get(labels("side", string(side), "rpc", rpc, "code", code)).value++
+	p.inFlight.get(lbls).value--
+	p.duration.get(lbls).observe(p.opts.Buckets, duration.Seconds())
+}
+
+// MessageSent implements Recorder.
+func (p *Prometheus) MessageSent(side Side, rpc string) {
+	p.inc(p.sent, labels("side", string(side), "rpc", rpc), 1)
+}
+
+// MessageReceived implements Recorder.
+func (p *Prometheus) MessageReceived(side Side, rpc string) {
+	p.inc(p.received, labels("side", string(side), "rpc", rpc), 1)
+}
+
+// ConnOpened implements Recorder.
+func (p *Prometheus) ConnOpened(side Side) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	lbls := labels("side", string(side))
+	p.connsOpened.get(lbls).value++
+	p.connsOpen.get(lbls).value++
+}
+
+// ConnClosed implements Recorder.
+func (p *Prometheus) ConnClosed(side Side) {
+	p.inc(p.connsOpen, labels("side", string(side)), -1)
+}
+
+// PoolHit implements Recorder.
+func (p *Prometheus) PoolHit() { p.inc(p.poolHits, "", 1) }
+
+// PoolMiss implements Recorder.
+func (p *Prometheus) PoolMiss() { p.inc(p.poolMisses, "", 1) }
+
+// PoolEvicted implements Recorder.
+func (p *Prometheus) PoolEvicted() { p.inc(p.poolEvicted, "", 1) }
+
+// inc adds the delta to the value of the series with the labels.
+func (p *Prometheus) inc(f *family, lbls string, delta float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	f.get(lbls).value += delta
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = p.Write(w)
+}
+
+// Write writes the metrics to the writer in the Prometheus text exposition
+// format.
+func (p *Prometheus) Write(w io.Writer) error {
+	p.mu.Lock()
+	sources := append([]statsSource(nil), p.sources...)
+	p.mu.Unlock()
+
+	// the stats are read without holding the mutex because they may be
+	// slow to collect.
+	read := &family{
+		name:   p.opts.Namespace + "_bytes_read_total",
+		typ:    "counter",
+		help:   "Number of bytes read by rpcs.",
+		series: make(map[string]*series),
+	}
+	written := &family{
+		name:   p.opts.Namespace + "_bytes_written_total",
+		typ:    "counter",
+		help:   "Number of bytes written by rpcs.",
+		series: make(map[string]*series),
+	}
+	for _, src := range sources {
+		for rpc, stats := range src.stats() {
+			lbls := labels("side", string(src.side), "rpc", rpc)
+			read.get(lbls).value += float64(stats.Read)
+			written.get(lbls).value += float64(stats.Written)
+		}
+	}
+
+	var buf bytes.Buffer
+
+	p.mu.Lock()
+	for _, f := range p.families {
+		f.write(&buf, p.opts.Buckets)
+	}
+	p.mu.Unlock()
+
+	read.write(&buf, nil)
+	written.write(&buf, nil)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+//
+// families
+//
+
+// family is a set of series with the same name and type.
+type family struct {
+	name   string
+	typ    string
+	help   string
+	series map[string]*series // keyed by their formatted labels
+}
+
+// series is the value of a metric with some labels. Histograms use the
+// buckets, sum and count instead of the value.
+type series struct {
+	value   float64
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+// get returns the series with the formatted labels, creating it if needed.
+func (f *family) get(lbls string) *series {
+	s := f.series[lbls]
+	if s == nil {
+		s = new(series)
+		f.series[lbls] = s
+	}
+	return s
+}
+
+// observe adds the value to the histogram with the upper bounds.
+func (s *series) observe(bounds []float64, v float64) {
+	if s.buckets == nil {
+		s.buckets = make([]uint64, len(bounds))
+	}
+	for i, bound := range bounds {
+		if v <= bound {
+			s.buckets[i]++
+		}
+	}
+	s.sum += v
+	s.count++
+}
+
+// write writes the family in the text exposition format, with histograms
+// using the upper bounds. Families without any series are skipped.
+func (f *family) write(buf *bytes.Buffer, bounds []float64) {
+	if len(f.series) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(f.series))
+	for lbls := range f.series {
+		keys = append(keys, lbls)
+	}
+	sort.Strings(keys)
+
+	buf.WriteString("# HELP " + f.name + " " + helpEscaper.Replace(f.help) + "\n")
+	buf.WriteString("# TYPE " + f.name + " " + f.typ + "\n")
+
+	for _, lbls := range keys {
+		s := f.series[lbls]
+		if f.typ != "histogram" {
+			writeSample(buf, f.name, lbls, s.value)
+			continue
+		}
+		for i, bound := range bounds {
+			writeSample(buf, f.name+"_bucket", withLabel(lbls, "le", formatFloat(bound)), float64(s.buckets[i]))
+		}
+		writeSample(buf, f.name+"_bucket", withLabel(lbls, "le", "+Inf"), float64(s.count))
+		writeSample(buf, f.name+"_sum", lbls, s.sum)
+		writeSample(buf, f.name+"_count", lbls, float64(s.count))
+	}
+}
+
+// writeSample writes a single line for a sample.
+func writeSample(buf *bytes.Buffer, name, lbls string, v float64) {
+	buf.WriteString(name)
+	buf.WriteString(lbls)
+	buf.WriteByte(' ')
+	buf.WriteString(formatFloat(v))
+	buf.WriteByte('\n')
+}
+
+//
+// formatting
+//
+
+// labels formats the pairs of label names and values, like {a="b",c="d"}.
+func labels(pairs ...string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i])
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(pairs[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// withLabel adds the label to the formatted labels.
+func withLabel(lbls, name, value string) string {
+	extra := labels(name, value)
+	if lbls == "" || lbls == "{}" {
+		return extra
+	}
+	return lbls[:len(lbls)-1] + "," + extra[1:]
+}
+
+// formatFloat formats the value like Prometheus expects.
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
@@ -0,0 +1,67 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmetrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zeebo/assert"
+
+	"storj.io/drpc/drpcstats"
+)
+
+func TestPrometheus(t *testing.T) {
+	metrics := NewPrometheusWithOptions(PrometheusOptions{
+		Namespace: "test",
+		Buckets:   []float64{0.1, 1},
+	})
+
+	metrics.RPCStarted(Server, "/svc/A")
+	metrics.RPCFinished(Server, "/svc/A", "OK", 50*time.Millisecond)
+	metrics.RPCStarted(Server, "/svc/A")
+	metrics.RPCFinished(Server, "/svc/A", "OK", 2*time.Second)
+	metrics.RPCStarted(Server, `/svc/"B"`)
+	metrics.PoolHit()
+	metrics.AddStats(Client, func() map[string]drpcstats.Stats {
+		return map[string]drpcstats.Stats{"/svc/A": {Read: 10, Written: 20}}
+	})
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, rec.Header().Get("Content-Type"), "text/plain; version=0.0.4; charset=utf-8")
+
+	body, err := io.ReadAll(rec.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, string(body), ""+
+		"# HELP test_rpcs_started_total Number of rpcs started.\n"+
+		"# TYPE test_rpcs_started_total counter\n"+
+		`test_rpcs_started_total{side="server",rpc="/svc/A"} 2`+"\n"+
+		`test_rpcs_started_total{side="server",rpc="/svc/\"B\""} 1`+"\n"+
+		"# HELP test_rpcs_handled_total Number of rpcs finished, by result code.\n"+
+		"# TYPE test_rpcs_handled_total counter\n"+
+		`test_rpcs_handled_total{side="server",rpc="/svc/A",code="OK"} 2`+"\n"+
+		"# HELP test_rpcs_in_flight Number of rpcs started but not finished.\n"+
+		"# TYPE test_rpcs_in_flight gauge\n"+
+		`test_rpcs_in_flight{side="server",rpc="/svc/A"} 0`+"\n"+
+		`test_rpcs_in_flight{side="server",rpc="/svc/\"B\""} 1`+"\n"+
+		"# HELP test_rpc_duration_seconds Latency of finished rpcs in seconds.\n"+
+		"# TYPE test_rpc_duration_seconds histogram\n"+
+		`test_rpc_duration_seconds_bucket{side="server",rpc="/svc/A",le="0.1"} 1`+"\n"+
+		`test_rpc_duration_seconds_bucket{side="server",rpc="/svc/A",le="1"} 1`+"\n"+
+		`test_rpc_duration_seconds_bucket{side="server",rpc="/svc/A",le="+Inf"} 2`+"\n"+
+		`test_rpc_duration_seconds_sum{side="server",rpc="/svc/A"} 2.05`+"\n"+
+		`test_rpc_duration_seconds_count{side="server",rpc="/svc/A"} 2`+"\n"+
+		"# HELP test_pool_hits_total Number of connections taken from a pool.\n"+
+		"# TYPE test_pool_hits_total counter\n"+
+		"test_pool_hits_total 1\n"+
+		"# HELP test_bytes_read_total Number of bytes read by rpcs.\n"+
+		"# TYPE test_bytes_read_total counter\n"+
+		`test_bytes_read_total{side="client",rpc="/svc/A"} 10`+"\n"+
+		"# HELP test_bytes_written_total Number of bytes written by rpcs.\n"+
+		"# TYPE test_bytes_written_total counter\n"+
+		`test_bytes_written_total{side="client",rpc="/svc/A"} 20`+"\n")
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmetrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcpool"
+)
+
+// Side is the side of a connection that metrics are recorded on.
+type Side string
+
+const (
+	// Server is the side that serves rpcs.
+	Server Side = "server"
+
+	// Client is the side that invokes rpcs.
+	Client Side = "client"
+)
+
+// Recorder is told about the activity of rpcs, connections and pools so that
+// it can record metrics about them. It must be safe for concurrent use. A
+// Recorder can be used as the Recorder of a drpcpool.Pool.
+type Recorder interface {
+	// RPCStarted is called when an rpc starts.
+	RPCStarted(side Side, rpc string)
+
+	// RPCFinished is called when an rpc that started finishes with the code,
+	// like "OK" or "NotFound", after the duration.
+	RPCFinished(side Side, rpc string, code string, duration time.Duration)
+
+	// MessageSent is called when a message is sent on an rpc.
+	MessageSent(side Side, rpc string)
+
+	// MessageReceived is called when a message is received on an rpc.
+	MessageReceived(side Side, rpc string)
+
+	// ConnOpened is called when a connection is opened.
+	ConnOpened(side Side)
+
+	// ConnClosed is called when a connection that was opened is closed.
+	ConnClosed(side Side)
+
+	// PoolHit is called when a connection is taken from a pool.
+	PoolHit()
+
+	// PoolMiss is called when there is no connection in a pool to take.
+	PoolMiss()
+
+	// PoolEvicted is called when a connection is removed from a pool without
+	// being taken.
+	PoolEvicted()
+}
+
+var _ drpcpool.Recorder = Recorder(nil)
+
+// Code returns the name of the code that an rpc that failed with the error
+// finished with. It is "OK" for nil errors and the name of the drpcerr code
+// if the error has one. Otherwise, it is "Canceled" or "DeadlineExceeded" for
+// context errors and "Unknown" for every other error.
+func Code(err error) string {
+	if err == nil {
+		return drpcerr.CodeName(0)
+	} else if code := drpcerr.Code(err); code != 0 {
+		return drpcerr.CodeName(code)
+	} else if errors.Is(err, context.Canceled) {
+		return drpcerr.CodeName(drpcerr.Canceled)
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		return drpcerr.CodeName(drpcerr.DeadlineExceeded)
+	}
+	return drpcerr.CodeName(drpcerr.Unknown)
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmux
+
+import (
+	"context"
+	"strings"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcctx"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcmetadata"
+)
+
+// Authenticator validates the credentials an rpc was sent with.
+type Authenticator interface {
+	// Authenticate returns the principal that the metadata sent with the rpc
+	// authenticates, or an error if it does not authenticate anyone.
+	Authenticate(ctx context.Context, rpc string, md map[string]string) (drpcctx.Principal, error)
+}
+
+// AuthenticatorFunc is an Authenticator implemented by a function.
+type AuthenticatorFunc func(ctx context.Context, rpc string, md map[string]string) (drpcctx.Principal, error)
+
+// Authenticate calls the function.
+func (fn AuthenticatorFunc) Authenticate(ctx context.Context, rpc string, md map[string]string) (drpcctx.Principal, error) {
+	return fn(ctx, rpc, md)
+}
+
+// BearerToken returns the token from an "authorization" metadata value of the
+// form "Bearer <token>", and a bool indicating if it existed.
+func BearerToken(md map[string]string) (string, bool) {
+	const prefix = "bearer "
+	value := md["authorization"]
+	if len(value) <= len(prefix) || !strings.EqualFold(value[:len(prefix)], prefix) {
+		return "", false
+	}
+	return value[len(prefix):], true
+}
+
+// AuthOptions controls configuration settings for an Auth.
+type AuthOptions struct {
+	// Skip returns true for the rpcs that do not need to be authenticated,
+	// like health checks. If nil, every rpc is authenticated.
+	Skip func(rpc string) bool
+}
+
+// Auth is an interceptor that authenticates rpcs with an Authenticator using
+// the metadata they were sent with. The handlers of authenticated rpcs can get
+// the principal with drpcctx.GetPrincipal. Rpcs that are not authenticated
+// fail with the error from the Authenticator, coded with Unauthenticated if it
+// does not have a code.
+type Auth struct {
+	authn Authenticator
+	opts  AuthOptions
+}
+
+// NewAuth returns an Auth that authenticates rpcs with the Authenticator.
+func NewAuth(authn Authenticator) *Auth {
+	return NewAuthWithOptions(authn, AuthOptions{})
+}
+
+// NewAuthWithOptions returns an Auth that authenticates rpcs with the
+// Authenticator and the options.
+func NewAuthWithOptions(authn Authenticator, opts AuthOptions) *Auth {
+	return &Auth{authn: authn, opts: opts}
+}
+
+// authenticate returns a context with the principal the rpc is authenticated
+// as, or an error if it is not.
+func (a *Auth) authenticate(ctx context.Context, rpc string) (context.Context, error) {
+	if a.opts.Skip != nil && a.opts.Skip(rpc) {
+		return ctx, nil
+	}
+
+	md, _ := drpcmetadata.Get(ctx)
+	principal, err := a.authn.Authenticate(ctx, rpc, md)
+	if err != nil {
+		if drpcerr.Code(err) == 0 {
+			err = drpcerr.WithCode(errs.Wrap(err), drpcerr.Unauthenticated)
+		}
+		return nil, err
+	}
+	return drpcctx.WithPrincipal(ctx, principal), nil
+}
+
+// UnaryServerInterceptor is a UnaryServerInterceptor that rejects the rpcs
+// that are not authenticated.
+func (a *Auth) UnaryServerInterceptor(ctx context.Context, req interface{}, rpc string, handler UnaryHandler) (interface{}, error) {
+	ctx, err := a.authenticate(ctx, rpc)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamServerInterceptor is a StreamServerInterceptor that rejects the rpcs
+// that are not authenticated.
+func (a *Auth) StreamServerInterceptor(stream drpc.Stream, rpc string, handler StreamHandler) (interface{}, error) {
+	ctx, err := a.authenticate(stream.Context(), rpc)
+	if err != nil {
+		return nil, err
+	}
+	return handler(&authStream{Stream: stream, ctx: ctx})
+}
+
+// authStream is a drpc.Stream with the context of the authenticated rpc.
+type authStream struct {
+	drpc.Stream
+	ctx context.Context
+}
+
+// Context returns the context of the authenticated rpc.
+func (s *authStream) Context() context.Context { return s.ctx }
@@ -0,0 +1,74 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmux
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcctx"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcmetadata"
+)
+
+func TestBearerToken(t *testing.T) {
+	token, ok := BearerToken(map[string]string{"authorization": "Bearer abc"})
+	require.True(t, ok)
+	require.Equal(t, "abc", token)
+
+	token, ok = BearerToken(map[string]string{"authorization": "bearer abc"})
+	require.True(t, ok)
+	require.Equal(t, "abc", token)
+
+	_, ok = BearerToken(map[string]string{"authorization": "Basic abc"})
+	require.False(t, ok)
+	_, ok = BearerToken(map[string]string{"authorization": "Bearer "})
+	require.False(t, ok)
+	_, ok = BearerToken(nil)
+	require.False(t, ok)
+}
+
+func TestAuth(t *testing.T) {
+	authn := AuthenticatorFunc(func(ctx context.Context, rpc string, md map[string]string) (drpcctx.Principal, error) {
+		token, ok := BearerToken(md)
+		if !ok || token != "secret" {
+			return drpcctx.Principal{}, errors.New("bad token")
+		}
+		return drpcctx.Principal{Name: "user"}, nil
+	})
+	a := NewAuthWithOptions(authn, AuthOptions{
+		Skip: func(rpc string) bool { return rpc == "health" },
+	})
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		principal, _ := drpcctx.GetPrincipal(ctx)
+		return principal.Name, nil
+	}
+
+	// rpcs without valid credentials are rejected.
+	ctx := context.Background()
+	_, err := a.UnaryServerInterceptor(ctx, "req", "rpc", handler)
+	require.Equal(t, uint64(drpcerr.Unauthenticated), drpcerr.Code(err))
+
+	// skipped rpcs do not need credentials.
+	out, err := a.UnaryServerInterceptor(ctx, "req", "health", handler)
+	require.NoError(t, err)
+	require.Equal(t, "", out)
+
+	// authenticated rpcs have the principal in their context.
+	ctx = drpcmetadata.Add(ctx, "authorization", "Bearer secret")
+	out, err = a.UnaryServerInterceptor(ctx, "req", "rpc", handler)
+	require.NoError(t, err)
+	require.Equal(t, "user", out)
+
+	out, err = a.StreamServerInterceptor(&mockStream{ctx: ctx}, "rpc", func(stream drpc.Stream) (interface{}, error) {
+		return handler(stream.Context(), nil)
+	})
+	require.NoError(t, err)
+	require.Equal(t, "user", out)
+}
@@ -0,0 +1,149 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmux
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcerr"
+)
+
+// ConcurrencyOptions controls configuration settings for a ConcurrencyLimiter.
+type ConcurrencyOptions struct {
+	// InitialLimit is the number of concurrent rpcs allowed at first. If
+	// zero, 20 is used.
+	InitialLimit int
+
+	// MinLimit bounds how low the limit can go. If zero, 1 is used.
+	MinLimit int
+
+	// MaxLimit bounds how high the limit can go. If zero, 1000 is used.
+	MaxLimit int
+
+	// Latency is how long a unary rpc can take before it is a sign that the
+	// server is overloaded. If zero, 1 second is used.
+	Latency time.Duration
+
+	// BackoffRatio is the factor the limit is multiplied by when the server
+	// is overloaded. If zero, 0.9 is used.
+	BackoffRatio float64
+}
+
+// ConcurrencyLimiter sheds load by limiting the number of concurrent rpcs with
+// a limit that adapts to how the server is doing, using additive increase and
+// multiplicative decrease. The limit grows by one when an rpc finishes while
+// the server is using at least half of the limit, and shrinks by the backoff
+// ratio when an rpc is a sign of overload: a unary rpc that is slower than the
+// latency option, or an rpc that fails with the DeadlineExceeded or
+// ResourceExhausted code. Rpcs over the limit fail immediately with an error
+// with the ResourceExhausted code.
+type ConcurrencyLimiter struct {
+	opts ConcurrencyOptions
+
+	mu       sync.Mutex
+	limit    float64
+	inflight int
+}
+
+// NewConcurrencyLimiter returns a ConcurrencyLimiter with the options.
+func NewConcurrencyLimiter(opts ConcurrencyOptions) *ConcurrencyLimiter {
+	if opts.MinLimit <= 0 {
+		opts.MinLimit = 1
+	}
+	if opts.MaxLimit <= 0 {
+		opts.MaxLimit = 1000
+	}
+	if opts.MaxLimit < opts.MinLimit {
+		opts.MaxLimit = opts.MinLimit
+	}
+	if opts.InitialLimit <= 0 {
+		opts.InitialLimit = 20
+	}
+	if opts.Latency <= 0 {
+		opts.Latency = time.Second
+	}
+	if opts.BackoffRatio <= 0 || opts.BackoffRatio >= 1 {
+		opts.BackoffRatio = 0.9
+	}
+
+	c := &ConcurrencyLimiter{opts: opts}
+	c.limit = c.clamp(float64(opts.InitialLimit))
+	return c
+}
+
+// Limit returns the current limit on concurrent rpcs.
+func (c *ConcurrencyLimiter) Limit() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return int(c.limit)
+}
+
+// clamp returns the limit bounded by the options.
+func (c *ConcurrencyLimiter) clamp(limit float64) float64 {
+	if limit < float64(c.opts.MinLimit) {
+		return float64(c.opts.MinLimit)
+	} else if limit > float64(c.opts.MaxLimit) {
+		return float64(c.opts.MaxLimit)
+	}
+	return limit
+}
+
+// acquire counts an rpc as in flight, returning an error if it is over the
+// limit.
+func (c *ConcurrencyLimiter) acquire(rpc string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.inflight >= int(c.limit) {
+		return drpcerr.Newf(drpcerr.ResourceExhausted, "too many concurrent rpcs for %s", rpc)
+	}
+	c.inflight++
+	return nil
+}
+
+// release finishes an rpc that failed with the error, adjusting the limit.
+func (c *ConcurrencyLimiter) release(err error, overloaded bool) {
+	switch drpcerr.Code(err) {
+	case drpcerr.DeadlineExceeded, drpcerr.ResourceExhausted:
+		overloaded = true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if overloaded {
+		c.limit = c.clamp(c.limit * c.opts.BackoffRatio)
+	} else if float64(c.inflight)*2 >= c.limit {
+		c.limit = c.clamp(c.limit + 1)
+	}
+	c.inflight--
+}
+
+// UnaryServerInterceptor is a UnaryServerInterceptor that rejects the rpcs
+// that are over the limit.
+func (c *ConcurrencyLimiter) UnaryServerInterceptor(ctx context.Context, req interface{}, rpc string, handler UnaryHandler) (out interface{}, err error) {
+	if err := c.acquire(rpc); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	defer func() { c.release(err, time.Since(start) > c.opts.Latency) }()
+
+	return handler(ctx, req)
+}
+
+// StreamServerInterceptor is a StreamServerInterceptor that rejects the rpcs
+// that are over the limit. Streams can last for any amount of time, so their
+// latency is not a sign of overload.
+func (c *ConcurrencyLimiter) StreamServerInterceptor(stream drpc.Stream, rpc string, handler StreamHandler) (out interface{}, err error) {
+	if err := c.acquire(rpc); err != nil {
+		return nil, err
+	}
+	defer func() { c.release(err, false) }()
+
+	return handler(stream)
+}
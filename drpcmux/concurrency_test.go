@@ -0,0 +1,61 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmux
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcerr"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	c := NewConcurrencyLimiter(ConcurrencyOptions{
+		InitialLimit: 2,
+		MinLimit:     1,
+		MaxLimit:     3,
+		BackoffRatio: 0.5,
+	})
+	ctx := context.Background()
+	require.Equal(t, 2, c.Limit())
+
+	// rpcs over the limit are rejected while others are in flight.
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	done := make(chan error, 2)
+	blocking := func(ctx context.Context, req interface{}) (interface{}, error) {
+		started <- struct{}{}
+		<-release
+		return nil, nil
+	}
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := c.UnaryServerInterceptor(ctx, nil, "rpc", blocking)
+			done <- err
+		}()
+		<-started
+	}
+
+	_, err := c.UnaryServerInterceptor(ctx, nil, "rpc", blocking)
+	require.Equal(t, uint64(drpcerr.ResourceExhausted), drpcerr.Code(err))
+
+	// finishing while the server is busy increases the limit up to the max.
+	close(release)
+	require.NoError(t, <-done)
+	require.NoError(t, <-done)
+	require.Equal(t, 3, c.Limit())
+
+	// overloaded rpcs decrease the limit down to the min.
+	overloaded := func(stream drpc.Stream) (interface{}, error) {
+		return nil, drpcerr.New(drpcerr.DeadlineExceeded, "too slow")
+	}
+	stream := &mockStream{ctx: ctx}
+	for i := 0; i < 3; i++ {
+		_, _ = c.StreamServerInterceptor(stream, "rpc", overloaded)
+	}
+	require.Equal(t, 1, c.Limit())
+}
@@ -67,13 +67,24 @@ func (m *Mux) HandleRPC(originalStream drpc.Stream, rpc string) (err error) {
 	switch {
 	case err != nil:
 		return errs.Wrap(err)
-	case out != nil && !reflect.ValueOf(out).IsNil():
+	case SendsOutput(out):
 		return originalStream.MsgSend(out, data.enc)
 	default:
 		return originalStream.CloseSend()
 	}
 }
 
+// SendsOutput returns true if the mux sends the output returned by a handler
+// to the client, which it does unless the output is nil or a nil pointer.
+// Interceptors can use it to tell if an rpc that succeeded sent a message.
+func SendsOutput(out interface{}) bool {
+	if out == nil {
+		return false
+	}
+	rv := reflect.ValueOf(out)
+	return rv.Kind() != reflect.Ptr || !rv.IsNil()
+}
+
 // msgRecv receives a message from the stream
 func (m *Mux) msgRecv(data rpcData, stream drpc.Stream) (drpc.Message, error) {
 	msg, ok := reflect.New(data.in1.Elem()).Interface().(drpc.Message)
@@ -0,0 +1,71 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmux
+
+import (
+	"reflect"
+	"sort"
+
+	"storj.io/drpc"
+)
+
+// RPCInfo describes an rpc registered with a Mux.
+type RPCInfo struct {
+	// Name is the name of the rpc, like "/package.Service/Method".
+	Name string
+
+	// ClientStreaming is true if the client sends a stream of messages.
+	ClientStreaming bool
+
+	// ServerStreaming is true if the server sends a stream of messages.
+	ServerStreaming bool
+
+	// Input is the type of the messages the client sends. It is nil if the
+	// rpc was registered with a stream type that does not say.
+	Input reflect.Type
+
+	// Output is the type of the messages the server sends. It is nil if the
+	// rpc was registered with a stream type that does not say.
+	Output reflect.Type
+
+	// Description is the Description the rpc was registered with.
+	Description drpc.Description
+}
+
+// RPCs returns information about every rpc registered with the Mux, sorted by
+// name. It must not be called concurrently with Register.
+func (m *Mux) RPCs() []RPCInfo {
+	infos := make([]RPCInfo, 0, len(m.rpcs))
+	for _, data := range m.rpcs {
+		infos = append(infos, data.info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// streamSendType returns the message type sent by the named method of a
+// generated stream type, or nil if it does not have one.
+func streamSendType(st reflect.Type, name string) reflect.Type {
+	if st.Kind() != reflect.Interface {
+		return nil
+	}
+	m, ok := st.MethodByName(name)
+	if !ok || m.Type.NumIn() != 1 || !m.Type.In(0).Implements(messageType) {
+		return nil
+	}
+	return m.Type.In(0)
+}
+
+// streamRecvType returns the message type received by the Recv method of a
+// generated stream type, or nil if it does not have one.
+func streamRecvType(st reflect.Type) reflect.Type {
+	if st.Kind() != reflect.Interface {
+		return nil
+	}
+	m, ok := st.MethodByName("Recv")
+	if !ok || m.Type.NumOut() != 2 || !m.Type.Out(0).Implements(messageType) {
+		return nil
+	}
+	return m.Type.Out(0)
+}
@@ -0,0 +1,84 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmux
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/drpc"
+)
+
+type infoClientStream interface {
+	drpc.Stream
+	SendAndClose(*mockMessage) error
+	Recv() (*mockMessage, error)
+}
+
+type infoServerStream interface {
+	drpc.Stream
+	Send(*mockMessage) error
+}
+
+type infoBidiStream interface {
+	drpc.Stream
+	Send(*mockMessage) error
+	Recv() (*mockMessage, error)
+}
+
+type infoServer interface {
+	Unitary(context.Context, *mockMessage) (*mockMessage, error)
+	ClientStream(infoClientStream) error
+	ServerStream(*mockMessage, infoServerStream) error
+	BidiStream(infoBidiStream) error
+	Raw(drpc.Stream) error
+}
+
+type infoDescription struct{}
+
+func (infoDescription) NumMethods() int { return 5 }
+
+func (infoDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver, interface{}, bool) {
+	switch n {
+	case 0:
+		return "/info.Service/Unitary", mockEncoding{}, nil, infoServer.Unitary, true
+	case 1:
+		return "/info.Service/ClientStream", mockEncoding{}, nil, infoServer.ClientStream, true
+	case 2:
+		return "/info.Service/ServerStream", mockEncoding{}, nil, infoServer.ServerStream, true
+	case 3:
+		return "/info.Service/BidiStream", mockEncoding{}, nil, infoServer.BidiStream, true
+	case 4:
+		return "/info.Service/Raw", mockEncoding{}, nil, infoServer.Raw, true
+	default:
+		return "", nil, nil, nil, false
+	}
+}
+
+func TestMux_RPCs(t *testing.T) {
+	r := require.New(t)
+
+	mux := New()
+	r.NoError(mux.Register(nil, infoDescription{}))
+
+	msg := reflect.TypeOf((*mockMessage)(nil))
+	strip := func(infos []RPCInfo) []RPCInfo {
+		for i := range infos {
+			r.Equal(infoDescription{}, infos[i].Description)
+			infos[i].Description = nil
+		}
+		return infos
+	}
+
+	r.Equal([]RPCInfo{
+		{Name: "/info.Service/BidiStream", ClientStreaming: true, ServerStreaming: true, Input: msg, Output: msg},
+		{Name: "/info.Service/ClientStream", ClientStreaming: true, Input: msg, Output: msg},
+		{Name: "/info.Service/Raw", ClientStreaming: true, ServerStreaming: true},
+		{Name: "/info.Service/ServerStream", ServerStreaming: true, Input: msg, Output: msg},
+		{Name: "/info.Service/Unitary", Input: msg, Output: msg},
+	}, strip(mux.RPCs()))
+}
@@ -49,6 +49,7 @@ type rpcData struct {
 	in1      reflect.Type
 	in2      reflect.Type
 	unitary  bool
+	info     RPCInfo
 }
 
 // Register associates the RPCs described by the description in the server.
@@ -60,7 +61,7 @@ func (m *Mux) Register(srv interface{}, desc drpc.Description) error {
 		if !ok {
 			return errs.New("Description returned invalid method for index %d", i)
 		}
-		if err := m.registerOne(srv, rpc, enc, receiver, method); err != nil {
+		if err := m.registerOne(srv, desc, rpc, enc, receiver, method); err != nil {
 			return err
 		}
 	}
@@ -69,9 +70,11 @@ func (m *Mux) Register(srv interface{}, desc drpc.Description) error {
 
 // registerOne does the work to register a single rpc.
 func (m *Mux) registerOne(
-	srv interface{}, rpc string, enc drpc.Encoding, receiver drpc.Receiver, method interface{},
+	srv interface{}, desc drpc.Description, rpc string, enc drpc.Encoding,
+	receiver drpc.Receiver, method interface{},
 ) error {
 	data := rpcData{srv: srv, enc: enc, receiver: receiver}
+	data.info = RPCInfo{Name: rpc, Description: desc}
 
 	switch mt := reflect.TypeOf(method); {
 	// unitary input, unitary output
@@ -81,6 +84,7 @@ func (m *Mux) registerOne(
 		if !data.in1.Implements(messageType) {
 			return errs.New("input argument not a drpc message: %v", data.in1)
 		}
+		data.info.Input, data.info.Output = data.in1, mt.Out(0)
 
 	// unitary input, stream output
 	case mt.NumIn() == 3:
@@ -89,10 +93,21 @@ func (m *Mux) registerOne(
 			return errs.New("input argument not a drpc message: %v", data.in1)
 		}
 		data.in2 = streamType
+		data.info.ServerStreaming = true
+		data.info.Input = data.in1
+		data.info.Output = streamSendType(mt.In(2), "Send")
 
 	// stream input
 	case mt.NumIn() == 2:
 		data.in1 = streamType
+		data.info.ClientStreaming = true
+		data.info.Input = streamRecvType(mt.In(1))
+		if out := streamSendType(mt.In(1), "SendAndClose"); out != nil {
+			data.info.Output = out
+		} else {
+			data.info.ServerStreaming = true
+			data.info.Output = streamSendType(mt.In(1), "Send")
+		}
 
 	// code gen bug?
 	default:
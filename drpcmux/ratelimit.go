@@ -0,0 +1,160 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmux
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcctx"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcmetadata"
+)
+
+// RateLimitKey returns the key that an rpc is rate limited by. Rpcs with the
+// same key share a token bucket.
+type RateLimitKey func(ctx context.Context, rpc string) string
+
+// KeyByRPC rate limits every rpc name separately.
+func KeyByRPC(ctx context.Context, rpc string) string { return rpc }
+
+// KeyByPeer rate limits every peer separately. A peer is identified by the
+// subject of its TLS certificate if it was verified during the handshake, and
+// otherwise by the ip address of the transport if it is a network connection.
+// Rpcs from unknown peers share a bucket.
+func KeyByPeer(ctx context.Context, rpc string) string {
+	// unverified certificates can have any subject, so peers could pick their
+	// own bucket with them.
+	if state, ok := drpcctx.TLSConnectionState(ctx); ok && len(state.VerifiedChains) > 0 && len(state.VerifiedChains[0]) > 0 {
+		return "cert:" + state.VerifiedChains[0][0].Subject.String()
+	}
+	if tr, ok := drpcctx.Transport(ctx); ok {
+		if conn, ok := tr.(interface{ RemoteAddr() net.Addr }); ok {
+			addr := conn.RemoteAddr().String()
+			if host, _, err := net.SplitHostPort(addr); err == nil {
+				addr = host
+			}
+			return "ip:" + addr
+		}
+	}
+	return ""
+}
+
+// KeyByMetadata rate limits every value of the metadata key separately. Rpcs
+// without the key share a bucket.
+func KeyByMetadata(key string) RateLimitKey {
+	return func(ctx context.Context, rpc string) string {
+		value, _ := drpcmetadata.GetValue(ctx, key)
+		return value
+	}
+}
+
+// maxBuckets is the number of buckets a RateLimiter keeps before it removes
+// the ones that are full.
+const maxBuckets = 10000
+
+// RateLimiter is a token bucket rate limiter for rpcs. Rpcs that are over the
+// limit fail with an error with the ResourceExhausted code.
+type RateLimiter struct {
+	rate  float64
+	burst float64
+	key   RateLimitKey
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// NewRateLimiter returns a RateLimiter that allows rate rpcs per second, with
+// bursts of up to burst rpcs, for every key. If key is nil, every rpc shares
+// the same bucket.
+func NewRateLimiter(rate float64, burst int, key RateLimitKey) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		key:     key,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// tokenBucket is the number of tokens available at a point in time.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Allow returns true if the rpc is allowed by the rate limit, taking a token
+// from its bucket.
+func (r *RateLimiter) Allow(ctx context.Context, rpc string) bool {
+	var key string
+	if r.key != nil {
+		key = r.key(ctx, rpc)
+	}
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b := r.buckets[key]
+	if b == nil {
+		if len(r.buckets) >= maxBuckets {
+			r.sweepLocked(now)
+		}
+		b = &tokenBucket{tokens: r.burst, last: now}
+		r.buckets[key] = b
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * r.rate
+	if b.tokens > r.burst {
+		b.tokens = r.burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// sweepLocked removes the buckets that have refilled, since they are the same
+// as a new bucket. It must be called with the mutex held.
+func (r *RateLimiter) sweepLocked(now time.Time) {
+	for key, b := range r.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*r.rate >= r.burst {
+			delete(r.buckets, key)
+		}
+	}
+}
+
+// check returns an error if the rpc is not allowed by the rate limit.
+func (r *RateLimiter) check(ctx context.Context, rpc string) error {
+	if !r.Allow(ctx, rpc) {
+		return drpcerr.Newf(drpcerr.ResourceExhausted, "rate limit exceeded for %s", rpc)
+	}
+	return nil
+}
+
+// UnaryServerInterceptor is a UnaryServerInterceptor that rejects the rpcs
+// that are over the rate limit.
+func (r *RateLimiter) UnaryServerInterceptor(ctx context.Context, req interface{}, rpc string, handler UnaryHandler) (interface{}, error) {
+	if err := r.check(ctx, rpc); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamServerInterceptor is a StreamServerInterceptor that rejects the rpcs
+// that are over the rate limit.
+func (r *RateLimiter) StreamServerInterceptor(stream drpc.Stream, rpc string, handler StreamHandler) (interface{}, error) {
+	if err := r.check(stream.Context(), rpc); err != nil {
+		return nil, err
+	}
+	return handler(stream)
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcmux
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcctx"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcmetadata"
+)
+
+func TestRateLimiter(t *testing.T) {
+	r := NewRateLimiter(1, 2, KeyByRPC)
+	ctx := context.Background()
+
+	// the burst is allowed and then rpcs are rejected.
+	require.True(t, r.Allow(ctx, "a"))
+	require.True(t, r.Allow(ctx, "a"))
+	require.False(t, r.Allow(ctx, "a"))
+
+	// other keys have their own bucket.
+	require.True(t, r.Allow(ctx, "b"))
+
+	// tokens refill over time.
+	r.mu.Lock()
+	r.buckets["a"].last = time.Now().Add(-time.Second)
+	r.mu.Unlock()
+	require.True(t, r.Allow(ctx, "a"))
+	require.False(t, r.Allow(ctx, "a"))
+}
+
+func TestRateLimiter_Interceptors(t *testing.T) {
+	r := NewRateLimiter(0, 1, nil)
+	ctx := context.Background()
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return req, nil }
+	out, err := r.UnaryServerInterceptor(ctx, "req", "a", handler)
+	require.NoError(t, err)
+	require.Equal(t, "req", out)
+
+	_, err = r.UnaryServerInterceptor(ctx, "req", "b", handler)
+	require.Equal(t, uint64(drpcerr.ResourceExhausted), drpcerr.Code(err))
+
+	stream := &mockStream{ctx: ctx}
+	_, err = r.StreamServerInterceptor(stream, "a", func(stream drpc.Stream) (interface{}, error) {
+		t.Fatal("handler called")
+		return nil, nil
+	})
+	require.Equal(t, uint64(drpcerr.ResourceExhausted), drpcerr.Code(err))
+}
+
+func TestRateLimitKeys(t *testing.T) {
+	ctx := context.Background()
+	require.Equal(t, "", KeyByPeer(ctx, "rpc"))
+	require.Equal(t, "", KeyByMetadata("user")(ctx, "rpc"))
+
+	// peers are keyed by the address of their transport.
+	a, b := net.Pipe()
+	defer func() { _ = a.Close() }()
+	defer func() { _ = b.Close() }()
+	tr := addrConn{Conn: a, addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1234}}
+	require.Equal(t, "ip:10.0.0.1", KeyByPeer(drpcctx.WithTransport(ctx, tr), "rpc"))
+
+	// or by their certificate if it was verified.
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "peer"}}
+	pctx := drpcctx.WithTLSConnectionState(drpcctx.WithTransport(ctx, tr), tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+		VerifiedChains:   [][]*x509.Certificate{{cert}},
+	})
+	require.Equal(t, "cert:CN=peer", KeyByPeer(pctx, "rpc"))
+
+	pctx = drpcctx.WithTLSConnectionState(drpcctx.WithTransport(ctx, tr), tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+	})
+	require.Equal(t, "ip:10.0.0.1", KeyByPeer(pctx, "rpc"))
+
+	mctx := drpcmetadata.Add(ctx, "user", "alice")
+	require.Equal(t, "alice", KeyByMetadata("user")(mctx, "rpc"))
+}
+
+// addrConn is a net.Conn with a fixed remote address.
+type addrConn struct {
+	net.Conn
+	addr net.Addr
+}
+
+func (c addrConn) RemoteAddr() net.Addr { return c.addr }
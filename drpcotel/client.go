@@ -0,0 +1,102 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcotel
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcclient"
+)
+
+// DialOptions returns the DialOptions that add the client interceptors of a
+// new Interceptor with the options.
+func DialOptions(opts Options) []drpcclient.DialOption {
+	return NewInterceptor(opts).DialOptions()
+}
+
+// DialOptions returns the DialOptions that add the client interceptors.
+func (i *Interceptor) DialOptions() []drpcclient.DialOption {
+	return []drpcclient.DialOption{
+		drpcclient.WithChainUnaryInterceptor(i.UnaryClientInterceptor),
+		drpcclient.WithChainStreamInterceptor(i.StreamClientInterceptor),
+	}
+}
+
+// startClient starts a span for an rpc being invoked and adds the trace
+// context to the metadata of the rpc.
+func (i *Interceptor) startClient(ctx context.Context, rpc string, cc *drpcclient.ClientConn) (context.Context, *call) {
+	attrs := rpcAttributes(rpc)
+	spanAttrs := attrs
+	if conn, ok := cc.Conn.(interface{ Transport() drpc.Transport }); ok {
+		spanAttrs = append(spanAttrs[:len(spanAttrs):len(spanAttrs)], peerAttributes(conn.Transport())...)
+	}
+
+	ctx, span := i.tracer.Start(ctx, spanName(rpc),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(spanAttrs...))
+
+	return i.inject(ctx), &call{
+		span:  span,
+		ins:   &i.client,
+		attrs: attrs,
+		kind:  trace.SpanKindClient,
+		start: time.Now(),
+	}
+}
+
+// UnaryClientInterceptor is a drpcclient.UnaryClientInterceptor that creates
+// a span for the unary rpcs invoked.
+func (i *Interceptor) UnaryClientInterceptor(ctx context.Context, rpc string, enc drpc.Encoding, in, out drpc.Message, cc *drpcclient.ClientConn, next drpcclient.UnaryInvoker) (err error) {
+	ctx, c := i.startClient(ctx, rpc, cc)
+	defer func() { c.end(err) }()
+
+	c.messageSent()
+	err = next(ctx, rpc, enc, in, out, cc)
+	if err == nil {
+		c.messageReceived()
+	}
+	return err
+}
+
+// StreamClientInterceptor is a drpcclient.StreamClientInterceptor that
+// creates a span for the streaming rpcs invoked. The span ends when receiving
+// from the stream fails, which is with io.EOF if it was successful, or when
+// its context is done otherwise.
+func (i *Interceptor) StreamClientInterceptor(ctx context.Context, rpc string, enc drpc.Encoding, cc *drpcclient.ClientConn, streamer drpcclient.Streamer) (drpc.Stream, error) {
+	ctx, c := i.startClient(ctx, rpc, cc)
+
+	stream, err := streamer(ctx, rpc, enc, cc)
+	if err != nil {
+		c.end(err)
+		return nil, err
+	}
+
+	return drpcclient.OnFinish(&clientStream{Stream: stream, call: c}, c.end), nil
+}
+
+// clientStream adds events for the messages on a stream being invoked.
+type clientStream struct {
+	drpc.Stream
+	call *call
+}
+
+func (s *clientStream) MsgSend(msg drpc.Message, enc drpc.Encoding) error {
+	err := s.Stream.MsgSend(msg, enc)
+	if err == nil {
+		s.call.messageSent()
+	}
+	return err
+}
+
+func (s *clientStream) MsgRecv(msg drpc.Message, enc drpc.Encoding) error {
+	err := s.Stream.MsgRecv(msg, enc)
+	if err == nil {
+		s.call.messageReceived()
+	}
+	return err
+}
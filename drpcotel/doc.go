@@ -0,0 +1,24 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package drpcotel instruments servers and clients with OpenTelemetry.
+//
+// An Interceptor creates spans for rpcs following the OpenTelemetry semantic
+// conventions for rpcs, with an event for every message sent or received on
+// them, and records the duration of rpcs and the number of messages on them
+// as metrics. The trace context is propagated in the drpcmetadata of the rpcs,
+// with the W3C trace context format by default.
+//
+// Its server interceptors are added to a drpcmux.Mux:
+//
+//	ints := drpcotel.NewInterceptor(drpcotel.Options{})
+//	mux := drpcmux.NewWithInterceptors(
+//		[]drpcmux.UnaryServerInterceptor{ints.UnaryServerInterceptor},
+//		[]drpcmux.StreamServerInterceptor{ints.StreamServerInterceptor},
+//	)
+//
+// and its client interceptors are added to a drpcclient.ClientConn with the
+// DialOptions:
+//
+//	cc, err := drpcclient.NewClientConnWithOptions(ctx, conn, drpcotel.DialOptions(drpcotel.Options{})...)
+package drpcotel
@@ -0,0 +1,22 @@
+module storj.io/drpc/drpcotel
+
+go 1.19
+
+require (
+	github.com/zeebo/assert v1.3.0
+	go.opentelemetry.io/otel v1.16.0
+	go.opentelemetry.io/otel/metric v1.16.0
+	go.opentelemetry.io/otel/sdk v1.16.0
+	go.opentelemetry.io/otel/sdk/metric v0.39.0
+	go.opentelemetry.io/otel/trace v1.16.0
+	storj.io/drpc v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/go-logr/logr v1.2.4 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/zeebo/errs v1.2.2 // indirect
+	golang.org/x/sys v0.10.0 // indirect
+)
+
+replace storj.io/drpc => ../
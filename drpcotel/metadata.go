@@ -0,0 +1,88 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcotel
+
+import (
+	"context"
+
+	"storj.io/drpc/drpcmetadata"
+)
+
+// metadataCarrier is a propagation.TextMapCarrier for drpc metadata.
+type metadataCarrier drpcmetadata.MD
+
+// Get returns the last value for the key. Values added later, such as the
+// outgoing metadata after the metadata added by drpcmetadata.Add, take
+// precedence.
+func (c metadataCarrier) Get(key string) string {
+	if values := c[key]; len(values) > 0 {
+		return values[len(values)-1]
+	}
+	return ""
+}
+
+// Set replaces the values for the key with the value.
+func (c metadataCarrier) Set(key, value string) {
+	c[key] = []string{value}
+}
+
+// Keys returns the keys in the metadata.
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// inject returns a context with the trace context of the context added to its
+// outgoing metadata. Any trace context in the metadata added by
+// drpcmetadata.Add, such as the one a server received from its caller, is
+// removed so that only the current one is sent.
+func (i *Interceptor) inject(ctx context.Context) context.Context {
+	ctx = i.dropFields(ctx)
+	md, ok := drpcmetadata.FromOutgoingContext(ctx)
+	if !ok {
+		md = make(drpcmetadata.MD)
+	}
+	i.propagators.Inject(ctx, metadataCarrier(md))
+	return drpcmetadata.NewOutgoingContext(ctx, md)
+}
+
+// dropFields returns a context with the propagator fields removed from the
+// metadata added by drpcmetadata.Add. The metadata is copied because it may be
+// shared with other contexts.
+func (i *Interceptor) dropFields(ctx context.Context) context.Context {
+	added, ok := drpcmetadata.Get(ctx)
+	if !ok {
+		return ctx
+	}
+
+	fields := i.propagators.Fields()
+	found := false
+	for _, field := range fields {
+		if _, ok := added[field]; ok {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ctx
+	}
+
+	pairs := make(map[string]string, len(added))
+	for key, value := range added {
+		pairs[key] = value
+	}
+	for _, field := range fields {
+		delete(pairs, field)
+	}
+	return drpcmetadata.AddPairs(drpcmetadata.ClearContext(ctx), pairs)
+}
+
+// extract returns a context with the trace context from its incoming metadata.
+func (i *Interceptor) extract(ctx context.Context) context.Context {
+	md, _ := drpcmetadata.FromIncomingContext(ctx)
+	return i.propagators.Extract(ctx, metadataCarrier(md))
+}
@@ -0,0 +1,248 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcotel
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcerr"
+)
+
+// instrumentationName is the name of the tracer and meter.
+const instrumentationName = "storj.io/drpc/drpcotel"
+
+// ErrorCodeKey is the attribute key for the drpcerr code that an rpc finished
+// with, which is zero if it was successful.
+const ErrorCodeKey = attribute.Key("rpc.drpc.error_code")
+
+// RPCSystemDRPC is the rpc.system attribute for drpc.
+var RPCSystemDRPC = semconv.RPCSystemKey.String("drpc")
+
+// Options controls configuration settings for an Interceptor.
+type Options struct {
+	// TracerProvider creates the tracer for the spans. If nil, the global
+	// TracerProvider is used.
+	TracerProvider trace.TracerProvider
+
+	// MeterProvider creates the meter for the metrics. If nil, the global
+	// MeterProvider is used.
+	MeterProvider metric.MeterProvider
+
+	// Propagators injects the trace context into the metadata of the rpcs
+	// sent by clients, and extracts it from the metadata of the rpcs served.
+	// If nil, the global TextMapPropagator is used if it has been set, and
+	// otherwise the W3C trace context format is used.
+	Propagators propagation.TextMapPropagator
+}
+
+// Interceptor instruments the rpcs of servers and clients with spans and
+// metrics. Its server interceptors are added to a drpcmux.Mux and its client
+// interceptors are added to a drpcclient.ClientConn.
+type Interceptor struct {
+	tracer      trace.Tracer
+	propagators propagation.TextMapPropagator
+	server      instruments
+	client      instruments
+}
+
+// instruments are the metrics for one side of rpcs.
+type instruments struct {
+	duration  metric.Float64Histogram
+	requests  metric.Int64Histogram
+	responses metric.Int64Histogram
+}
+
+// NewInterceptor returns an Interceptor with the options.
+func NewInterceptor(opts Options) *Interceptor {
+	if opts.TracerProvider == nil {
+		opts.TracerProvider = otel.GetTracerProvider()
+	}
+	if opts.MeterProvider == nil {
+		opts.MeterProvider = otel.GetMeterProvider()
+	}
+	if opts.Propagators == nil {
+		opts.Propagators = otel.GetTextMapPropagator()
+		if len(opts.Propagators.Fields()) == 0 {
+			opts.Propagators = propagation.TraceContext{}
+		}
+	}
+
+	meter := opts.MeterProvider.Meter(instrumentationName)
+	return &Interceptor{
+		tracer:      opts.TracerProvider.Tracer(instrumentationName),
+		propagators: opts.Propagators,
+		server:      newInstruments(meter, "server"),
+		client:      newInstruments(meter, "client"),
+	}
+}
+
+// newInstruments creates the metrics for the side, which is "server" or
+// "client". Errors creating them are sent to the global error handler.
+func newInstruments(meter metric.Meter, side string) (ins instruments) {
+	var err error
+
+	ins.duration, err = meter.Float64Histogram("rpc."+side+".duration",
+		metric.WithUnit("ms"),
+		metric.WithDescription("Measures the duration of rpcs."))
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	ins.requests, err = meter.Int64Histogram("rpc."+side+".requests_per_rpc",
+		metric.WithUnit("{count}"),
+		metric.WithDescription("Measures the number of request messages per rpc."))
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	ins.responses, err = meter.Int64Histogram("rpc."+side+".responses_per_rpc",
+		metric.WithUnit("{count}"),
+		metric.WithDescription("Measures the number of response messages per rpc."))
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return ins
+}
+
+// rpcAttributes returns the attributes for the rpc, like "/pkg.Service/Method".
+func rpcAttributes(rpc string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{RPCSystemDRPC}
+	service, method, ok := strings.Cut(strings.TrimPrefix(rpc, "/"), "/")
+	if !ok {
+		return attrs
+	}
+	return append(attrs, semconv.RPCService(service), semconv.RPCMethod(method))
+}
+
+// spanName returns the name of the span for the rpc, which is the rpc without
+// the leading slash.
+func spanName(rpc string) string {
+	if name := strings.TrimPrefix(rpc, "/"); name != "" {
+		return name
+	}
+	return rpc
+}
+
+// peerAttributes returns the attributes for the remote address of the
+// transport if it is a network connection.
+func peerAttributes(tr drpc.Transport) []attribute.KeyValue {
+	conn, ok := tr.(interface{ RemoteAddr() net.Addr })
+	if !ok {
+		return nil
+	}
+	host, port, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return nil
+	}
+	attrs := []attribute.KeyValue{semconv.NetSockPeerAddr(host)}
+	if port, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, semconv.NetSockPeerPort(port))
+	}
+	return attrs
+}
+
+// serverError returns true if an error with the code is a failure of the
+// server rather than of the request, like the conventions for gRPC servers.
+func serverError(code uint64) bool {
+	switch code {
+	case drpcerr.Unknown, drpcerr.DeadlineExceeded, drpcerr.Unimplemented,
+		drpcerr.Internal, drpcerr.Unavailable, drpcerr.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
+
+//
+// calls
+//
+
+// call is an rpc being instrumented.
+type call struct {
+	span  trace.Span
+	ins   *instruments
+	attrs []attribute.KeyValue
+	kind  trace.SpanKind
+	start time.Time
+
+	mu       sync.Mutex
+	sent     int64
+	received int64
+}
+
+// messageSent adds an event for a message sent on the rpc.
+func (c *call) messageSent() {
+	c.mu.Lock()
+	c.sent++
+	id := c.sent
+	c.mu.Unlock()
+
+	c.span.AddEvent("message", trace.WithAttributes(semconv.MessageTypeSent, semconv.MessageID(int(id))))
+}
+
+// messageReceived adds an event for a message received on the rpc.
+func (c *call) messageReceived() {
+	c.mu.Lock()
+	c.received++
+	id := c.received
+	c.mu.Unlock()
+
+	c.span.AddEvent("message", trace.WithAttributes(semconv.MessageTypeReceived, semconv.MessageID(int(id))))
+}
+
+// end ends the span of the rpc after it failed with the error and records
+// its metrics.
+func (c *call) end(err error) {
+	code := drpcerr.Code(err)
+	if err != nil && code == 0 {
+		code = drpcerr.Unknown
+	}
+
+	c.span.SetAttributes(ErrorCodeKey.Int64(int64(code)))
+	if err != nil && (c.kind == trace.SpanKindClient || serverError(code)) {
+		c.span.SetStatus(codes.Error, err.Error())
+	}
+	c.span.End()
+
+	// servers receive requests and send responses, and clients do the
+	// opposite.
+	c.mu.Lock()
+	requests, responses := c.received, c.sent
+	if c.kind == trace.SpanKindClient {
+		requests, responses = c.sent, c.received
+	}
+	c.mu.Unlock()
+
+	// the context of the rpc may be canceled, and the metrics should be
+	// recorded anyway.
+	ctx := context.Background()
+	attrs := append(append([]attribute.KeyValue(nil), c.attrs...), ErrorCodeKey.Int64(int64(code)))
+	opt := metric.WithAttributes(attrs...)
+	elapsed := float64(time.Since(c.start)) / float64(time.Millisecond)
+
+	if c.ins.duration != nil {
+		c.ins.duration.Record(ctx, elapsed, opt)
+	}
+	if c.ins.requests != nil {
+		c.ins.requests.Record(ctx, requests, opt)
+	}
+	if c.ins.responses != nil {
+		c.ins.responses.Record(ctx, responses, opt)
+	}
+}
@@ -0,0 +1,336 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcotel
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/zeebo/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcclient"
+	"storj.io/drpc/drpcconn"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcmux"
+	"storj.io/drpc/drpcserver"
+	"storj.io/drpc/drpctest"
+)
+
+type stringEncoding struct{}
+
+func (stringEncoding) Marshal(msg drpc.Message) ([]byte, error) {
+	return []byte(*msg.(*string)), nil
+}
+
+func (stringEncoding) Unmarshal(buf []byte, msg drpc.Message) error {
+	*msg.(*string) = string(buf)
+	return nil
+}
+
+type testServer interface {
+	Unary(ctx context.Context, in *string) (*string, error)
+	Stream(stream drpc.Stream) error
+}
+
+type testDescription struct{}
+
+func (testDescription) NumMethods() int { return 2 }
+
+func (testDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver, interface{}, bool) {
+	switch n {
+	case 0:
+		return "/test.Service/Unary", stringEncoding{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(testServer).Unary(ctx, in1.(*string))
+			}, testServer.Unary, true
+	case 1:
+		return "/test.Service/Stream", stringEncoding{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return nil, srv.(testServer).Stream(in1.(drpc.Stream))
+			}, testServer.Stream, true
+	default:
+		return "", nil, nil, nil, false
+	}
+}
+
+// echoServer echoes messages, failing for the "fail" message, and keeps the
+// span context of the last rpc it served.
+type echoServer struct {
+	last chan trace.SpanContext
+}
+
+func (s echoServer) Unary(ctx context.Context, in *string) (*string, error) {
+	s.last <- trace.SpanContextFromContext(ctx)
+	if *in == "fail" {
+		return nil, drpcerr.New(drpcerr.NotFound, "not found")
+	}
+	return in, nil
+}
+
+func (s echoServer) Stream(stream drpc.Stream) error {
+	s.last <- trace.SpanContextFromContext(stream.Context())
+	for {
+		var msg string
+		if err := stream.MsgRecv(&msg, stringEncoding{}); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := stream.MsgSend(&msg, stringEncoding{}); err != nil {
+			return err
+		}
+	}
+}
+
+// hopServer forwards unary rpcs to the next server, and keeps the span context
+// of the last rpc it served.
+type hopServer struct {
+	echoServer
+	next *drpcclient.ClientConn
+}
+
+func (s hopServer) Unary(ctx context.Context, in *string) (*string, error) {
+	s.last <- trace.SpanContextFromContext(ctx)
+	var out string
+	err := s.next.Invoke(ctx, "/test.Service/Unary", stringEncoding{}, in, &out)
+	return &out, err
+}
+
+type testEnv struct {
+	ints   *Interceptor
+	spans  *tracetest.InMemoryExporter
+	reader sdkmetric.Reader
+	tracer trace.Tracer
+	last   chan trace.SpanContext
+	cc     *drpcclient.ClientConn
+}
+
+func newTestEnv(t *testing.T, ctx *drpctest.Tracker) *testEnv {
+	spans := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	ints := NewInterceptor(Options{TracerProvider: tp, MeterProvider: mp})
+	last := make(chan trace.SpanContext, 1)
+
+	return &testEnv{
+		ints:   ints,
+		spans:  spans,
+		reader: reader,
+		tracer: tp.Tracer("test"),
+		last:   last,
+		cc:     serve(t, ctx, ints, echoServer{last: last}),
+	}
+}
+
+// serve returns a client conn to a server for the test server instrumented
+// with the interceptor.
+func serve(t *testing.T, ctx *drpctest.Tracker, ints *Interceptor, impl testServer) *drpcclient.ClientConn {
+	mux := drpcmux.NewWithInterceptors(
+		[]drpcmux.UnaryServerInterceptor{ints.UnaryServerInterceptor},
+		[]drpcmux.StreamServerInterceptor{ints.StreamServerInterceptor},
+	)
+	assert.NoError(t, mux.Register(impl, testDescription{}))
+
+	pc, ps := net.Pipe()
+	srv := drpcserver.New(mux)
+	ctx.Run(func(ctx context.Context) { _ = srv.ServeOne(ctx, ps) })
+
+	conn := drpcconn.New(pc)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	cc, err := drpcclient.NewClientConnWithOptions(ctx, conn, ints.DialOptions()...)
+	assert.NoError(t, err)
+	return cc
+}
+
+// span returns the span with the kind.
+func (env *testEnv) span(t *testing.T, kind trace.SpanKind) tracetest.SpanStub {
+	t.Helper()
+	for _, span := range env.spans.GetSpans() {
+		if span.SpanKind == kind {
+			return span
+		}
+	}
+	t.Fatalf("no %v span", kind)
+	return tracetest.SpanStub{}
+}
+
+// spanByID returns the span with the span id.
+func (env *testEnv) spanByID(t *testing.T, id trace.SpanID) tracetest.SpanStub {
+	t.Helper()
+	for _, span := range env.spans.GetSpans() {
+		if span.SpanContext.SpanID() == id {
+			return span
+		}
+	}
+	t.Fatalf("no span %v", id)
+	return tracetest.SpanStub{}
+}
+
+// attr returns the value of the attribute of the span.
+func attr(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	set := attribute.NewSet(span.Attributes...)
+	value, _ := set.Value(key)
+	return value
+}
+
+// messages returns the types of the message events of the span.
+func messages(span tracetest.SpanStub) (types []string) {
+	for _, ev := range span.Events {
+		if ev.Name == "message" {
+			set := attribute.NewSet(ev.Attributes...)
+			value, _ := set.Value("message.type")
+			types = append(types, value.AsString())
+		}
+	}
+	return types
+}
+
+func TestUnary(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	env := newTestEnv(t, ctx)
+
+	pctx, parent := env.tracer.Start(ctx, "parent")
+	in, out := "hello", ""
+	assert.NoError(t, env.cc.Invoke(pctx, "/test.Service/Unary", stringEncoding{}, &in, &out))
+	assert.Equal(t, out, "hello")
+
+	client := env.span(t, trace.SpanKindClient)
+	server := env.span(t, trace.SpanKindServer)
+
+	// the trace context is propagated from the client to the server.
+	assert.Equal(t, client.Name, "test.Service/Unary")
+	assert.Equal(t, client.Parent.SpanID(), parent.SpanContext().SpanID())
+	assert.Equal(t, server.Parent.SpanID(), client.SpanContext.SpanID())
+	assert.Equal(t, server.SpanContext.TraceID(), parent.SpanContext().TraceID())
+	assert.Equal(t, (<-env.last).SpanID(), server.SpanContext.SpanID())
+
+	for _, span := range []tracetest.SpanStub{client, server} {
+		assert.Equal(t, attr(span, "rpc.system").AsString(), "drpc")
+		assert.Equal(t, attr(span, "rpc.service").AsString(), "test.Service")
+		assert.Equal(t, attr(span, "rpc.method").AsString(), "Unary")
+		assert.Equal(t, attr(span, ErrorCodeKey).AsInt64(), int64(0))
+		assert.Equal(t, span.Status.Code, codes.Unset)
+	}
+	assert.DeepEqual(t, messages(client), []string{"SENT", "RECEIVED"})
+	assert.DeepEqual(t, messages(server), []string{"RECEIVED", "SENT"})
+}
+
+func TestUnary_TwoHops(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	env := newTestEnv(t, ctx)
+	hop := hopServer{echoServer: echoServer{last: make(chan trace.SpanContext, 1)}, next: env.cc}
+	cc := serve(t, ctx, env.ints, hop)
+
+	in, out := "hello", ""
+	assert.NoError(t, cc.Invoke(ctx, "/test.Service/Unary", stringEncoding{}, &in, &out))
+	assert.Equal(t, out, "hello")
+
+	first := env.spanByID(t, (<-hop.last).SpanID())
+	second := env.spanByID(t, (<-env.last).SpanID())
+	downstream := env.spanByID(t, second.Parent.SpanID())
+
+	// the second server is parented to the call made by the first server, not
+	// to the call made to the first server.
+	assert.Equal(t, first.SpanKind, trace.SpanKindServer)
+	assert.Equal(t, second.SpanKind, trace.SpanKindServer)
+	assert.Equal(t, downstream.SpanKind, trace.SpanKindClient)
+	assert.Equal(t, downstream.Parent.SpanID(), first.SpanContext.SpanID())
+	assert.Equal(t, second.SpanContext.TraceID(), first.SpanContext.TraceID())
+}
+
+func TestUnary_Error(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	env := newTestEnv(t, ctx)
+
+	in, out := "fail", ""
+	err := env.cc.Invoke(ctx, "/test.Service/Unary", stringEncoding{}, &in, &out)
+	assert.Equal(t, drpcerr.Code(err), drpcerr.NotFound)
+	<-env.last
+
+	client := env.span(t, trace.SpanKindClient)
+	server := env.span(t, trace.SpanKindServer)
+
+	// errors are failures of the client, but not the server unless they are
+	// caused by the server.
+	assert.Equal(t, attr(client, ErrorCodeKey).AsInt64(), int64(drpcerr.NotFound))
+	assert.Equal(t, attr(server, ErrorCodeKey).AsInt64(), int64(drpcerr.NotFound))
+	assert.Equal(t, client.Status.Code, codes.Error)
+	assert.Equal(t, server.Status.Code, codes.Unset)
+	assert.DeepEqual(t, messages(client), []string{"SENT"})
+	assert.DeepEqual(t, messages(server), []string{"RECEIVED"})
+}
+
+func TestStream(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	env := newTestEnv(t, ctx)
+
+	stream, err := env.cc.NewStream(ctx, "/test.Service/Stream", stringEncoding{})
+	assert.NoError(t, err)
+
+	var out string
+	for _, msg := range []string{"a", "b"} {
+		assert.NoError(t, stream.MsgSend(&msg, stringEncoding{}))
+		assert.NoError(t, stream.MsgRecv(&out, stringEncoding{}))
+	}
+	assert.NoError(t, stream.CloseSend())
+	assert.That(t, errors.Is(stream.MsgRecv(&out, stringEncoding{}), io.EOF))
+
+	client := env.span(t, trace.SpanKindClient)
+	server := env.span(t, trace.SpanKindServer)
+
+	assert.Equal(t, server.Parent.SpanID(), client.SpanContext.SpanID())
+	assert.Equal(t, (<-env.last).SpanID(), server.SpanContext.SpanID())
+	assert.DeepEqual(t, messages(client), []string{"SENT", "RECEIVED", "SENT", "RECEIVED"})
+	assert.DeepEqual(t, messages(server), []string{"RECEIVED", "SENT", "RECEIVED", "SENT"})
+
+	// the metrics count the messages on both sides.
+	var rm metricdata.ResourceMetrics
+	assert.NoError(t, env.reader.Collect(ctx, &rm))
+
+	counts := make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Histogram[int64]:
+				for _, dp := range data.DataPoints {
+					counts[m.Name] += dp.Sum
+				}
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					counts[m.Name] += int64(dp.Count)
+				}
+			}
+		}
+	}
+	assert.DeepEqual(t, counts, map[string]int64{
+		"rpc.server.duration":          1,
+		"rpc.server.requests_per_rpc":  2,
+		"rpc.server.responses_per_rpc": 2,
+		"rpc.client.duration":          1,
+		"rpc.client.requests_per_rpc":  2,
+		"rpc.client.responses_per_rpc": 2,
+	})
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcotel
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcctx"
+	"storj.io/drpc/drpcmux"
+)
+
+// startServer starts a span for an rpc being served, continuing the trace
+// from the metadata of the rpc.
+func (i *Interceptor) startServer(ctx context.Context, rpc string) (context.Context, *call) {
+	attrs := rpcAttributes(rpc)
+	spanAttrs := attrs
+	if tr, ok := drpcctx.Transport(ctx); ok {
+		spanAttrs = append(spanAttrs[:len(spanAttrs):len(spanAttrs)], peerAttributes(tr)...)
+	}
+
+	ctx, span := i.tracer.Start(i.extract(ctx), spanName(rpc),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(spanAttrs...))
+
+	return ctx, &call{
+		span:  span,
+		ins:   &i.server,
+		attrs: attrs,
+		kind:  trace.SpanKindServer,
+		start: time.Now(),
+	}
+}
+
+// UnaryServerInterceptor is a drpcmux.UnaryServerInterceptor that creates a
+// span for the unary rpcs served.
+func (i *Interceptor) UnaryServerInterceptor(ctx context.Context, req interface{}, rpc string, handler drpcmux.UnaryHandler) (out interface{}, err error) {
+	ctx, c := i.startServer(ctx, rpc)
+	defer func() { c.end(err) }()
+
+	// the request has already been received by the mux, and the mux sends
+	// the response after the handler returns.
+	c.messageReceived()
+	out, err = handler(ctx, req)
+	if err == nil && drpcmux.SendsOutput(out) {
+		c.messageSent()
+	}
+	return out, err
+}
+
+// StreamServerInterceptor is a drpcmux.StreamServerInterceptor that creates
+// a span for the streaming rpcs served. The span is in the context of the
+// stream passed to the handler.
+func (i *Interceptor) StreamServerInterceptor(stream drpc.Stream, rpc string, handler drpcmux.StreamHandler) (out interface{}, err error) {
+	ctx, c := i.startServer(stream.Context(), rpc)
+	defer func() { c.end(err) }()
+
+	out, err = handler(&serverStream{Stream: stream, ctx: ctx, call: c})
+	if err == nil && drpcmux.SendsOutput(out) {
+		c.messageSent()
+	}
+	return out, err
+}
+
+// serverStream adds events for the messages on a stream being served and
+// replaces its context with one that has the span.
+type serverStream struct {
+	drpc.Stream
+	ctx  context.Context
+	call *call
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+func (s *serverStream) MsgSend(msg drpc.Message, enc drpc.Encoding) error {
+	err := s.Stream.MsgSend(msg, enc)
+	if err == nil {
+		s.call.messageSent()
+	}
+	return err
+}
+
+func (s *serverStream) MsgRecv(msg drpc.Message, enc drpc.Encoding) error {
+	err := s.Stream.MsgRecv(msg, enc)
+	if err == nil {
+		s.call.messageReceived()
+	}
+	return err
+}
@@ -61,7 +61,7 @@ func (p *poolConn[K, V]) Invoke(ctx context.Context, rpc string, enc drpc.Encodi
 			return err
 		}
 	}
-	defer p.pool.Put(p.key, conn)
+	defer func() { p.replace(conn, err) }()
 
 	return conn.Invoke(ctx, rpc, enc, in, out)
 }
@@ -86,7 +86,7 @@ func (p *poolConn[K, V]) NewStream(ctx context.Context, rpc string, enc drpc.Enc
 
 	stream, err := conn.NewStream(ctx, rpc, enc)
 	if err != nil {
-		p.pool.Put(p.key, conn)
+		p.replace(conn, err)
 		return nil, err
 	}
 
@@ -102,6 +102,19 @@ func (p *poolConn[K, V]) NewStream(ctx context.Context, rpc string, enc drpc.Enc
 	return sw, nil
 }
 
+// replace puts the conn back into the Pool after an rpc finished with the error.
+// If the conn is closed or the error is from the transport being closed, like
+// when the remote is going away, the conn is unusable, so it is closed instead.
+// That way an rpc that is retried is sent on a fresh connection. Errors sent by
+// the remote, whatever their code, leave the conn usable.
+func (p *poolConn[K, V]) replace(conn V, err error) {
+	if drpc.ClosedError.Has(err) || closed(conn.Closed()) {
+		_ = conn.Close()
+		return
+	}
+	p.pool.Put(p.key, conn)
+}
+
 func (p *poolConn[K, V]) monitorStream(stream drpc.Stream, conn V, done *drpcsignal.Chan) {
 	<-stream.Context().Done()
 	p.pool.Put(p.key, conn)
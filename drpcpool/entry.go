@@ -9,11 +9,24 @@ import (
 )
 
 type entry[K comparable, V Conn] struct {
-	key    K
-	val    V
-	exp    *time.Timer
-	global node[K, V]
-	local  node[K, V]
+	key     K
+	val     V
+	exp     *time.Timer
+	done    chan struct{} // closed when the entry is removed from the pool
+	removed bool          // set when the entry is removed from the pool
+	global  node[K, V]
+	local   node[K, V]
+}
+
+// unwatch marks the entry as removed and stops any goroutine waiting for the
+// entry's connection to close. It must be called with the pool's mutex held
+// when the entry is removed.
+func (e *entry[K, V]) unwatch() {
+	e.removed = true
+	if e.done != nil {
+		close(e.done)
+		e.done = nil
+	}
 }
 
 func (e *entry[K, V]) String() string {
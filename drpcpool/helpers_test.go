@@ -5,6 +5,7 @@ package drpcpool
 
 import (
 	"context"
+	"sync/atomic"
 
 	"storj.io/drpc"
 )
@@ -88,3 +89,12 @@ func useConn(ctx context.Context, pool *Pool[string, Conn], closed chan string,
 func invoke(ctx context.Context, conn Conn) {
 	_ = conn.Invoke(ctx, "", nil, nil, nil)
 }
+
+// countingRecorder is a Recorder that counts the events.
+type countingRecorder struct {
+	hits, misses, evictions int64
+}
+
+func (r *countingRecorder) PoolHit()     { atomic.AddInt64(&r.hits, 1) }
+func (r *countingRecorder) PoolMiss()    { atomic.AddInt64(&r.misses, 1) }
+func (r *countingRecorder) PoolEvicted() { atomic.AddInt64(&r.evictions, 1) }
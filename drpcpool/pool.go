@@ -28,6 +28,24 @@ type Options struct {
 	// the Pool holds unlimited for any single key. Negative means
 	// no values for any single key.
 	KeyCapacity int
+
+	// Recorder, if set, is told about the cache hits, misses and
+	// evictions of the Pool, like to export them as metrics.
+	Recorder Recorder
+}
+
+// Recorder is told about the activity of the cache of a Pool.
+type Recorder interface {
+	// PoolHit is called when a value is taken from the cache.
+	PoolHit()
+
+	// PoolMiss is called when there is no value in the cache to take.
+	PoolMiss()
+
+	// PoolEvicted is called when a value is removed from the cache
+	// without being taken, like when it expires, is closed, is pushed
+	// out by the capacity limits or is evicted by key.
+	PoolEvicted()
 }
 
 // Pool is a connection pool with key type K. It maintains a cache of connections
@@ -55,6 +73,13 @@ func (p *Pool[K, V]) log(what string, cb func() string) {
 	}
 }
 
+// record calls the callback with the Recorder if the Pool has one.
+func (p *Pool[K, V]) record(cb func(Recorder)) {
+	if p.opts.Recorder != nil {
+		cb(p.opts.Recorder)
+	}
+}
+
 // Close evicts all entries from the Pool's cache, closing them and returning all
 // of the combined errors from closing.
 func (p *Pool[K, V]) Close() (err error) {
@@ -63,6 +88,7 @@ func (p *Pool[K, V]) Close() (err error) {
 
 	var eg errs.Group
 	for ent := p.order.head; ent != nil; ent = ent.global.next {
+		ent.unwatch()
 		eg.Add(p.closeEntry(ent))
 	}
 
@@ -72,6 +98,31 @@ func (p *Pool[K, V]) Close() (err error) {
 	return eg.Err()
 }
 
+// Evict removes all of the values cached for the key from the Pool, closing
+// them and returning all of the combined errors from closing. Values for the
+// key that are in use are not affected and may be placed back into the Pool.
+func (p *Pool[K, V]) Evict(key K) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	local := p.entries[key]
+	if local == nil {
+		return nil
+	}
+
+	var eg errs.Group
+	for ent := local.head; ent != nil; ent = ent.local.next {
+		ent.unwatch()
+		eg.Add(p.closeEntry(ent))
+		p.order.removeEntry(ent, (*entry[K, V]).globalList)
+		p.record(Recorder.PoolEvicted)
+	}
+
+	delete(p.entries, key)
+
+	return eg.Err()
+}
+
 // Get returns a new Conn that will use the provided dial function to create an
 // underlying conn to be cached by the Pool when Conn methods are invoked. It will
 // share any cached connections with other conns that use the same key.
@@ -88,21 +139,57 @@ func (p *Pool[K, V]) Get(ctx context.Context, key K,
 // helpers
 //
 
-func (p *Pool[K, V]) removeEntry(ent *entry[K, V]) {
+// removeEntry removes the entry from the cache, returning true if it was
+// still in the cache.
+func (p *Pool[K, V]) removeEntry(ent *entry[K, V]) bool {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	return p.removeEntryLocked(ent)
+}
+
+// removeEntryLocked is like removeEntry but assumes the mutex is held.
+func (p *Pool[K, V]) removeEntryLocked(ent *entry[K, V]) bool {
 	local := p.entries[ent.key]
-	if local == nil {
-		return
+	if local == nil || ent.removed {
+		return false
 	}
 
+	ent.unwatch()
 	local.removeEntry(ent, (*entry[K, V]).localList)
 	p.order.removeEntry(ent, (*entry[K, V]).globalList)
 
 	if local.count == 0 {
 		delete(p.entries, ent.key)
 	}
+	return true
+}
+
+// watchEntry waits for the entry's connection to be closed, like when the
+// remote stops responding to keepalives, and evicts it from the cache so that
+// it is not handed out. It returns early if the entry is removed first.
+func (p *Pool[K, V]) watchEntry(ent *entry[K, V], done, closed <-chan struct{}) {
+	select {
+	case <-done:
+		return
+	case <-closed:
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// the entry may have been removed while we were acquiring the mutex, and
+	// if the expiration timer has already fired, it is removing the entry.
+	if ent.done != done {
+		return
+	} else if ent.exp != nil && !ent.exp.Stop() {
+		return
+	}
+
+	p.log("EVICT", ent.String)
+	if p.removeEntryLocked(ent) {
+		p.record(Recorder.PoolEvicted)
+	}
 }
 
 // closeEntry ensures the timer and connection are closed, returning any errors.
@@ -117,7 +204,15 @@ func (p *Pool[K, V]) closeEntry(ent *entry[K, V]) error {
 
 // Take acquires a value from the cache if one exists. It returns
 // the zero value for V and false if one does not.
-func (p *Pool[K, V]) Take(key K) (V, bool) {
+func (p *Pool[K, V]) Take(key K) (val V, ok bool) {
+	defer func() {
+		if ok {
+			p.record(Recorder.PoolHit)
+		} else {
+			p.record(Recorder.PoolMiss)
+		}
+	}()
+
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -135,6 +230,7 @@ func (p *Pool[K, V]) Take(key K) (V, bool) {
 			continue
 		}
 
+		ent.unwatch()
 		local.removeEntry(ent, (*entry[K, V]).localList)
 		p.order.removeEntry(ent, (*entry[K, V]).globalList)
 
@@ -152,7 +248,8 @@ func (p *Pool[K, V]) Take(key K) (V, bool) {
 }
 
 // Put places the connection in to the cache with the provided key, ensuring
-// that the size limits the Pool is configured with are respected.
+// that the size limits the Pool is configured with are respected. The
+// connection is evicted from the cache if it becomes closed.
 func (p *Pool[K, V]) Put(key K, val V) {
 	if p.opts.Capacity < 0 || p.opts.KeyCapacity < 0 {
 		_ = val.Close()
@@ -173,16 +270,19 @@ func (p *Pool[K, V]) Put(key K, val V) {
 	for p.opts.KeyCapacity != 0 && local.count >= p.opts.KeyCapacity {
 		ent := local.head
 
+		ent.unwatch()
 		_ = p.closeEntry(ent)
 
 		local.removeEntry(ent, (*entry[K, V]).localList)
 		p.order.removeEntry(ent, (*entry[K, V]).globalList)
+		p.record(Recorder.PoolEvicted)
 	}
 
 	for p.opts.Capacity != 0 && p.order.count >= p.opts.Capacity {
 		ent := p.order.head
 		local := p.entries[ent.key]
 
+		ent.unwatch()
 		_ = p.closeEntry(ent)
 
 		local.removeEntry(ent, (*entry[K, V]).localList)
@@ -191,6 +291,7 @@ func (p *Pool[K, V]) Put(key K, val V) {
 		if local.count == 0 {
 			delete(p.entries, ent.key)
 		}
+		p.record(Recorder.PoolEvicted)
 	}
 
 	ent := &entry[K, V]{key: key, val: val}
@@ -200,8 +301,17 @@ func (p *Pool[K, V]) Put(key K, val V) {
 
 	if p.opts.Expiration > 0 {
 		ent.exp = time.AfterFunc(p.opts.Expiration, func() {
+			// the entry may have been removed already if stopping the
+			// timer lost the race with it firing.
 			_ = val.Close()
-			p.removeEntry(ent)
+			if p.removeEntry(ent) {
+				p.record(Recorder.PoolEvicted)
+			}
 		})
 	}
+
+	if ch := val.Closed(); ch != nil {
+		ent.done = make(chan struct{})
+		go p.watchEntry(ent, ent.done, ch)
+	}
 }
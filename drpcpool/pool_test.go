@@ -5,12 +5,14 @@ package drpcpool
 
 import (
 	"context"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/zeebo/assert"
 
 	"storj.io/drpc"
+	"storj.io/drpc/drpcerr"
 	"storj.io/drpc/drpctest"
 )
 
@@ -154,6 +156,95 @@ func TestPool_Stale(t *testing.T) {
 	assert.Equal(t, calls, 2)
 }
 
+// TestPool_EvictClosed checks that cached entries are evicted when they close.
+func TestPool_EvictClosed(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	dead := make(chan struct{})
+	pool := New[string, Conn](Options{})
+	defer func() { _ = pool.Close() }()
+
+	conn := pool.Get(ctx, "key", func(ctx context.Context, key string) (Conn, error) {
+		return &callbackConn{ClosedFn: func() <-chan struct{} { return dead }}, nil
+	})
+
+	// an invoke should place the conn in the cache
+	invoke(ctx, conn)
+	pool.mu.Lock()
+	assert.Equal(t, pool.order.count, 1)
+	pool.mu.Unlock()
+
+	// closing the conn should eventually evict it
+	close(dead)
+	for {
+		pool.mu.Lock()
+		count := pool.order.count
+		pool.mu.Unlock()
+
+		if count == 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPool_CloseClosed(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	pool := New[string, Conn](Options{})
+	defer func() { _ = pool.Close() }()
+
+	dials, closes := 0, 0
+	conn := pool.Get(ctx, "key", func(ctx context.Context, key string) (Conn, error) {
+		dials++
+		return &callbackConn{
+			CloseFn: func() error { closes++; return nil },
+			InvokeFn: func(ctx context.Context, rpc string, enc drpc.Encoding, in, out drpc.Message) error {
+				if rpc == "closed" {
+					return drpc.ClosedError.Wrap(drpcerr.New(drpcerr.Unavailable, "going away"))
+				}
+				return drpcerr.New(drpcerr.Unavailable, "unavailable")
+			},
+		}, nil
+	})
+
+	// errors from the remote put the conn back in the cache, even if they
+	// have the unavailable code
+	assert.Error(t, conn.Invoke(ctx, "other", nil, nil, nil))
+	assert.Error(t, conn.Invoke(ctx, "other", nil, nil, nil))
+	assert.Equal(t, dials, 1)
+	assert.Equal(t, closes, 0)
+
+	// closed errors close the conn so the next rpc dials a fresh one
+	assert.Error(t, conn.Invoke(ctx, "closed", nil, nil, nil))
+	assert.Equal(t, closes, 1)
+	assert.Error(t, conn.Invoke(ctx, "other", nil, nil, nil))
+	assert.Equal(t, dials, 2)
+}
+
+func TestPool_Evict(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	pool := New[string, Conn](Options{})
+	defer func() { _ = pool.Close() }()
+
+	closed := make(chan string, 2)
+	useConn(ctx, pool, closed, "key1")
+	useConn(ctx, pool, closed, "key2")
+
+	// evicting a key closes only the values for that key
+	assert.NoError(t, pool.Evict("key1"))
+	assert.Equal(t, <-closed, "key1")
+	assert.NoError(t, pool.Evict("key1"))
+
+	pool.mu.Lock()
+	assert.Equal(t, pool.order.count, 1)
+	pool.mu.Unlock()
+}
+
 // TestPool_Capacity checks that total capacity limits are enforced.
 func TestPool_Capacity(t *testing.T) {
 	ctx := drpctest.NewTracker(t)
@@ -445,3 +536,42 @@ func BenchmarkPool(b *testing.B) {
 		invoke(ctx, conn)
 	}
 }
+
+func TestPool_Recorder(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	rec := new(countingRecorder)
+	closed := make(chan string, 2)
+	pool := New[string, Conn](Options{Capacity: 1, Recorder: rec})
+	defer func() { _ = pool.Close() }()
+
+	conn := getConn(ctx, pool, closed, "key0")
+	invoke(ctx, conn) // miss
+	invoke(ctx, conn) // hit
+
+	// using key1 misses and pushes key0 out of the pool
+	useConn(ctx, pool, closed, "key1")
+	assert.Equal(t, <-closed, "key0")
+
+	// evicting key1 evicts its value
+	pool.mu.Lock()
+	ent := pool.order.head
+	pool.mu.Unlock()
+
+	assert.NoError(t, pool.Evict("key1"))
+	assert.Equal(t, <-closed, "key1")
+
+	// and removing it again, like when its expiration timer fires while it
+	// is evicted, does not count it twice even if key1 is cached again.
+	useConn(ctx, pool, closed, "key1")
+	assert.That(t, !pool.removeEntry(ent))
+
+	pool.mu.Lock()
+	assert.Equal(t, pool.order.count, 1)
+	pool.mu.Unlock()
+
+	assert.Equal(t, atomic.LoadInt64(&rec.hits), int64(1))
+	assert.Equal(t, atomic.LoadInt64(&rec.misses), int64(3))
+	assert.Equal(t, atomic.LoadInt64(&rec.evictions), int64(2))
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcreflect
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"storj.io/drpc"
+)
+
+// Client queries the reflection service of a server.
+type Client struct {
+	cc drpc.Conn
+}
+
+// NewClient returns a Client that queries the server on the conn.
+func NewClient(cc drpc.Conn) *Client {
+	return &Client{cc: cc}
+}
+
+// ListRPCs returns the rpcs registered with the server, sorted by name.
+func (c *Client) ListRPCs(ctx context.Context) ([]RPC, error) {
+	var out ListRPCsResponse
+	if err := c.cc.Invoke(ctx, ListRPCsRPC, encoding{}, &ListRPCsRequest{}, &out); err != nil {
+		return nil, err
+	}
+	return out.RPCs, nil
+}
+
+// FileDescriptors returns the file descriptors for the rpcs registered with
+// the server and their dependencies. Every file comes after the files it
+// imports.
+func (c *Client) FileDescriptors(ctx context.Context) ([]*descriptorpb.FileDescriptorProto, error) {
+	var out FileDescriptorsResponse
+	if err := c.cc.Invoke(ctx, FileDescriptorsRPC, encoding{}, &FileDescriptorsRequest{}, &out); err != nil {
+		return nil, err
+	}
+
+	fdps := make([]*descriptorpb.FileDescriptorProto, 0, len(out.Files))
+	for _, buf := range out.Files {
+		fdp := new(descriptorpb.FileDescriptorProto)
+		if err := proto.Unmarshal(buf, fdp); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		fdps = append(fdps, fdp)
+	}
+	return fdps, nil
+}
+
+// Files returns a registry of the file descriptors for the rpcs registered
+// with the server, which can be used to find the services and messages they
+// use by name.
+func (c *Client) Files(ctx context.Context) (*protoregistry.Files, error) {
+	fdps, err := c.FileDescriptors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	files, err := protodesc.NewFiles(&descriptorpb.FileDescriptorSet{File: fdps})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return files, nil
+}
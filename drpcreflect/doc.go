@@ -0,0 +1,13 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package drpcreflect is a reflection service that lets generic tools discover
+// the rpcs a server exposes at runtime.
+//
+// Register adds the service to a drpcmux.Mux. It lists every rpc registered
+// with the Mux along with its streaming shape, and returns the protobuf file
+// descriptors for them. Descriptors are embedded in the service descriptions
+// when protoc-gen-go-drpc is run with the descriptors=true option, and
+// otherwise are found in the global protobuf registry from the messages and
+// services the rpcs use. A Client queries the service.
+package drpcreflect
@@ -0,0 +1,110 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcreflect
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/drpc"
+)
+
+// Error is the class of errors returned by this package.
+var Error = errs.Class("drpcreflect")
+
+// The rpcs served by the reflection service.
+const (
+	ListRPCsRPC        = "/drpc.reflection.v1.Reflection/ListRPCs"
+	FileDescriptorsRPC = "/drpc.reflection.v1.Reflection/FileDescriptors"
+)
+
+// RPC describes an rpc registered with a server.
+type RPC struct {
+	// Name is the name of the rpc, like "/package.Service/Method".
+	Name string `json:"name"`
+
+	// ClientStreaming is true if the client sends a stream of messages.
+	ClientStreaming bool `json:"client_streaming,omitempty"`
+
+	// ServerStreaming is true if the server sends a stream of messages.
+	ServerStreaming bool `json:"server_streaming,omitempty"`
+
+	// InputType is the full name of the protobuf message the client sends,
+	// or the Go type if it is not a protobuf message. It is empty if unknown.
+	InputType string `json:"input_type,omitempty"`
+
+	// OutputType is the full name of the protobuf message the server sends,
+	// or the Go type if it is not a protobuf message. It is empty if unknown.
+	OutputType string `json:"output_type,omitempty"`
+}
+
+// ListRPCsRequest is the request for the ListRPCs rpc.
+type ListRPCsRequest struct{}
+
+// ListRPCsResponse is the response for the ListRPCs rpc.
+type ListRPCsResponse struct {
+	// RPCs are the rpcs registered with the server, sorted by name.
+	RPCs []RPC `json:"rpcs"`
+}
+
+// FileDescriptorsRequest is the request for the FileDescriptors rpc.
+type FileDescriptorsRequest struct{}
+
+// FileDescriptorsResponse is the response for the FileDescriptors rpc.
+type FileDescriptorsResponse struct {
+	// Files are serialized google.protobuf.FileDescriptorProto messages for
+	// the rpcs registered with the server and their dependencies. Every file
+	// comes after the files it imports.
+	Files [][]byte `json:"files"`
+}
+
+// encoding is the drpc.Encoding used by the reflection service so that it
+// does not depend on generated code.
+type encoding struct{}
+
+func (encoding) Marshal(msg drpc.Message) ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+func (encoding) Unmarshal(buf []byte, msg drpc.Message) error {
+	return json.Unmarshal(buf, msg)
+}
+
+func (encoding) JSONMarshal(msg drpc.Message) ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+func (encoding) JSONUnmarshal(buf []byte, msg drpc.Message) error {
+	return json.Unmarshal(buf, msg)
+}
+
+// reflectionServer is the interface the description dispatches to.
+type reflectionServer interface {
+	ListRPCs(context.Context, *ListRPCsRequest) (*ListRPCsResponse, error)
+	FileDescriptors(context.Context, *FileDescriptorsRequest) (*FileDescriptorsResponse, error)
+}
+
+// description is the drpc.Description of the reflection service.
+type description struct{}
+
+func (description) NumMethods() int { return 2 }
+
+func (description) Method(n int) (string, drpc.Encoding, drpc.Receiver, interface{}, bool) {
+	switch n {
+	case 0:
+		return ListRPCsRPC, encoding{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(reflectionServer).ListRPCs(ctx, in1.(*ListRPCsRequest))
+			}, reflectionServer.ListRPCs, true
+	case 1:
+		return FileDescriptorsRPC, encoding{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(reflectionServer).FileDescriptors(ctx, in1.(*FileDescriptorsRequest))
+			}, reflectionServer.FileDescriptors, true
+	default:
+		return "", nil, nil, nil, false
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcreflect
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/zeebo/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcconn"
+	"storj.io/drpc/drpcmux"
+	"storj.io/drpc/drpcserver"
+	"storj.io/drpc/drpctest"
+)
+
+// echoFile declares the echo service, which is not in the global registry.
+var echoFile = &descriptorpb.FileDescriptorProto{
+	Name:       proto.String("echo.proto"),
+	Package:    proto.String("echo"),
+	Dependency: []string{"google/protobuf/wrappers.proto"},
+	Service: []*descriptorpb.ServiceDescriptorProto{{
+		Name: proto.String("Echo"),
+		Method: []*descriptorpb.MethodDescriptorProto{{
+			Name:       proto.String("Echo"),
+			InputType:  proto.String(".google.protobuf.StringValue"),
+			OutputType: proto.String(".google.protobuf.StringValue"),
+		}},
+	}},
+	Syntax: proto.String("proto3"),
+}
+
+type echoServer interface {
+	Echo(context.Context, *wrapperspb.StringValue) (*wrapperspb.StringValue, error)
+}
+
+type echoDescription struct{}
+
+func (echoDescription) NumMethods() int { return 1 }
+
+func (echoDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver, interface{}, bool) {
+	if n != 0 {
+		return "", nil, nil, nil, false
+	}
+	return "/echo.Echo/Echo", encoding{}, nil, echoServer.Echo, true
+}
+
+type embeddedEchoDescription struct{ echoDescription }
+
+func (embeddedEchoDescription) FileDescriptor() []byte {
+	buf, _ := proto.Marshal(echoFile)
+	return buf
+}
+
+func newClient(t *testing.T, ctx *drpctest.Tracker, desc drpc.Description) *Client {
+	mux := drpcmux.New()
+	assert.NoError(t, Register(mux))
+	assert.NoError(t, mux.Register(nil, desc))
+
+	pc, ps := net.Pipe()
+	srv := drpcserver.New(mux)
+	ctx.Run(func(ctx context.Context) { _ = srv.ServeOne(ctx, ps) })
+
+	conn := drpcconn.New(pc)
+	t.Cleanup(func() { _ = conn.Close() })
+	return NewClient(conn)
+}
+
+func TestListRPCs(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	rpcs, err := newClient(t, ctx, echoDescription{}).ListRPCs(ctx)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, rpcs, []RPC{
+		{
+			Name:       FileDescriptorsRPC,
+			InputType:  "*drpcreflect.FileDescriptorsRequest",
+			OutputType: "*drpcreflect.FileDescriptorsResponse",
+		},
+		{
+			Name:       ListRPCsRPC,
+			InputType:  "*drpcreflect.ListRPCsRequest",
+			OutputType: "*drpcreflect.ListRPCsResponse",
+		},
+		{
+			Name:       "/echo.Echo/Echo",
+			InputType:  "google.protobuf.StringValue",
+			OutputType: "google.protobuf.StringValue",
+		},
+	})
+}
+
+func TestFiles(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	// without an embedded descriptor only the files of the messages are known.
+	fdps, err := newClient(t, ctx, echoDescription{}).FileDescriptors(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, len(fdps), 1)
+	assert.Equal(t, fdps[0].GetName(), "google/protobuf/wrappers.proto")
+
+	// with one, the service can be found, and the imports come first.
+	client := newClient(t, ctx, embeddedEchoDescription{})
+	fdps, err = client.FileDescriptors(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, len(fdps), 2)
+	assert.Equal(t, fdps[0].GetName(), "google/protobuf/wrappers.proto")
+	assert.Equal(t, fdps[1].GetName(), "echo.proto")
+
+	files, err := client.Files(ctx)
+	assert.NoError(t, err)
+	desc, err := files.FindDescriptorByName("echo.Echo")
+	assert.NoError(t, err)
+	method := desc.(protoreflect.ServiceDescriptor).Methods().ByName("Echo")
+	assert.Equal(t, method.Input().FullName(), protoreflect.FullName("google.protobuf.StringValue"))
+}
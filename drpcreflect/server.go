@@ -0,0 +1,230 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcreflect
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"storj.io/drpc/drpcmux"
+)
+
+// FileDescriptorDescription is implemented by the service descriptions that
+// protoc-gen-go-drpc generates with the descriptors=true option.
+type FileDescriptorDescription interface {
+	// FileDescriptor returns the serialized google.protobuf.FileDescriptorProto
+	// of the file that declares the service.
+	FileDescriptor() []byte
+}
+
+// Register registers the reflection service on the mux. The service reports
+// every rpc registered with the mux when it is called, including the ones
+// registered after it. The mux must not be registered with while it is
+// serving.
+func Register(mux *drpcmux.Mux) error {
+	return mux.Register(&server{mux: mux}, description{})
+}
+
+// server implements the reflection service for a mux.
+type server struct {
+	mux *drpcmux.Mux
+}
+
+// ListRPCs returns the rpcs registered with the mux. The shape and types of
+// an rpc come from its protobuf method descriptor if it can be found, and
+// otherwise from the types it was registered with.
+func (s *server) ListRPCs(ctx context.Context, req *ListRPCsRequest) (*ListRPCsResponse, error) {
+	infos := s.mux.RPCs()
+	rpcs := make([]RPC, 0, len(infos))
+	for _, info := range infos {
+		if mdp := methodDescriptor(info); mdp != nil {
+			rpcs = append(rpcs, RPC{
+				Name:            info.Name,
+				ClientStreaming: mdp.GetClientStreaming(),
+				ServerStreaming: mdp.GetServerStreaming(),
+				InputType:       strings.TrimPrefix(mdp.GetInputType(), "."),
+				OutputType:      strings.TrimPrefix(mdp.GetOutputType(), "."),
+			})
+			continue
+		}
+		rpcs = append(rpcs, RPC{
+			Name:            info.Name,
+			ClientStreaming: info.ClientStreaming,
+			ServerStreaming: info.ServerStreaming,
+			InputType:       typeName(info.Input),
+			OutputType:      typeName(info.Output),
+		})
+	}
+	return &ListRPCsResponse{RPCs: rpcs}, nil
+}
+
+// FileDescriptors returns the file descriptors for the rpcs registered with
+// the mux.
+func (s *server) FileDescriptors(ctx context.Context, req *FileDescriptorsRequest) (*FileDescriptorsResponse, error) {
+	var fs fileSet
+	for _, info := range s.mux.RPCs() {
+		if desc, ok := info.Description.(FileDescriptorDescription); ok {
+			if err := fs.addRaw(desc.FileDescriptor()); err != nil {
+				return nil, err
+			}
+		}
+		if sd := serviceDescriptor(info.Name); sd != nil {
+			fs.add(sd.ParentFile())
+		}
+		for _, typ := range []reflect.Type{info.Input, info.Output} {
+			if md := messageDescriptor(typ); md != nil {
+				fs.add(md.ParentFile())
+			}
+		}
+	}
+
+	files := make([][]byte, 0, len(fs.files))
+	for _, fdp := range fs.files {
+		buf, err := proto.MarshalOptions{Deterministic: true}.Marshal(fdp)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		files = append(files, buf)
+	}
+	return &FileDescriptorsResponse{Files: files}, nil
+}
+
+// fileSet collects file descriptors, keeping every file after its imports.
+type fileSet struct {
+	seen  map[string]bool
+	files []*descriptorpb.FileDescriptorProto
+}
+
+// add adds the file and its imports if they have not been added.
+func (fs *fileSet) add(fd protoreflect.FileDescriptor) {
+	if fs.seen[fd.Path()] {
+		return
+	}
+	imports := fd.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		fs.add(imports.Get(i).FileDescriptor)
+	}
+	fs.insert(protodesc.ToFileDescriptorProto(fd))
+}
+
+// addRaw adds the serialized file and the imports that can be found in the
+// global registry if they have not been added.
+func (fs *fileSet) addRaw(buf []byte) error {
+	fdp := new(descriptorpb.FileDescriptorProto)
+	if err := proto.Unmarshal(buf, fdp); err != nil {
+		return Error.Wrap(err)
+	}
+	if fs.seen[fdp.GetName()] {
+		return nil
+	}
+	for _, dep := range fdp.GetDependency() {
+		if fd, err := protoregistry.GlobalFiles.FindFileByPath(dep); err == nil {
+			fs.add(fd)
+		}
+	}
+	fs.insert(fdp)
+	return nil
+}
+
+// insert appends the file to the set.
+func (fs *fileSet) insert(fdp *descriptorpb.FileDescriptorProto) {
+	if fs.seen == nil {
+		fs.seen = make(map[string]bool)
+	}
+	fs.seen[fdp.GetName()] = true
+	fs.files = append(fs.files, fdp)
+}
+
+// splitRPC splits the rpc name into the full name of its service and the
+// name of its method.
+func splitRPC(rpc string) (service, method string) {
+	name := strings.TrimPrefix(rpc, "/")
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return name, ""
+}
+
+// methodDescriptor returns the descriptor of the method of the rpc from the
+// embedded file descriptor or the global registry, or nil if neither have it.
+func methodDescriptor(info drpcmux.RPCInfo) *descriptorpb.MethodDescriptorProto {
+	service, method := splitRPC(info.Name)
+
+	if desc, ok := info.Description.(FileDescriptorDescription); ok {
+		fdp := new(descriptorpb.FileDescriptorProto)
+		if err := proto.Unmarshal(desc.FileDescriptor(), fdp); err == nil {
+			for _, sdp := range fdp.GetService() {
+				if fullName(fdp.GetPackage(), sdp.GetName()) != service {
+					continue
+				}
+				for _, mdp := range sdp.GetMethod() {
+					if mdp.GetName() == method {
+						return mdp
+					}
+				}
+			}
+		}
+	}
+
+	if sd := serviceDescriptor(info.Name); sd != nil {
+		if md := sd.Methods().ByName(protoreflect.Name(method)); md != nil {
+			return protodesc.ToMethodDescriptorProto(md)
+		}
+	}
+	return nil
+}
+
+// fullName returns the full name of the named declaration in the package.
+func fullName(pkg, name string) string {
+	if pkg == "" {
+		return name
+	}
+	return pkg + "." + name
+}
+
+// serviceDescriptor returns the descriptor of the service of the rpc from the
+// global registry, or nil if it is not there.
+func serviceDescriptor(rpc string) protoreflect.ServiceDescriptor {
+	name, _ := splitRPC(rpc)
+	if !protoreflect.FullName(name).IsValid() {
+		return nil
+	}
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil
+	}
+	sd, _ := desc.(protoreflect.ServiceDescriptor)
+	return sd
+}
+
+// messageDescriptor returns the descriptor of the message type, or nil if it
+// is not a protobuf message.
+func messageDescriptor(typ reflect.Type) protoreflect.MessageDescriptor {
+	if typ == nil || typ.Kind() != reflect.Ptr {
+		return nil
+	}
+	msg, ok := reflect.New(typ.Elem()).Interface().(proto.Message)
+	if !ok {
+		return nil
+	}
+	return msg.ProtoReflect().Descriptor()
+}
+
+// typeName returns the name of the message type.
+func typeName(typ reflect.Type) string {
+	if typ == nil {
+		return ""
+	}
+	if md := messageDescriptor(typ); md != nil {
+		return string(md.FullName())
+	}
+	return typ.String()
+}
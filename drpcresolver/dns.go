@@ -0,0 +1,51 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcresolver
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// dnsResolver periodically looks up the addresses of a host.
+type dnsResolver struct {
+	host string
+	port string
+	opts Options
+}
+
+// newDNS constructs a resolver for a "dns:///host:port" target.
+func newDNS(endpoint string, opts Options) (Resolver, error) {
+	hostport, err := trimAuthority(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	host, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if opts.LookupHost == nil {
+		opts.LookupHost = net.DefaultResolver.LookupHost
+	}
+	return &dnsResolver{host: host, port: port, opts: opts}, nil
+}
+
+// Watch implements Resolver.
+func (d *dnsResolver) Watch(ctx context.Context, update func(addrs []string)) error {
+	return poll(ctx, d.opts.interval(30*time.Second), d.opts, d.resolve, update)
+}
+
+// resolve looks up the addresses of the host.
+func (d *dnsResolver) resolve(ctx context.Context) ([]string, error) {
+	hosts, err := d.opts.LookupHost(ctx, d.host)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	addrs := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		addrs = append(addrs, net.JoinHostPort(host, d.port))
+	}
+	return addrs, nil
+}
@@ -0,0 +1,18 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package drpcresolver resolves targets into the addresses of backends.
+//
+// A Resolver watches a target and pushes the set of addresses every time it
+// changes, so that backends can be added and removed without restarting. The
+// updates can be sent to a drpcbalancer.Conn with its SetAddresses method, or
+// to a drpcpool.Pool with PoolUpdater.
+//
+// Targets are parsed by Parse and look like
+//
+//	dns:///host:port
+//	static:///host1:port,host2:port
+//	file:///path/to/addresses
+//
+// and a target without a scheme is a single static address.
+package drpcresolver
@@ -0,0 +1,55 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcresolver
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+)
+
+// fileResolver periodically reads the addresses from a file.
+type fileResolver struct {
+	path string
+	opts Options
+}
+
+// newFile constructs a resolver for a "file://path" target, where the path is
+// everything after the "://", so "file:///etc/addrs" is the absolute path
+// "/etc/addrs".
+func newFile(endpoint string, opts Options) (Resolver, error) {
+	if endpoint == "" {
+		return nil, Error.New("missing path")
+	}
+	return &fileResolver{path: endpoint, opts: opts}, nil
+}
+
+// File returns a Resolver that watches the file at the path for addresses. The
+// file contains an address on every line. Blank lines and lines starting with
+// a '#' are ignored.
+func File(path string, opts Options) Resolver {
+	return &fileResolver{path: path, opts: opts}
+}
+
+// Watch implements Resolver.
+func (f *fileResolver) Watch(ctx context.Context, update func(addrs []string)) error {
+	return poll(ctx, f.opts.interval(time.Second), f.opts, f.resolve, update)
+}
+
+// resolve reads the addresses from the file.
+func (f *fileResolver) resolve(ctx context.Context) ([]string, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	var addrs []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			addrs = append(addrs, line)
+		}
+	}
+	return addrs, nil
+}
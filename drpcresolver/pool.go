@@ -0,0 +1,34 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcresolver
+
+import (
+	"sync"
+
+	"storj.io/drpc/drpcpool"
+)
+
+// PoolUpdater returns an update function for a Resolver that evicts the
+// connections cached by the pool, keyed by address, for the addresses that
+// are removed.
+func PoolUpdater[V drpcpool.Conn](pool *drpcpool.Pool[string, V]) func(addrs []string) {
+	var mu sync.Mutex
+	current := make(map[string]struct{})
+
+	return func(addrs []string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		next := make(map[string]struct{}, len(addrs))
+		for _, addr := range addrs {
+			next[addr] = struct{}{}
+		}
+		for addr := range current {
+			if _, ok := next[addr]; !ok {
+				_ = pool.Evict(addr)
+			}
+		}
+		current = next
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcresolver
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+)
+
+// Error is the class of errors returned by this package.
+var Error = errs.Class("drpcresolver")
+
+// Resolver watches a target for the addresses it resolves to.
+type Resolver interface {
+	// Watch calls update with the addresses of the target, and again every
+	// time they change, until the context is canceled. It returns the context
+	// error once it is canceled. Calls to update are not concurrent.
+	Watch(ctx context.Context, update func(addrs []string)) error
+}
+
+// Options controls configuration settings for the resolvers created by Parse.
+type Options struct {
+	// Interval is how often the dns and file resolvers check for changes. If
+	// zero, 30 seconds is used for dns and 1 second for files.
+	Interval time.Duration
+
+	// LookupHost is used by the dns resolver to look up the addresses of a
+	// host. If nil, the LookupHost method of net.DefaultResolver is used.
+	LookupHost func(ctx context.Context, host string) ([]string, error)
+
+	// Log is called when errors happen that can not be returned up, like
+	// temporary failures to resolve a target. It is not called if nil.
+	Log func(error)
+}
+
+// interval returns the Interval or the default if it is not set.
+func (o Options) interval(def time.Duration) time.Duration {
+	if o.Interval > 0 {
+		return o.Interval
+	}
+	return def
+}
+
+// log calls the Log option with the error if it is set.
+func (o Options) log(err error) {
+	if o.Log != nil {
+		o.Log(err)
+	}
+}
+
+// Builder constructs a Resolver for the endpoint of a target, which is the
+// part after the scheme and "://".
+type Builder func(endpoint string, opts Options) (Resolver, error)
+
+var builders = struct {
+	sync.Mutex
+	m map[string]Builder
+}{m: map[string]Builder{
+	"dns":    newDNS,
+	"static": newStatic,
+	"file":   newFile,
+}}
+
+// Register registers the builder to construct Resolvers for targets with the
+// scheme, replacing any builder already registered for it.
+func Register(scheme string, builder Builder) {
+	builders.Lock()
+	defer builders.Unlock()
+
+	builders.m[scheme] = builder
+}
+
+// Parse constructs a Resolver for the target, which is of the form
+// "scheme://endpoint". The dns, static and file schemes are always supported,
+// and others can be added with Register. A target without a scheme is a single
+// static address.
+func Parse(target string, opts Options) (Resolver, error) {
+	scheme, endpoint, ok := strings.Cut(target, "://")
+	if !ok {
+		return Static{target}, nil
+	}
+
+	builders.Lock()
+	builder := builders.m[scheme]
+	builders.Unlock()
+
+	if builder == nil {
+		return nil, Error.New("unknown scheme %q", scheme)
+	}
+	return builder(endpoint, opts)
+}
+
+// trimAuthority removes the empty authority from the endpoint, returning an
+// error if the authority is not empty.
+func trimAuthority(endpoint string) (string, error) {
+	if !strings.HasPrefix(endpoint, "/") {
+		return "", Error.New("authority not supported: %q", endpoint)
+	}
+	return endpoint[1:], nil
+}
+
+// normalize returns the addresses sorted and without duplicates or empty
+// addresses.
+func normalize(addrs []string) []string {
+	out := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			out = append(out, addr)
+		}
+	}
+	sort.Strings(out)
+
+	uniq := out[:0]
+	for i, addr := range out {
+		if i == 0 || addr != out[i-1] {
+			uniq = append(uniq, addr)
+		}
+	}
+	return uniq
+}
+
+// equal returns true if the normalized addresses are the same.
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// poll calls resolve every interval, calling update with the addresses when
+// they change. Errors resolving are logged and keep the previous addresses.
+func poll(ctx context.Context, interval time.Duration, opts Options,
+	resolve func(ctx context.Context) ([]string, error), update func([]string)) error {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last []string
+	var have bool
+	for {
+		if addrs, err := resolve(ctx); err != nil {
+			opts.log(err)
+		} else if addrs = normalize(addrs); !have || !equal(addrs, last) {
+			last, have = addrs, true
+			update(addrs)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
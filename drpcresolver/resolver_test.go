@@ -0,0 +1,174 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcresolver
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zeebo/assert"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcpool"
+	"storj.io/drpc/drpctest"
+)
+
+func TestParse(t *testing.T) {
+	r, err := Parse("host:1000", Options{})
+	assert.NoError(t, err)
+	assert.DeepEqual(t, r, Static{"host:1000"})
+
+	r, err = Parse("static:///b:1,a:1,,b:1", Options{})
+	assert.NoError(t, err)
+	assert.DeepEqual(t, r, Static{"b:1", "a:1", "", "b:1"})
+
+	r, err = Parse("dns:///host:1000", Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, r.(*dnsResolver).host, "host")
+
+	r, err = Parse("file:///etc/addrs", Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, r.(*fileResolver).path, "/etc/addrs")
+
+	_, err = Parse("dns://server/host:1000", Options{})
+	assert.Error(t, err)
+
+	_, err = Parse("dns:///host", Options{})
+	assert.Error(t, err)
+
+	_, err = Parse("unknown:///host", Options{})
+	assert.Error(t, err)
+
+	Register("custom", func(endpoint string, opts Options) (Resolver, error) {
+		return Static{endpoint}, nil
+	})
+	r, err = Parse("custom://value", Options{})
+	assert.NoError(t, err)
+	assert.DeepEqual(t, r, Static{"value"})
+}
+
+func TestStatic(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	updates := watch(ctx, Static{"b", "a", "b"})
+	assert.DeepEqual(t, <-updates, []string{"a", "b"})
+}
+
+func TestManual(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	m := NewManual("a")
+	updates := watch(ctx, m)
+	assert.DeepEqual(t, <-updates, []string{"a"})
+
+	// unchanged addresses are not sent again
+	m.Update([]string{"a"})
+	m.Update([]string{"a", "b"})
+	assert.DeepEqual(t, <-updates, []string{"a", "b"})
+
+	m.Update(nil)
+	assert.Equal(t, len(<-updates), 0)
+}
+
+func TestDNS(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	var mu sync.Mutex
+	hosts, lookupErr := []string{"10.0.0.1", "::1"}, error(nil)
+	set := func(h []string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		hosts, lookupErr = h, err
+	}
+
+	logged := make(chan error, 10)
+	r, err := Parse("dns:///host:1000", Options{
+		Interval: time.Millisecond,
+		LookupHost: func(ctx context.Context, host string) ([]string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return hosts, lookupErr
+		},
+		Log: func(err error) { logged <- err },
+	})
+	assert.NoError(t, err)
+
+	updates := watch(ctx, r)
+	assert.DeepEqual(t, <-updates, []string{"10.0.0.1:1000", "[::1]:1000"})
+
+	// errors are logged and keep the previous addresses
+	set(nil, errors.New("lookup failed"))
+	assert.Error(t, <-logged)
+
+	set([]string{"10.0.0.2"}, nil)
+	assert.DeepEqual(t, <-updates, []string{"10.0.0.2:1000"})
+}
+
+func TestFile(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	path := filepath.Join(t.TempDir(), "addrs")
+	assert.NoError(t, os.WriteFile(path, []byte("# backends\na:1\n\n  b:1  \n"), 0o644))
+
+	updates := watch(ctx, File(path, Options{Interval: time.Millisecond}))
+	assert.DeepEqual(t, <-updates, []string{"a:1", "b:1"})
+
+	assert.NoError(t, os.WriteFile(path, []byte("c:1\n"), 0o644))
+	assert.DeepEqual(t, <-updates, []string{"c:1"})
+}
+
+func TestPoolUpdater(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	pool := drpcpool.New[string, drpcpool.Conn](drpcpool.Options{})
+	defer func() { _ = pool.Close() }()
+
+	closed := make(chan string, 2)
+	for _, addr := range []string{"a", "b"} {
+		addr := addr
+		conn := pool.Get(ctx, addr, func(ctx context.Context, addr string) (drpcpool.Conn, error) {
+			return &closeConn{closed: closed, addr: addr}, nil
+		})
+		assert.NoError(t, conn.Invoke(ctx, "rpc", nil, nil, nil))
+	}
+
+	update := PoolUpdater(pool)
+	update([]string{"a", "b"})
+	update([]string{"b"})
+	assert.Equal(t, <-closed, "a")
+}
+
+// watch runs the resolver, sending every update over the returned channel.
+func watch(ctx *drpctest.Tracker, r Resolver) <-chan []string {
+	updates := make(chan []string, 10)
+	ctx.Run(func(ctx context.Context) {
+		_ = r.Watch(ctx, func(addrs []string) { updates <- addrs })
+	})
+	return updates
+}
+
+// closeConn is a drpcpool.Conn that reports its address when it is closed.
+type closeConn struct {
+	drpc.Conn
+	closed chan string
+	addr   string
+}
+
+func (c *closeConn) Close() error               { c.closed <- c.addr; return nil }
+func (c *closeConn) Closed() <-chan struct{}    { return nil }
+func (c *closeConn) Unblocked() <-chan struct{} { return nil }
+
+func (c *closeConn) Invoke(ctx context.Context, rpc string, enc drpc.Encoding, in, out drpc.Message) error {
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcresolver
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Static is a Resolver for a fixed set of addresses.
+type Static []string
+
+// newStatic constructs a resolver for a "static:///a,b,c" target.
+func newStatic(endpoint string, opts Options) (Resolver, error) {
+	addrs, err := trimAuthority(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return Static(strings.Split(addrs, ",")), nil
+}
+
+// Watch implements Resolver.
+func (s Static) Watch(ctx context.Context, update func(addrs []string)) error {
+	update(normalize(s))
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// Manual is an in-memory Resolver whose addresses are set with Update. It is
+// useful for tests.
+type Manual struct {
+	mu       sync.Mutex
+	addrs    []string
+	watchers map[chan struct{}]struct{}
+}
+
+// NewManual returns a Manual resolver with the initial addresses.
+func NewManual(addrs ...string) *Manual {
+	return &Manual{
+		addrs:    normalize(addrs),
+		watchers: make(map[chan struct{}]struct{}),
+	}
+}
+
+// Update replaces the addresses, notifying every watcher if they changed.
+func (m *Manual) Update(addrs []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	addrs = normalize(addrs)
+	if equal(addrs, m.addrs) {
+		return
+	}
+	m.addrs = addrs
+
+	for ch := range m.watchers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Watch implements Resolver.
+func (m *Manual) Watch(ctx context.Context, update func(addrs []string)) error {
+	ch := make(chan struct{}, 1)
+	ch <- struct{}{}
+
+	m.mu.Lock()
+	m.watchers[ch] = struct{}{}
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		delete(m.watchers, ch)
+		m.mu.Unlock()
+	}()
+
+	var last []string
+	var have bool
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ch:
+		}
+
+		m.mu.Lock()
+		addrs := m.addrs
+		m.mu.Unlock()
+
+		if !have || !equal(addrs, last) {
+			last, have = addrs, true
+			update(append([]string(nil), addrs...))
+		}
+	}
+}
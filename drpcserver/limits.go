@@ -0,0 +1,175 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcserver
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcmanager"
+)
+
+// rejectTimeout bounds how long a rejected connection is kept open waiting for
+// an rpc to reject.
+var rejectTimeout = time.Second
+
+// limiter keeps track of the connections and rpcs being served so that they
+// can be bounded by the server options.
+type limiter struct {
+	reject bool
+	conns  chan struct{} // nil if connections are unlimited
+	rpcs   chan struct{} // nil if rpcs are unlimited
+
+	mu    sync.Mutex
+	maxIP int
+	ips   map[string]int
+}
+
+func (l *limiter) init(opts Options) {
+	l.reject = opts.RejectOverLimit
+	if opts.MaxConnections > 0 {
+		l.conns = make(chan struct{}, opts.MaxConnections)
+	}
+	if opts.MaxConcurrentRPCs > 0 {
+		l.rpcs = make(chan struct{}, opts.MaxConcurrentRPCs)
+	}
+	if opts.MaxConnectionsPerIP > 0 {
+		l.maxIP = opts.MaxConnectionsPerIP
+		l.ips = make(map[string]int)
+	}
+}
+
+// acquire reserves a slot in the semaphore. If the limiter rejects work over
+// the limit, it returns an error coded with drpcerr.ResourceExhausted when no
+// slot is available. Otherwise, it waits for a slot until the context is
+// canceled or the stop channel is closed.
+func (l *limiter) acquire(ctx context.Context, stop <-chan struct{}, sem chan struct{}, what string) error {
+	if sem == nil {
+		return nil
+	}
+
+	select {
+	case sem <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if l.reject {
+		return drpcerr.WithCode(errs.New("too many %s", what), drpcerr.ResourceExhausted)
+	}
+
+	select {
+	case sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-stop:
+		return errs.New("server is shut down")
+	}
+}
+
+// release frees a slot in the semaphore.
+func (l *limiter) release(sem chan struct{}) {
+	if sem != nil {
+		<-sem
+	}
+}
+
+// acquireIP reserves a connection for the remote ip. Connections over the
+// limit are always rejected, because blocking the accept loop on a single
+// remote would stall every other one.
+func (l *limiter) acquireIP(ip string) error {
+	if l.ips == nil || ip == "" {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.ips[ip] >= l.maxIP {
+		return drpcerr.WithCode(errs.New("too many connections from %s", ip), drpcerr.ResourceExhausted)
+	}
+	l.ips[ip]++
+	return nil
+}
+
+// releaseIP frees a connection for the remote ip.
+func (l *limiter) releaseIP(ip string) {
+	if l.ips == nil || ip == "" {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.ips[ip]--; l.ips[ip] <= 0 {
+		delete(l.ips, ip)
+	}
+}
+
+// remoteIP returns the ip address of the remote end of the connection, or the
+// empty string if it has none.
+func remoteIP(conn net.Conn) string {
+	addr := conn.RemoteAddr()
+	if addr == nil {
+		return ""
+	} else if tcp, ok := addr.(*net.TCPAddr); ok {
+		return tcp.IP.String()
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return ""
+	}
+	return host
+}
+
+// limitConn reserves capacity for serving the connection and returns a
+// function to release it.
+func (s *Server) limitConn(ctx context.Context, conn net.Conn) (func(), error) {
+	ip := remoteIP(conn)
+	if err := s.lim.acquireIP(ip); err != nil {
+		return nil, err
+	}
+	if err := s.lim.acquire(ctx, s.stop.Signal(), s.lim.conns, "connections"); err != nil {
+		s.lim.releaseIP(ip)
+		return nil, err
+	}
+	return func() {
+		s.lim.release(s.lim.conns)
+		s.lim.releaseIP(ip)
+	}, nil
+}
+
+// limitRPC reserves capacity for handling an rpc on the stream and returns a
+// function to release it.
+func (s *Server) limitRPC(ctx context.Context) (func(), error) {
+	if err := s.lim.acquire(ctx, nil, s.lim.rpcs, "rpcs"); err != nil {
+		return nil, err
+	}
+	return func() { s.lim.release(s.lim.rpcs) }, nil
+}
+
+// rejectConn answers the first rpc invoked on the transport with the error and
+// then closes it.
+func (s *Server) rejectConn(ctx context.Context, tr net.Conn, rerr error) {
+	ctx, cancel := context.WithTimeout(ctx, rejectTimeout)
+	defer cancel()
+
+	// the transport is a net.Conn, so wrapping it in TLS does not fail.
+	str, _ := s.tlsTransport(tr)
+
+	man := drpcmanager.NewWithOptions(str, s.opts.Manager)
+	defer func() { _ = man.Close() }()
+
+	stream, _, err := man.NewServerStream(ctx)
+	if err != nil {
+		return
+	}
+	_ = stream.SendError(rerr)
+}
@@ -15,6 +15,7 @@ import (
 	"storj.io/drpc/drpccache"
 	"storj.io/drpc/drpcctx"
 	"storj.io/drpc/drpcmanager"
+	"storj.io/drpc/drpcsignal"
 	"storj.io/drpc/drpcstats"
 	"storj.io/drpc/drpcstream"
 	"storj.io/drpc/internal/drpcopts"
@@ -33,6 +34,36 @@ type Options struct {
 	// CollectStats controls whether the server should collect stats on the
 	// rpcs it serves.
 	CollectStats bool
+
+	// MaxConnections, if positive, limits the number of connections that
+	// Serve handles at once.
+	MaxConnections int
+
+	// MaxConnectionsPerIP, if positive, limits the number of connections from
+	// a single remote ip address that Serve handles at once. Connections over
+	// this limit are always rejected.
+	MaxConnectionsPerIP int
+
+	// MaxConcurrentRPCs, if positive, limits the number of rpcs handled at
+	// once across every connection.
+	MaxConcurrentRPCs int
+
+	// RejectOverLimit controls what happens when a limit is reached. If false,
+	// Serve stops accepting connections until one finishes, and rpcs wait to
+	// be handled until another rpc returns. If true, the rpc is instead failed
+	// with an error coded with drpcerr.ResourceExhausted, and a connection is
+	// closed after failing the first rpc invoked on it.
+	RejectOverLimit bool
+
+	// TLSConfig, if not nil, causes the server to serve every connection over
+	// TLS with the config. Connections that are already TLS connections are
+	// served as is. The state of the connection is available to handlers with
+	// drpcctx.TLSConnectionState.
+	TLSConfig *tls.Config
+
+	// TLSHandshakeTimeout bounds how long the TLS handshake of a connection
+	// may take. If zero, 10 seconds is used.
+	TLSHandshakeTimeout time.Duration
 }
 
 // Server is an implementation of drpc.Server to serve drpc connections.
@@ -42,6 +73,13 @@ type Server struct {
 
 	mu    sync.Mutex
 	stats map[string]*drpcstats.Stats
+	lim   limiter
+
+	// the following fields are used to shut down gracefully.
+	smu      sync.Mutex
+	stop     drpcsignal.Signal // set when Shutdown is called
+	managers map[*drpcmanager.Manager]struct{}
+	active   sync.WaitGroup // held by every connection being served
 }
 
 // New constructs a new Server.
@@ -55,8 +93,12 @@ func NewWithOptions(handler drpc.Handler, opts Options) *Server {
 	s := &Server{
 		opts:    opts,
 		handler: handler,
+
+		managers: make(map[*drpcmanager.Manager]struct{}),
 	}
 
+	s.lim.init(opts)
+
 	if s.opts.CollectStats {
 		drpcopts.SetManagerStatsCB(&s.opts.Manager.Internal, s.getStats)
 		s.stats = make(map[string]*drpcstats.Stats)
@@ -90,8 +132,120 @@ func (s *Server) getStats(rpc string) *drpcstats.Stats {
 	return stats
 }
 
-// ServeOne serves a single set of rpcs on the provided transport.
+//
+// shutdown
+//
+
+// Shutdown gracefully shuts down the server. It stops accepting connections,
+// tells every connected client not to start any new rpcs, and waits for the
+// active rpcs to finish. If the context is canceled before they do, the
+// remaining connections are closed and the context error is returned. Calls to
+// Serve return nil once the connections they accepted are finished.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.smu.Lock()
+	s.stop.Set(nil)
+	for man := range s.managers {
+		go s.goAway(man)
+	}
+	s.smu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.active.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.smu.Lock()
+	mans := make([]*drpcmanager.Manager, 0, len(s.managers))
+	for man := range s.managers {
+		mans = append(mans, man)
+	}
+	s.smu.Unlock()
+
+	for _, man := range mans {
+		_ = man.Close()
+	}
+
+	<-done
+	return ctx.Err()
+}
+
+// GracefulStop is like Shutdown except that it waits for the active rpcs to
+// finish no matter how long they take.
+func (s *Server) GracefulStop() { _ = s.Shutdown(context.Background()) }
+
+// isShutdown returns true if Shutdown has been called.
+func (s *Server) isShutdown() bool { return s.stop.IsSet() }
+
+// goAway sends a go away on the manager, logging any errors.
+func (s *Server) goAway(man *drpcmanager.Manager) {
+	if err := man.GoAway(); err != nil && s.opts.Log != nil {
+		s.opts.Log(errs.Wrap(err))
+	}
+}
+
+// trackConn adds a connection to the set of active connections. It returns
+// false if the server is shut down.
+func (s *Server) trackConn() bool {
+	s.smu.Lock()
+	defer s.smu.Unlock()
+
+	if s.stop.IsSet() {
+		return false
+	}
+	s.active.Add(1)
+	return true
+}
+
+// trackManager adds the manager to the set that is sent a go away by Shutdown,
+// sending one immediately if the server is already shutting down.
+func (s *Server) trackManager(man *drpcmanager.Manager) {
+	s.smu.Lock()
+	defer s.smu.Unlock()
+
+	s.managers[man] = struct{}{}
+	if s.stop.IsSet() {
+		go s.goAway(man)
+	}
+}
+
+// untrackManager removes the manager from the set that is sent a go away.
+func (s *Server) untrackManager(man *drpcmanager.Manager) {
+	s.smu.Lock()
+	defer s.smu.Unlock()
+
+	delete(s.managers, man)
+}
+
+//
+// serving
+//
+
+// ServeOne serves a single set of rpcs on the provided transport. If the
+// server is shut down, it finishes the active rpcs and returns nil. It closes
+// the transport and returns an error if the server was already shut down.
 func (s *Server) ServeOne(ctx context.Context, tr drpc.Transport) (err error) {
+	if !s.trackConn() {
+		return errs.Combine(errs.New("server is shut down"), tr.Close())
+	}
+	defer s.active.Done()
+
+	return s.serveOne(ctx, tr)
+}
+
+// serveOne is ServeOne for a connection that has already been tracked.
+func (s *Server) serveOne(ctx context.Context, tr drpc.Transport) (err error) {
+	tr, err = s.tlsTransport(tr)
+	if err != nil {
+		return errs.Combine(err, tr.Close())
+	}
+
 	// Check if the transport is a TLS connection
 	if tlsConn, ok := tr.(*tls.Conn); ok {
 		// Manually perform the TLS handshake to access peer certificate
@@ -107,47 +261,100 @@ func (s *Server) ServeOne(ctx context.Context, tr drpc.Transport) (err error) {
 		// interrupting any ongoing communication. Even if we didn't call it
 		// explicitly, the first read/write operation would call it internally
 		// anyway.
-		err := tlsConn.Handshake()
+		timeout := s.opts.TLSHandshakeTimeout
+		if timeout <= 0 {
+			timeout = 10 * time.Second
+		}
+		hctx, cancel := context.WithTimeout(ctx, timeout)
+		err := tlsConn.HandshakeContext(hctx)
+		cancel()
 		if err != nil {
-			return err
+			return errs.Combine(errs.Wrap(err), tr.Close())
 		}
 		state := tlsConn.ConnectionState()
+		ctx = drpcctx.WithTLSConnectionState(ctx, state)
 		if len(state.PeerCertificates) > 0 {
 			ctx = drpcctx.WithPeerConnectionInfo(
 				ctx, drpcctx.PeerConnectionInfo{Certificates: state.PeerCertificates})
 		}
 	}
 
+	// multiplexed streams are handled concurrently, and we wait for all of the
+	// handlers to return after the manager has been closed.
+	tracker := drpcctx.NewTracker(ctx)
+	defer tracker.Wait()
+	multiplexed := s.opts.Manager.MaxConcurrentStreams > 1
+
 	man := drpcmanager.NewWithOptions(tr, s.opts.Manager)
 	defer func() { err = errs.Combine(err, man.Close()) }()
 
+	s.trackManager(man)
+	defer s.untrackManager(man)
+
 	cache := drpccache.New()
 	defer cache.Clear()
 
 	ctx = drpccache.WithContext(ctx, cache)
+	ctx = drpcctx.WithTransport(ctx, tr)
 
 	for {
 		stream, rpc, err := man.NewServerStream(ctx)
 		if err != nil {
+			// once shut down, the manager stops returning streams after the
+			// ones invoked before the go away, so let the handlers finish.
+			if s.isShutdown() {
+				tracker.Wait()
+				return nil
+			}
 			return errs.Wrap(err)
 		}
+
+		if multiplexed {
+			tracker.Run(func(ctx context.Context) {
+				if err := s.handleRPC(stream, rpc); err != nil && s.opts.Log != nil {
+					s.opts.Log(errs.Wrap(err))
+				}
+			})
+			continue
+		}
+
 		if err := s.handleRPC(stream, rpc); err != nil {
 			return errs.Wrap(err)
 		}
 	}
 }
 
+// tlsTransport wraps the transport in a TLS server connection if the server is
+// configured to use TLS and it is not already a TLS connection.
+func (s *Server) tlsTransport(tr drpc.Transport) (drpc.Transport, error) {
+	if s.opts.TLSConfig == nil {
+		return tr, nil
+	}
+	switch conn := tr.(type) {
+	case *tls.Conn:
+		return conn, nil
+	case net.Conn:
+		return tls.Server(conn, s.opts.TLSConfig), nil
+	default:
+		return tr, errs.New("TLS requires the transport to be a net.Conn")
+	}
+}
+
 var temporarySleep = 500 * time.Millisecond
 
 // Serve listens for connections on the listener and serves the drpc request
-// on new connections.
+// on new connections. It returns nil when the context is canceled or, after
+// Shutdown is called, once the connections it accepted are finished.
 func (s *Server) Serve(ctx context.Context, lis net.Listener) (err error) {
 	tracker := drpcctx.NewTracker(ctx)
 	defer tracker.Wait()
 	defer tracker.Cancel()
 
 	tracker.Run(func(ctx context.Context) {
-		<-ctx.Done()
+		select {
+		case <-ctx.Done():
+		case <-s.stop.Signal():
+		}
 		_ = lis.Close()
 	})
 
@@ -158,6 +365,12 @@ func (s *Server) Serve(ctx context.Context, lis net.Listener) (err error) {
 				return nil
 			}
 
+			// wait for the connections to drain rather than canceling them.
+			if s.isShutdown() {
+				s.active.Wait()
+				return nil
+			}
+
 			if isTemporary(err) {
 				if s.opts.Log != nil {
 					s.opts.Log(err)
@@ -177,9 +390,34 @@ func (s *Server) Serve(ctx context.Context, lis net.Listener) (err error) {
 			return errs.Wrap(err)
 		}
 
-		// TODO(jeff): connection limits?
+		if !s.trackConn() {
+			_ = conn.Close()
+			continue
+		}
+
+		release, err := s.limitConn(ctx, conn)
+		if err != nil {
+			if ctx.Err() != nil || s.isShutdown() {
+				_ = conn.Close()
+				s.active.Done()
+				continue
+			}
+
+			if s.opts.Log != nil {
+				s.opts.Log(err)
+			}
+			tracker.Run(func(ctx context.Context) {
+				defer s.active.Done()
+				s.rejectConn(ctx, conn, err)
+			})
+			continue
+		}
+
 		tracker.Run(func(ctx context.Context) {
-			err := s.ServeOne(ctx, conn)
+			defer s.active.Done()
+			defer release()
+
+			err := s.serveOne(ctx, conn)
 			if err != nil && s.opts.Log != nil {
 				s.opts.Log(err)
 			}
@@ -189,6 +427,12 @@ func (s *Server) Serve(ctx context.Context, lis net.Listener) (err error) {
 
 // handleRPC handles the rpc that has been requested by the stream.
 func (s *Server) handleRPC(stream *drpcstream.Stream, rpc string) (err error) {
+	release, err := s.limitRPC(stream.Context())
+	if err != nil {
+		return errs.Wrap(stream.SendError(err))
+	}
+	defer release()
+
 	err = s.handler.HandleRPC(stream, rpc)
 	if err != nil {
 		return errs.Wrap(stream.SendError(err))
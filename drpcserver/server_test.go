@@ -4,11 +4,19 @@
 package drpcserver
 
 import (
+	"context"
 	"net"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/zeebo/assert"
 
+	"storj.io/drpc"
+	"storj.io/drpc/drpcconn"
+	"storj.io/drpc/drpcctx"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcmanager"
 	"storj.io/drpc/drpctest"
 )
 
@@ -46,3 +54,304 @@ type temporaryError struct{}
 func (temporaryError) Error() string   { return "temporary error" }
 func (temporaryError) Timeout() bool   { return false }
 func (temporaryError) Temporary() bool { return true }
+
+// Dummy encoding, which assumes the drpc.Message is a *string.
+type testEncoding struct{}
+
+func (testEncoding) Marshal(msg drpc.Message) ([]byte, error) {
+	return []byte(*msg.(*string)), nil
+}
+
+func (testEncoding) Unmarshal(buf []byte, msg drpc.Message) error {
+	*msg.(*string) = string(buf)
+	return nil
+}
+
+type handlerFunc func(stream drpc.Stream, rpc string) error
+
+func (fn handlerFunc) HandleRPC(stream drpc.Stream, rpc string) error { return fn(stream, rpc) }
+
+func TestServerMultiplexed(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	const calls = 4
+
+	// every handler waits for all of the calls to arrive, so the rpcs must be
+	// served concurrently for any of them to return.
+	var wg sync.WaitGroup
+	wg.Add(calls)
+
+	srv := NewWithOptions(handlerFunc(func(stream drpc.Stream, rpc string) error {
+		var in string
+		if err := stream.MsgRecv(&in, testEncoding{}); err != nil {
+			return err
+		}
+		wg.Done()
+		wg.Wait()
+		return stream.MsgSend(&in, testEncoding{})
+	}), Options{
+		Manager: drpcmanager.Options{MaxConcurrentStreams: calls},
+	})
+
+	pc, ps := net.Pipe()
+	defer func() { _ = pc.Close() }()
+
+	ctx.Run(func(ctx context.Context) { _ = srv.ServeOne(ctx, ps) })
+
+	conn := drpcconn.NewWithOptions(pc, drpcconn.Options{
+		Manager: drpcmanager.Options{MaxConcurrentStreams: calls},
+	})
+	defer func() { _ = conn.Close() }()
+
+	outs := make(chan string, calls)
+	for i := 0; i < calls; i++ {
+		in := string(rune('a' + i))
+		ctx.Run(func(ctx context.Context) {
+			var out string
+			assert.NoError(t, conn.Invoke(ctx, "rpc", testEncoding{}, &in, &out))
+			outs <- out
+		})
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i < calls; i++ {
+		seen[<-outs] = true
+	}
+	assert.Equal(t, len(seen), calls)
+}
+
+func TestServerTransport(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	pc, ps := net.Pipe()
+	defer func() { _ = pc.Close() }()
+
+	srv := New(handlerFunc(func(stream drpc.Stream, rpc string) error {
+		tr, ok := drpcctx.Transport(stream.Context())
+		assert.That(t, ok)
+		assert.Equal(t, tr, ps)
+
+		var in string
+		if err := stream.MsgRecv(&in, testEncoding{}); err != nil {
+			return err
+		}
+		return stream.MsgSend(&in, testEncoding{})
+	}))
+	ctx.Run(func(ctx context.Context) { _ = srv.ServeOne(ctx, ps) })
+
+	conn := drpcconn.New(pc)
+	defer func() { _ = conn.Close() }()
+
+	in, out := "in", ""
+	assert.NoError(t, conn.Invoke(ctx, "rpc", testEncoding{}, &in, &out))
+}
+
+func TestServerShutdown(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	srv := New(handlerFunc(func(stream drpc.Stream, rpc string) error {
+		var in string
+		if err := stream.MsgRecv(&in, testEncoding{}); err != nil {
+			return err
+		}
+		close(started)
+		<-release
+		return stream.MsgSend(&in, testEncoding{})
+	}))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	served := make(chan error, 1)
+	ctx.Run(func(ctx context.Context) { served <- srv.Serve(ctx, lis) })
+
+	raw, err := net.Dial("tcp", lis.Addr().String())
+	assert.NoError(t, err)
+	conn := drpcconn.New(raw)
+	defer func() { _ = conn.Close() }()
+
+	invoked := make(chan error, 1)
+	ctx.Run(func(ctx context.Context) {
+		in, out := "hello", ""
+		invoked <- conn.Invoke(ctx, "rpc", testEncoding{}, &in, &out)
+	})
+	<-started
+
+	shutdown := make(chan error, 1)
+	ctx.Run(func(ctx context.Context) { shutdown <- srv.Shutdown(ctx) })
+
+	// the in-flight rpc is allowed to finish.
+	select {
+	case <-shutdown:
+		t.Fatal("shutdown returned with an active rpc")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(release)
+
+	assert.NoError(t, <-invoked)
+	assert.NoError(t, <-shutdown)
+	assert.NoError(t, <-served)
+
+	// the client closes the connection after being told to go away.
+	<-conn.Closed()
+
+	// new connections are refused.
+	_, err = net.Dial("tcp", lis.Addr().String())
+	assert.Error(t, err)
+}
+
+func TestServerShutdown_Deadline(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	started := make(chan struct{})
+	srv := New(handlerFunc(func(stream drpc.Stream, rpc string) error {
+		close(started)
+		<-stream.Context().Done()
+		return stream.Context().Err()
+	}))
+
+	pc, ps := net.Pipe()
+	defer func() { _ = pc.Close() }()
+
+	served := make(chan error, 1)
+	ctx.Run(func(ctx context.Context) { served <- srv.ServeOne(ctx, ps) })
+
+	conn := drpcconn.New(pc)
+	defer func() { _ = conn.Close() }()
+
+	invoked := make(chan error, 1)
+	ctx.Run(func(ctx context.Context) {
+		in, out := "hello", ""
+		invoked <- conn.Invoke(ctx, "rpc", testEncoding{}, &in, &out)
+	})
+	<-started
+
+	// the rpc never finishes on its own, so it is forcibly closed.
+	sctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, srv.Shutdown(sctx), context.DeadlineExceeded)
+	assert.Error(t, <-invoked)
+	<-served
+
+	// the server does not serve any more transports.
+	assert.Error(t, srv.ServeOne(ctx, ps))
+}
+
+func TestServerLimits(t *testing.T) {
+	// blockingServer returns a server whose handlers wait for the release
+	// channel to be closed, and a channel that is sent on when one starts.
+	blockingServer := func(opts Options) (*Server, chan struct{}, chan struct{}) {
+		started, release := make(chan struct{}, 10), make(chan struct{})
+		return NewWithOptions(handlerFunc(func(stream drpc.Stream, rpc string) error {
+			var in string
+			if err := stream.MsgRecv(&in, testEncoding{}); err != nil {
+				return err
+			}
+			started <- struct{}{}
+			<-release
+			return stream.MsgSend(&in, testEncoding{})
+		}), opts), started, release
+	}
+
+	serve := func(ctx *drpctest.Tracker, srv *Server) string {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		ctx.Run(func(ctx context.Context) { _ = srv.Serve(ctx, lis) })
+		return lis.Addr().String()
+	}
+
+	dial := func(addr string) *drpcconn.Conn {
+		raw, err := net.Dial("tcp", addr)
+		assert.NoError(t, err)
+		return drpcconn.New(raw)
+	}
+
+	invoke := func(ctx *drpctest.Tracker, conn *drpcconn.Conn) chan error {
+		errs := make(chan error, 1)
+		ctx.Run(func(ctx context.Context) {
+			in, out := "hello", ""
+			errs <- conn.Invoke(ctx, "rpc", testEncoding{}, &in, &out)
+		})
+		return errs
+	}
+
+	t.Run("RPCs", func(t *testing.T) {
+		ctx := drpctest.NewTracker(t)
+		defer ctx.Close()
+
+		srv, started, release := blockingServer(Options{
+			MaxConcurrentRPCs: 1,
+			RejectOverLimit:   true,
+		})
+		addr := serve(ctx, srv)
+
+		conn1, conn2 := dial(addr), dial(addr)
+		defer func() { _ = conn1.Close() }()
+		defer func() { _ = conn2.Close() }()
+
+		first := invoke(ctx, conn1)
+		<-started
+
+		err := <-invoke(ctx, conn2)
+		assert.Equal(t, drpcerr.Code(err), drpcerr.ResourceExhausted)
+
+		close(release)
+		assert.NoError(t, <-first)
+		assert.NoError(t, <-invoke(ctx, conn2))
+	})
+
+	t.Run("PerIP", func(t *testing.T) {
+		ctx := drpctest.NewTracker(t)
+		defer ctx.Close()
+
+		srv, started, release := blockingServer(Options{
+			MaxConnectionsPerIP: 1,
+		})
+		defer close(release)
+		addr := serve(ctx, srv)
+
+		conn1, conn2 := dial(addr), dial(addr)
+		defer func() { _ = conn1.Close() }()
+		defer func() { _ = conn2.Close() }()
+
+		_ = invoke(ctx, conn1)
+		<-started
+
+		err := <-invoke(ctx, conn2)
+		assert.Equal(t, drpcerr.Code(err), drpcerr.ResourceExhausted)
+	})
+
+	t.Run("BlockingConnections", func(t *testing.T) {
+		ctx := drpctest.NewTracker(t)
+		defer ctx.Close()
+
+		srv, started, release := blockingServer(Options{
+			MaxConnections: 1,
+		})
+		close(release)
+		addr := serve(ctx, srv)
+
+		conn1, conn2 := dial(addr), dial(addr)
+		defer func() { _ = conn2.Close() }()
+
+		assert.NoError(t, <-invoke(ctx, conn1))
+		<-started
+
+		// the second connection is not served until the first is closed.
+		second := invoke(ctx, conn2)
+		select {
+		case <-second:
+			t.Fatal("second connection served while at the limit")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		assert.NoError(t, conn1.Close())
+		assert.NoError(t, <-second)
+	})
+}
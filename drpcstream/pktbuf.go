@@ -12,6 +12,7 @@ type packetBuffer struct {
 	cond sync.Cond
 	err  error
 	data []byte
+	comp bool
 	set  bool
 	held bool
 }
@@ -36,7 +37,7 @@ func (pb *packetBuffer) Close(err error) {
 	}
 }
 
-func (pb *packetBuffer) Put(data []byte) {
+func (pb *packetBuffer) Put(data []byte, comp bool) {
 	pb.mu.Lock()
 	defer pb.mu.Unlock()
 
@@ -48,6 +49,7 @@ func (pb *packetBuffer) Put(data []byte) {
 	}
 
 	pb.data = data
+	pb.comp = comp
 	pb.set = true
 	pb.held = false
 	pb.cond.Broadcast()
@@ -57,7 +59,7 @@ func (pb *packetBuffer) Put(data []byte) {
 	}
 }
 
-func (pb *packetBuffer) Get() ([]byte, error) {
+func (pb *packetBuffer) Get() ([]byte, bool, error) {
 	pb.mu.Lock()
 	defer pb.mu.Unlock()
 
@@ -65,13 +67,13 @@ func (pb *packetBuffer) Get() ([]byte, error) {
 		pb.cond.Wait()
 	}
 	if pb.err != nil {
-		return nil, pb.err
+		return nil, false, pb.err
 	}
 
 	pb.held = true
 	pb.cond.Broadcast()
 
-	return pb.data, nil
+	return pb.data, pb.comp, nil
 }
 
 func (pb *packetBuffer) Done() {
@@ -5,17 +5,22 @@ package drpcstream
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"runtime/trace"
 	"sync"
+	"sync/atomic"
 
 	"github.com/zeebo/errs"
 
 	"storj.io/drpc"
+	"storj.io/drpc/drpccompress"
 	"storj.io/drpc/drpcctx"
 	"storj.io/drpc/drpcdebug"
 	"storj.io/drpc/drpcenc"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcmetadata"
 	"storj.io/drpc/drpcsignal"
 	"storj.io/drpc/drpcwire"
 	"storj.io/drpc/internal/drpcopts"
@@ -37,6 +42,42 @@ type Options struct {
 	// more allocations. 0 is unlimited.
 	MaximumBufferSize int
 
+	// InitialWindowSize, if positive, enables flow control for the stream. It
+	// is the number of bytes of message data that may be sent to the stream
+	// before the remote must wait for it to be read. Clients advertise it in
+	// the invoke metadata, and servers only use flow control with clients
+	// that advertise a window, telling them their own window in response.
+	// Until a client has been told the server's window, it does not limit
+	// what it sends, so remotes that do not support flow control keep
+	// working. A remote that sends more than it was allowed to fails the
+	// stream.
+	InitialWindowSize int
+
+	// MaxWindowSize is the largest size that the window is allowed to grow to
+	// as the stream is read from. If it is not larger than InitialWindowSize,
+	// the window does not grow.
+	MaxWindowSize int
+
+	// Compression is the name of a compressor registered with drpccompress
+	// that client streams compress the messages they send with. It is
+	// advertised to the server in the invoke metadata, and a server that has
+	// it registered confirms it in the response header and compresses the
+	// messages it sends with it. Clients only compress once the server has
+	// confirmed it, so messages sent before the header is received, like the
+	// request of a unary rpc, are not compressed, and remotes that do not
+	// support compression keep working. Servers use the compressor advertised
+	// by the client instead.
+	Compression string
+
+	// CompressionThreshold is the size in bytes a message must be before it is
+	// compressed. Smaller messages are sent uncompressed. If zero, 1024 is
+	// used.
+	CompressionThreshold int
+
+	// MaximumDecompressedSize is the largest size that a received message is
+	// allowed to decompress to. If zero, 4MiB is used.
+	MaximumDecompressedSize int
+
 	// Internal contains options that are for internal use only.
 	Internal drpcopts.Stream
 }
@@ -56,14 +97,35 @@ type Stream struct {
 	wr   *drpcwire.Writer
 	pbuf packetBuffer
 	wbuf []byte
-
-	mu   sync.Mutex // protects state transitions
-	sigs struct {
+	comp drpccompress.Compressor
+	cmpr atomic.Bool // set when the remote is known to support the compressor
+	cbuf []byte      // buffer for compressed messages being sent
+	dbuf []byte      // buffer for decompressed messages being received
+	swin sendWindow
+	rwin recvWindow
+	sent int64 // message data sent, protected by the write lock
+
+	unflushed bool // set when the stream has written without a flush
+	acked     bool // set when the client has started using the server's window, protected by the write lock
+	hdrDone   bool // set when the header has been sent, protected by the write lock
+
+	mdmu    sync.Mutex        // protects the metadata to send
+	header  map[string]string // header metadata to send
+	trailer map[string]string // trailer metadata to send
+	hdrSent bool              // set when the header can no longer be changed
+	trlSent bool              // set when the trailer can no longer be changed
+
+	mu       sync.Mutex        // protects state transitions
+	details  []drpcerr.Detail  // details for the error the remote is sending
+	rheader  map[string]string // header metadata the remote sent
+	rtrailer map[string]string // trailer metadata the remote sent
+	sigs     struct {
 		send   drpcsignal.Signal // set when done sending messages
 		recv   drpcsignal.Signal // set when done receiving messages
 		term   drpcsignal.Signal // set when the stream is terminating and no new ops should begin
 		fin    drpcsignal.Signal // set when the stream is finished and all ops are complete
 		cancel drpcsignal.Signal // set when externally canceled
+		header drpcsignal.Signal // set when the header from the remote is available
 	}
 }
 
@@ -99,12 +161,49 @@ func NewWithOptions(ctx context.Context, sid uint64, wr *drpcwire.Writer, opts O
 		task: task,
 
 		id: drpcwire.ID{Stream: sid},
-		wr: wr.Reset(),
+		wr: wr,
+	}
+
+	// handlers for server streams can send response metadata through the
+	// context of the stream.
+	if drpcopts.GetStreamServer(&opts.Internal) {
+		s.ctx.rw = s
+	}
+
+	// a multiplexed stream shares the writer with other active streams, so
+	// any buffered data belongs to them and must not be discarded.
+	if !drpcopts.GetStreamMultiplexed(&opts.Internal) {
+		s.wr.Reset()
 	}
 
 	// initialize the packet buffer
 	s.pbuf.init()
 
+	// look up the compressor, if any. an unknown compressor means that sent
+	// messages are not compressed and received compressed messages fail. a
+	// server only has a compressor if the client advertised it, so it can
+	// compress right away, and it confirms the compressor in the header.
+	if opts.Compression != "" {
+		s.comp, _ = drpccompress.Get(opts.Compression)
+	}
+	if s.comp != nil && s.server() {
+		s.cmpr.Store(true)
+		s.header = map[string]string{drpcmetadata.CompressionKey: opts.Compression}
+	}
+
+	// initialize the flow control windows. a server only uses flow control if
+	// the client advertised a window, and it tells the client its own window
+	// before sending anything else. a client does not use its windows until
+	// it has been told.
+	remote := drpcopts.GetStreamWindow(&opts.Internal)
+	negotiated := s.server() && opts.InitialWindowSize > 0 && remote > 0
+	s.swin.init(negotiated, remote)
+	s.rwin.init(negotiated, opts.InitialWindowSize, opts.MaxWindowSize)
+	if negotiated {
+		// any error is left to be noticed by the next write on the stream.
+		_ = s.sendPacketLocked(drpcwire.KindWindowUpdate, true, drpcwire.AppendVarint(nil, uint64(opts.InitialWindowSize)))
+	}
+
 	return s
 }
 
@@ -132,16 +231,20 @@ func (s *Stream) log(what string, cb func() string) {
 type streamCtx struct {
 	context.Context
 	tr  drpc.Transport
+	rw  drpcmetadata.ResponseWriter
 	sig drpcsignal.Signal
 }
 
-// Value checks for the drpc.Transport key and forwards if necessary.
-// We do this because using drpcctx to make a new context would cause
-// an extra allocation.
+// Value checks for the drpc.Transport and drpcmetadata.ResponseWriter keys
+// and forwards if necessary. We do this because using drpcctx to make a new
+// context would cause an extra allocation.
 func (s *streamCtx) Value(key interface{}) interface{} {
 	if s.tr != nil && key == (drpcctx.TransportKey{}) {
 		return s.tr
 	}
+	if s.rw != nil && key == (drpcmetadata.ResponseWriterKey{}) {
+		return s.rw
+	}
 	return s.Context.Value(key)
 }
 
@@ -227,11 +330,21 @@ func (s *Stream) HandlePacket(pkt drpcwire.Packet) (err error) {
 
 	s.log("HANDLE", pkt.String)
 
-	if pkt.Kind == drpcwire.KindMessage {
-		s.pbuf.Put(pkt.Data)
+	if pkt.Kind == drpcwire.KindMessage || pkt.Kind == drpcwire.KindCompressedMessage {
+		if !drpcopts.GetStreamMultiplexed(&s.opts.Internal) && !s.CheckWindow(len(pkt.Data)) {
+			return nil
+		}
+		s.sigs.header.Set(nil) // any header is sent before the first message
+		s.pbuf.Put(pkt.Data, pkt.Kind == drpcwire.KindCompressedMessage)
 		return nil
 	}
 
+	// window updates do not change the state of the stream and may be handled
+	// concurrently with the other packets for it.
+	if pkt.Kind == drpcwire.KindWindowUpdate {
+		return s.handleWindowUpdate(pkt)
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -241,8 +354,33 @@ func (s *Stream) HandlePacket(pkt drpcwire.Packet) (err error) {
 		s.terminate(err)
 		return err
 
+	case drpcwire.KindErrorDetails:
+		details, err := drpcwire.UnmarshalErrorDetails(pkt.Data)
+		if err != nil {
+			err := drpc.ProtocolError.Wrap(err)
+			s.terminate(err)
+			return err
+		}
+		s.details = details
+		return nil
+
+	case drpcwire.KindHeader, drpcwire.KindTrailer:
+		md, err := drpcmetadata.Decode(pkt.Data)
+		if err != nil {
+			err := drpc.ProtocolError.Wrap(err)
+			s.terminate(err)
+			return err
+		}
+		if pkt.Kind == drpcwire.KindHeader {
+			s.rheader = s.confirmCompression(md)
+			s.sigs.header.Set(nil)
+		} else {
+			s.rtrailer = md
+		}
+		return nil
+
 	case drpcwire.KindError:
-		err := drpcwire.UnmarshalError(pkt.Data)
+		err := drpcerr.WithDetails(drpcwire.UnmarshalError(pkt.Data), s.details...)
 		s.sigs.send.Set(io.EOF) // in this state, gRPC returns io.EOF on send.
 		s.terminate(err)
 		return nil
@@ -278,10 +416,194 @@ func (s *Stream) HandlePacket(pkt drpcwire.Packet) (err error) {
 	}
 }
 
+// CheckWindow records that a message with n bytes of data arrived for the
+// stream. If the remote did not have the credit to send it, the stream is
+// terminated and it returns false. HandlePacket calls it for streams that are
+// not multiplexed, and for multiplexed streams, it must be called when the
+// message arrives rather than when it is handled.
+func (s *Stream) CheckWindow(n int) bool {
+	if s.rwin.Receive(n) {
+		return true
+	}
+
+	s.log("WINDOW", func() string { return fmt.Sprint(n) })
+
+	s.mu.Lock()
+	s.terminate(drpcerr.WithCode(drpc.ProtocolError.New("remote exceeded the flow control window"), drpcerr.ResourceExhausted))
+	s.mu.Unlock()
+	return false
+}
+
+// handleWindowUpdate grants the credit in the window update packet to the
+// stream if it is using flow control. The first window update a client
+// receives is the server's window, and the first one a server receives also
+// carries how much message data the client sent before using that window.
+func (s *Stream) handleWindowUpdate(pkt drpcwire.Packet) error {
+	if s.opts.InitialWindowSize <= 0 {
+		return nil
+	}
+
+	rem, credit, ok, err := drpcwire.ReadVarint(pkt.Data)
+	if !ok || err != nil {
+		return s.invalidWindowUpdate()
+	}
+
+	if !s.server() {
+		if s.swin.Enable(credit) {
+			s.rwin.Enforce(0)
+			return nil
+		}
+	} else if !s.swin.Enabled() {
+		return nil
+	} else if len(rem) > 0 {
+		_, offset, ok, err := drpcwire.ReadVarint(rem)
+		if !ok || err != nil {
+			return s.invalidWindowUpdate()
+		}
+		s.rwin.Enforce(offset)
+	}
+
+	s.swin.Add(credit)
+	return nil
+}
+
+// invalidWindowUpdate terminates the stream with an error for an invalid window
+// update and returns it.
+func (s *Stream) invalidWindowUpdate() error {
+	err := drpc.ProtocolError.New("invalid window update")
+	s.mu.Lock()
+	s.terminate(err)
+	s.mu.Unlock()
+	return err
+}
+
 //
 // helpers
 //
 
+// server returns true if the stream is the server side of an rpc.
+func (s *Stream) server() bool { return drpcopts.GetStreamServer(&s.opts.Internal) }
+
+// waitWindow blocks until the remote has granted credit to send a message if
+// the stream is using flow control. It must be called before acquiring the
+// write lock so that the stream can still be terminated while waiting.
+func (s *Stream) waitWindow() error {
+	if s.opts.InitialWindowSize <= 0 {
+		return nil
+	}
+	return s.checkCancelError(s.swin.Wait())
+}
+
+// consumeWindow records that n bytes of message data were read and sends the
+// remote a window update if enough of the window has been consumed. It must be
+// called while holding the read lock. Any error sending the update is left to
+// be noticed by the next write on the stream or the transport.
+func (s *Stream) consumeWindow(n int) {
+	if s.opts.InitialWindowSize <= 0 || !s.rwin.Active() {
+		return
+	}
+
+	credit := s.rwin.Consume(n)
+	if credit == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	if s.sigs.recv.IsSet() || s.sigs.term.IsSet() {
+		s.mu.Unlock()
+		return
+	}
+
+	s.write.Lock()
+	defer s.write.Unlock()
+	s.mu.Unlock()
+
+	s.rwin.Grant(credit)
+	_ = s.sendPacketLocked(drpcwire.KindWindowUpdate, true, s.windowUpdateLocked(credit))
+}
+
+// windowUpdateLocked returns the body of a window update granting the credit.
+// The first one a client sends after it starts using the server's window also
+// tells the server how much message data was sent before then, so that the
+// server knows which data has to fit in the window. It must be called while
+// holding the write lock.
+func (s *Stream) windowUpdateLocked(credit uint64) []byte {
+	data := drpcwire.AppendVarint(nil, credit)
+	if !s.server() && !s.acked {
+		s.acked = true
+		data = drpcwire.AppendVarint(data, uint64(s.sent))
+	}
+	return data
+}
+
+// compressLocked returns the kind and data to send for the message data,
+// compressing it if the stream is using compression and the message is large
+// enough. It must be called while holding the write lock.
+func (s *Stream) compressLocked(data []byte) (drpcwire.Kind, []byte, error) {
+	threshold := s.opts.CompressionThreshold
+	if threshold <= 0 {
+		threshold = 1024
+	}
+	if s.comp == nil || !s.cmpr.Load() || len(data) < threshold {
+		return drpcwire.KindMessage, data, nil
+	}
+
+	cbuf, err := s.comp.Compress(s.cbuf[:0], data)
+	if err != nil {
+		return 0, nil, errs.Wrap(err)
+	}
+	if s.opts.MaximumBufferSize == 0 || len(cbuf) < s.opts.MaximumBufferSize {
+		s.cbuf = cbuf
+	}
+
+	// incompressible messages are sent as is to save the remote the work.
+	if len(cbuf) >= len(data) {
+		return drpcwire.KindMessage, data, nil
+	}
+	return drpcwire.KindCompressedMessage, cbuf, nil
+}
+
+// confirmCompression enables compression if the header from the remote
+// confirms the compressor of the stream, and returns the header without the
+// confirmation.
+func (s *Stream) confirmCompression(md map[string]string) map[string]string {
+	name, ok := md[drpcmetadata.CompressionKey]
+	if !ok {
+		return md
+	}
+	delete(md, drpcmetadata.CompressionKey)
+	if s.comp != nil && !s.server() && name == s.opts.Compression {
+		s.cmpr.Store(true)
+	}
+	if len(md) == 0 {
+		return nil
+	}
+	return md
+}
+
+// decompressLocked returns the decompressed form of the data of a compressed
+// message. It must be called while holding the read lock, and the returned
+// data is only valid until the next call.
+func (s *Stream) decompressLocked(data []byte) ([]byte, error) {
+	if s.comp == nil {
+		return nil, drpcerr.Newf(drpcerr.Unimplemented, "compressor %q is not registered", s.opts.Compression)
+	}
+
+	max := s.opts.MaximumDecompressedSize
+	if max <= 0 {
+		max = 4 << 20
+	}
+
+	dbuf, err := s.comp.Decompress(s.dbuf[:0], data, max)
+	if err != nil {
+		return nil, drpcerr.WithCode(errs.Wrap(err), drpcerr.Internal)
+	}
+	if s.opts.MaximumBufferSize == 0 || len(dbuf) < s.opts.MaximumBufferSize {
+		s.dbuf = dbuf
+	}
+	return dbuf, nil
+}
+
 // checkFinished checks to see if the stream is terminated, and if so, sets the
 // finished flag. This must be called after every read or write is complete, as
 // well as when the stream becomes terminated.
@@ -289,7 +611,11 @@ func (s *Stream) checkFinished() {
 	if s.sigs.term.IsSet() && s.write.Unlocked() && s.read.Unlocked() {
 		if s.sigs.fin.Set(nil) {
 			s.log("FIN", func() string { return "" })
-			s.ctx.sig.Set(context.Canceled)
+			if errors.Is(s.sigs.term.Err(), context.DeadlineExceeded) {
+				s.ctx.sig.Set(context.DeadlineExceeded)
+			} else {
+				s.ctx.sig.Set(context.Canceled)
+			}
 			if s.fin != nil {
 				s.fin <- struct{}{}
 			}
@@ -321,6 +647,20 @@ func (s *Stream) newFrameLocked(kind drpcwire.Kind) drpcwire.Frame {
 // check for any conditions to stop it from writing and is meant for internal
 // stream use to do things like signal errors or closes to the remote side.
 func (s *Stream) sendPacketLocked(kind drpcwire.Kind, control bool, data []byte) (err error) {
+	if err := s.writePacketLocked(kind, control, data); err != nil {
+		return err
+	}
+	if err := s.wr.Flush(); err != nil {
+		return errs.Wrap(err)
+	}
+	s.unflushed = false
+	return nil
+}
+
+// writePacketLocked writes the packet in a single frame without flushing. Like
+// sendPacketLocked, it does not check for any conditions to stop it from
+// writing.
+func (s *Stream) writePacketLocked(kind drpcwire.Kind, control bool, data []byte) (err error) {
 	fr := s.newFrameLocked(kind)
 	fr.Data = data
 	fr.Control = control
@@ -329,13 +669,7 @@ func (s *Stream) sendPacketLocked(kind drpcwire.Kind, control bool, data []byte)
 	drpcopts.GetStreamStats(&s.opts.Internal).AddWritten(uint64(len(data)))
 	s.log("SEND", fr.String)
 
-	if err := s.wr.WriteFrame(fr); err != nil {
-		return errs.Wrap(err)
-	}
-	if err := s.wr.Flush(); err != nil {
-		return errs.Wrap(err)
-	}
-	return nil
+	return errs.Wrap(s.wr.WriteFrame(fr))
 }
 
 // terminateIfBothClosed is a helper to terminate the stream if both sides have
@@ -352,7 +686,9 @@ func (s *Stream) terminate(err error) {
 	s.sigs.send.Set(err)
 	s.sigs.recv.Set(err)
 	s.sigs.term.Set(err)
+	s.sigs.header.Set(nil)
 	s.pbuf.Close(err)
+	s.swin.Close(err)
 	s.checkFinished()
 }
 
@@ -362,6 +698,12 @@ func (s *Stream) terminate(err error) {
 
 // RawWrite sends the data bytes with the given kind.
 func (s *Stream) RawWrite(kind drpcwire.Kind, data []byte) (err error) {
+	if kind == drpcwire.KindMessage {
+		if err := s.waitWindow(); err != nil {
+			return err
+		}
+	}
+
 	defer s.checkFinished()
 	s.write.Lock()
 	defer s.write.Unlock()
@@ -370,31 +712,60 @@ func (s *Stream) RawWrite(kind drpcwire.Kind, data []byte) (err error) {
 }
 
 // rawWriteLocked does the body of RawWrite assuming the caller is holding the
-// appropriate locks.
+// appropriate locks. Messages are compressed if the stream is using
+// compression.
 func (s *Stream) rawWriteLocked(kind drpcwire.Kind, data []byte) (err error) {
-	fr := s.newFrameLocked(kind)
+	if kind == drpcwire.KindMessage {
+		kind, data, err = s.compressLocked(data)
+		if err != nil {
+			return err
+		}
+	}
+
+	message := kind == drpcwire.KindMessage || kind == drpcwire.KindCompressedMessage
 	n := s.opts.SplitSize
 
-	for {
-		switch {
-		case s.sigs.send.IsSet():
-			return s.sigs.send.Err()
-		case s.sigs.term.IsSet():
-			return s.sigs.term.Err()
-		}
+	// the window may be enabled concurrently, so the message only uses it if
+	// it was enabled when the credit was taken.
+	windowed := message && s.swin.Take(len(data))
 
-		fr.Data, data = drpcwire.SplitData(data, n)
-		fr.Done = len(data) == 0
+	switch {
+	case s.sigs.send.IsSet():
+		return s.sigs.send.Err()
+	case s.sigs.term.IsSet():
+		return s.sigs.term.Err()
+	}
 
-		drpcopts.GetStreamStats(&s.opts.Internal).AddWritten(uint64(len(fr.Data)))
-		s.log("SEND", fr.String)
+	// any header is sent before the first message.
+	if message && !s.hdrDone {
+		if err := s.writeHeaderLocked(); err != nil {
+			return s.checkCancelError(err)
+		}
+	}
 
-		if err := s.wr.WriteFrame(fr); err != nil {
-			return s.checkCancelError(errs.Wrap(err))
-		} else if fr.Done {
-			return nil
+	// a client tells the server when its messages start using the window.
+	if windowed && !s.server() && !s.acked {
+		if err := s.writePacketLocked(drpcwire.KindWindowUpdate, true, s.windowUpdateLocked(0)); err != nil {
+			return s.checkCancelError(err)
 		}
 	}
+	if message {
+		s.sent += int64(len(data))
+	}
+
+	fr := s.newFrameLocked(kind)
+	pkt := drpcwire.Packet{Data: data, ID: fr.ID, Kind: kind}
+
+	drpcopts.GetStreamStats(&s.opts.Internal).AddWritten(uint64(len(data)))
+	s.log("SEND", pkt.String)
+
+	// all of the frames for a packet are written at once so that they are not
+	// interleaved with frames from other streams or from the manager.
+	if err := s.wr.WriteSplitPacket(pkt, n); err != nil {
+		return s.checkCancelError(errs.Wrap(err))
+	}
+	s.unflushed = true
+	return nil
 }
 
 // RawFlush flushes any buffers of data.
@@ -410,7 +781,14 @@ func (s *Stream) RawFlush() (err error) {
 // happen and then issues the flush. It assumes the caller is holding the
 // appropriate locks.
 func (s *Stream) rawFlushLocked() (err error) {
-	if s.wr.Empty() {
+	if drpcopts.GetStreamMultiplexed(&s.opts.Internal) {
+		// the writer is shared with other streams, so only data written by
+		// this stream is considered.
+		if !s.unflushed {
+			return nil
+		}
+		s.unflushed = false
+	} else if s.wr.Empty() {
 		return nil
 	}
 
@@ -453,14 +831,19 @@ func (s *Stream) RawRecv() (data []byte, err error) {
 	s.read.Lock()
 	defer s.read.Unlock()
 
-	data, err = s.pbuf.Get()
+	data, comp, err := s.pbuf.Get()
 	if err != nil {
 		return nil, err
 	}
+	n := len(data)
+	if comp {
+		data, err = s.decompressLocked(data)
+	}
 	data = append([]byte(nil), data...)
 	s.pbuf.Done()
+	s.consumeWindow(n)
 
-	return data, nil
+	return data, err
 }
 
 //
@@ -471,6 +854,10 @@ func (s *Stream) RawRecv() (data []byte, err error) {
 func (s *Stream) MsgSend(msg drpc.Message, enc drpc.Encoding) (err error) {
 	s.flush.Do(func() {})
 
+	if err := s.waitWindow(); err != nil {
+		return err
+	}
+
 	defer s.checkFinished()
 	s.write.Lock()
 	defer s.write.Unlock()
@@ -501,16 +888,145 @@ func (s *Stream) MsgRecv(msg drpc.Message, enc drpc.Encoding) (err error) {
 	s.read.Lock()
 	defer s.read.Unlock()
 
-	data, err := s.pbuf.Get()
+	data, comp, err := s.pbuf.Get()
 	if err != nil {
 		return err
 	}
-	err = enc.Unmarshal(data, msg)
+	n := len(data)
+	if comp {
+		data, err = s.decompressLocked(data)
+	}
+	if err == nil {
+		err = enc.Unmarshal(data, msg)
+	}
 	s.pbuf.Done()
+	s.consumeWindow(n)
 
 	return err
 }
 
+//
+// response metadata
+//
+
+var (
+	headerSent  = drpc.Error.New("header already sent")
+	trailerSent = drpc.Error.New("trailer already sent")
+)
+
+// SetHeader adds the metadata to the header sent to the remote before the
+// first message, or before the stream ends if no messages are sent. It returns
+// an error if the header has already been sent. It is used by servers to send
+// response metadata.
+func (s *Stream) SetHeader(md map[string]string) error {
+	s.mdmu.Lock()
+	defer s.mdmu.Unlock()
+
+	if s.hdrSent {
+		return headerSent
+	}
+	s.header = mergeMetadata(s.header, md)
+	return nil
+}
+
+// SetTrailer adds the metadata to the trailer sent to the remote when the
+// stream ends by CloseSend, SendError or Close. It returns an error if the
+// trailer has already been sent. It is used by servers to send response
+// metadata.
+func (s *Stream) SetTrailer(md map[string]string) error {
+	s.mdmu.Lock()
+	defer s.mdmu.Unlock()
+
+	if s.trlSent {
+		return trailerSent
+	}
+	s.trailer = mergeMetadata(s.trailer, md)
+	return nil
+}
+
+// mergeMetadata adds the entries of md into into, allocating it if necessary.
+func mergeMetadata(into, md map[string]string) map[string]string {
+	if into == nil && len(md) > 0 {
+		into = make(map[string]string, len(md))
+	}
+	for key, value := range md {
+		into[key] = value
+	}
+	return into
+}
+
+// writeHeaderLocked writes any header metadata as a control packet without
+// flushing. The header cannot be changed after. It must be called while
+// holding the write lock.
+func (s *Stream) writeHeaderLocked() error {
+	s.hdrDone = true
+
+	s.mdmu.Lock()
+	header := s.header
+	s.header, s.hdrSent = nil, true
+	s.mdmu.Unlock()
+
+	if len(header) == 0 {
+		return nil
+	}
+	return s.writeMetadataLocked(drpcwire.KindHeader, header)
+}
+
+// writeTrailerLocked writes any header metadata that has not been sent and any
+// trailer metadata as control packets without flushing. The trailer cannot be
+// changed after. It must be called while holding the write lock.
+func (s *Stream) writeTrailerLocked() error {
+	if !s.hdrDone {
+		if err := s.writeHeaderLocked(); err != nil {
+			return err
+		}
+	}
+
+	s.mdmu.Lock()
+	trailer := s.trailer
+	s.trailer, s.trlSent = nil, true
+	s.mdmu.Unlock()
+
+	if len(trailer) == 0 {
+		return nil
+	}
+	return s.writeMetadataLocked(drpcwire.KindTrailer, trailer)
+}
+
+// writeMetadataLocked writes the metadata as a control packet of the kind
+// without flushing. It must be called while holding the write lock.
+func (s *Stream) writeMetadataLocked(kind drpcwire.Kind, md map[string]string) error {
+	data, err := drpcmetadata.Encode(nil, md)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	return s.writePacketLocked(kind, true, data)
+}
+
+// Header returns the header metadata the remote sent in response to the rpc.
+// It blocks until the header is received, the first message is received, or
+// the stream is terminated, so it returns nil only if the remote did not send
+// a header.
+func (s *Stream) Header() map[string]string {
+	_ = s.checkRecvFlush() // the remote may be waiting for the invoke to respond
+	s.sigs.header.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.rheader
+}
+
+// Trailer returns the trailer metadata the remote sent when it ended the rpc.
+// It is only complete once receiving from the stream has returned an error,
+// like io.EOF.
+func (s *Stream) Trailer() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.rtrailer
+}
+
 //
 // terminal messages
 //
@@ -522,8 +1038,9 @@ var (
 	termBothClosed = drpc.Error.New("stream terminated by both issuing close send")
 )
 
-// SendError terminates the stream and sends the error to the remote. It is a
-// no-op if the stream is already terminated.
+// SendError terminates the stream and sends the error to the remote. Any
+// details associated with the error by drpcerr.WithDetails are sent along with
+// it. It is a no-op if the stream is already terminated.
 func (s *Stream) SendError(serr error) (err error) {
 	s.log("CALL", func() string { return fmt.Sprintf("SendError(%v)", serr) })
 
@@ -541,6 +1058,18 @@ func (s *Stream) SendError(serr error) (err error) {
 	s.terminate(termError)
 	s.mu.Unlock()
 
+	if err := s.writeTrailerLocked(); err != nil {
+		return s.checkCancelError(err)
+	}
+
+	// any details are sent first as a control packet so that remotes that do
+	// not support them still receive the error.
+	if details := drpcwire.MarshalErrorDetails(serr); len(details) > 0 {
+		if err := s.sendPacketLocked(drpcwire.KindErrorDetails, true, details); err != nil {
+			return s.checkCancelError(err)
+		}
+	}
+
 	return s.checkCancelError(s.sendPacketLocked(drpcwire.KindError, false, drpcwire.MarshalError(serr)))
 }
 
@@ -548,13 +1077,20 @@ func (s *Stream) SendError(serr error) (err error) {
 // context.Canceled and sends a cancel error to the remote side for a soft
 // cancel. It is a no-op if the stream is already terminated. It returns true
 // for busy if writes are already blocked and a hard cancel is required.
+// Multiplexed streams are never busy: because a hard cancel would affect every
+// stream on the transport, they instead wait for any write to finish.
 func (s *Stream) SendCancel(err error) (busy bool, _ error) {
 	s.log("CALL", func() string { return "SendCancel()" })
 
 	s.mu.Lock()
 	if !s.write.Unlocked() { // if writes are happening, then we have to do a hard cancel.
-		s.mu.Unlock()
-		return true, nil
+		if !drpcopts.GetStreamMultiplexed(&s.opts.Internal) {
+			s.mu.Unlock()
+			return true, nil
+		}
+
+		// unblock any reads while we wait for the write to finish.
+		s.pbuf.Close(err)
 	}
 
 	if s.sigs.term.IsSet() {
@@ -591,6 +1127,10 @@ func (s *Stream) Close() (err error) {
 	s.terminate(termClosed)
 	s.mu.Unlock()
 
+	if err := s.writeTrailerLocked(); err != nil {
+		return s.checkCancelError(err)
+	}
+
 	return s.checkCancelError(s.sendPacketLocked(drpcwire.KindClose, false, nil))
 }
 
@@ -614,6 +1154,10 @@ func (s *Stream) CloseSend() (err error) {
 	s.terminateIfBothClosed()
 	s.mu.Unlock()
 
+	if err := s.writeTrailerLocked(); err != nil {
+		return s.checkCancelError(err)
+	}
+
 	return s.checkCancelError(s.sendPacketLocked(drpcwire.KindCloseSend, false, nil))
 }
 
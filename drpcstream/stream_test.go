@@ -9,13 +9,17 @@ import (
 	"errors"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/zeebo/assert"
 	"github.com/zeebo/errs"
 
 	"storj.io/drpc"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcmetadata"
 	"storj.io/drpc/drpctest"
 	"storj.io/drpc/drpcwire"
+	"storj.io/drpc/internal/drpcopts"
 )
 
 func TestStream_StateTransitions(t *testing.T) {
@@ -327,3 +331,398 @@ func TestStream_SendCancelBusyDuringBlockedClose(t *testing.T) {
 	assert.NoError(t, err)
 	assert.That(t, busy)
 }
+
+func TestStream_FlowControlSend(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	// the client advertised a window of 10 bytes.
+	opts := Options{InitialWindowSize: 10}
+	drpcopts.SetStreamServer(&opts.Internal, true)
+	drpcopts.SetStreamWindow(&opts.Internal, 10)
+	st := NewWithOptions(ctx, 1, drpcwire.NewWriter(io.Discard, 0), opts)
+
+	// the first message uses more than the entire window.
+	assert.NoError(t, st.MsgSend(make([]byte, 15), byteEncoding{}))
+
+	// the next message must wait for credit from the remote.
+	errch := make(chan error, 1)
+	ctx.Run(func(ctx context.Context) {
+		errch <- st.MsgSend(make([]byte, 5), byteEncoding{})
+	})
+
+	assert.NoError(t, st.HandlePacket(drpcwire.Packet{
+		Data:    drpcwire.AppendVarint(nil, 5),
+		ID:      drpcwire.ID{Stream: 1, Message: 1},
+		Kind:    drpcwire.KindWindowUpdate,
+		Control: true,
+	}))
+	select {
+	case err := <-errch:
+		t.Fatal("send did not wait for enough credit:", err)
+	default:
+	}
+
+	assert.NoError(t, st.HandlePacket(drpcwire.Packet{
+		Data:    drpcwire.AppendVarint(nil, 1),
+		ID:      drpcwire.ID{Stream: 1, Message: 2},
+		Kind:    drpcwire.KindWindowUpdate,
+		Control: true,
+	}))
+	assert.NoError(t, <-errch)
+
+	// a send waiting for credit is unblocked when the stream is canceled.
+	ctx.Run(func(ctx context.Context) {
+		errch <- st.MsgSend(make([]byte, 5), byteEncoding{})
+	})
+	st.Cancel(context.Canceled)
+	assert.That(t, errors.Is(<-errch, context.Canceled))
+}
+
+func TestStream_FlowControlRecv(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	var buf bytes.Buffer
+	opts := Options{InitialWindowSize: 10, MaxWindowSize: 15}
+	drpcopts.SetStreamServer(&opts.Internal, true)
+	drpcopts.SetStreamWindow(&opts.Internal, 10)
+	st := NewWithOptions(ctx, 1, drpcwire.NewWriter(&buf, 0), opts)
+
+	recv := func(n int) {
+		ctx.Run(func(ctx context.Context) {
+			_ = st.HandlePacket(drpcwire.Packet{
+				Data: make([]byte, n),
+				ID:   drpcwire.ID{Stream: 1, Message: 1},
+				Kind: drpcwire.KindMessage,
+			})
+		})
+		_, err := st.RawRecv()
+		assert.NoError(t, err)
+	}
+
+	readUpdates := func() (credits []uint64) {
+		rd := drpcwire.NewReader(bytes.NewReader(buf.Bytes()))
+		for {
+			pkt, err := rd.ReadPacket()
+			if err != nil {
+				return credits
+			}
+			assert.Equal(t, pkt.Kind, drpcwire.KindWindowUpdate)
+			assert.That(t, pkt.Control)
+			_, credit, ok, err := drpcwire.ReadVarint(pkt.Data)
+			assert.That(t, ok)
+			assert.NoError(t, err)
+			credits = append(credits, credit)
+		}
+	}
+
+	// the client is told the window of the server when the stream is created.
+	assert.DeepEqual(t, readUpdates(), []uint64{10})
+
+	// nothing is granted until half of the window has been consumed.
+	recv(4)
+	assert.DeepEqual(t, readUpdates(), []uint64{10})
+
+	// the window grows up to the maximum with the first update.
+	recv(1)
+	assert.DeepEqual(t, readUpdates(), []uint64{10, 10})
+
+	// further updates only grant credit for what was consumed.
+	recv(8)
+	assert.DeepEqual(t, readUpdates(), []uint64{10, 10, 8})
+}
+
+func TestStream_FlowControlNegotiate(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	windowUpdate := func(data ...uint64) drpcwire.Packet {
+		var buf []byte
+		for _, v := range data {
+			buf = drpcwire.AppendVarint(buf, v)
+		}
+		return drpcwire.Packet{
+			Data:    buf,
+			ID:      drpcwire.ID{Stream: 1},
+			Kind:    drpcwire.KindWindowUpdate,
+			Control: true,
+		}
+	}
+
+	message := func(n int) drpcwire.Packet {
+		return drpcwire.Packet{
+			Data: make([]byte, n),
+			ID:   drpcwire.ID{Stream: 1},
+			Kind: drpcwire.KindMessage,
+		}
+	}
+
+	t.Run("Client", func(t *testing.T) {
+		var buf bytes.Buffer
+		st := NewWithOptions(ctx, 1, drpcwire.NewWriter(&buf, 0), Options{
+			InitialWindowSize: 10,
+		})
+
+		// until the server says what its window is, sends are not limited.
+		assert.NoError(t, st.MsgSend(make([]byte, 15), byteEncoding{}))
+		assert.NoError(t, st.MsgSend(make([]byte, 15), byteEncoding{}))
+
+		// the first window update is the window of the server.
+		assert.NoError(t, st.HandlePacket(windowUpdate(5)))
+		assert.NoError(t, st.MsgSend(make([]byte, 5), byteEncoding{}))
+
+		errch := make(chan error, 1)
+		ctx.Run(func(ctx context.Context) {
+			errch <- st.MsgSend(make([]byte, 5), byteEncoding{})
+		})
+		select {
+		case err := <-errch:
+			t.Fatal("send did not wait for the window:", err)
+		case <-time.After(10 * time.Millisecond):
+		}
+		assert.NoError(t, st.HandlePacket(windowUpdate(5)))
+		assert.NoError(t, <-errch)
+
+		// before the first message using the window, the client tells the
+		// server how much was sent before it.
+		rd := drpcwire.NewReader(bytes.NewReader(buf.Bytes()))
+		var updates [][]byte
+		for {
+			pkt, err := rd.ReadPacket()
+			if err != nil {
+				break
+			}
+			if pkt.Kind == drpcwire.KindWindowUpdate {
+				updates = append(updates, pkt.Data)
+			}
+		}
+		assert.Equal(t, len(updates), 1)
+		assert.DeepEqual(t, updates[0], drpcwire.AppendVarint(drpcwire.AppendVarint(nil, 0), 30))
+
+		// the server must stay within the window of the client.
+		assert.That(t, st.CheckWindow(10))
+		assert.That(t, !st.IsTerminated())
+		assert.NoError(t, st.HandlePacket(message(5)))
+		assert.That(t, st.IsTerminated())
+
+		_, err := st.RawRecv()
+		assert.Equal(t, drpcerr.Code(err), uint64(drpcerr.ResourceExhausted))
+	})
+
+	t.Run("Server", func(t *testing.T) {
+		opts := Options{InitialWindowSize: 10}
+		drpcopts.SetStreamServer(&opts.Internal, true)
+		drpcopts.SetStreamWindow(&opts.Internal, 10)
+		st := NewWithOptions(ctx, 1, drpcwire.NewWriter(io.Discard, 0), opts)
+
+		// messages sent before the client used the window are not limited.
+		assert.That(t, st.CheckWindow(30))
+		assert.NoError(t, st.HandlePacket(windowUpdate(0, 30)))
+
+		// but afterwards, the client must stay within it.
+		assert.That(t, st.CheckWindow(10))
+		assert.That(t, !st.IsTerminated())
+		assert.That(t, !st.CheckWindow(1))
+		assert.That(t, st.IsTerminated())
+	})
+
+	t.Run("Fallback", func(t *testing.T) {
+		// a server does not use flow control if the client did not advertise
+		// a window.
+		var buf bytes.Buffer
+		opts := Options{InitialWindowSize: 10}
+		drpcopts.SetStreamServer(&opts.Internal, true)
+		st := NewWithOptions(ctx, 1, drpcwire.NewWriter(&buf, 0), opts)
+
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, st.MsgSend(make([]byte, 15), byteEncoding{}))
+			ctx.Run(func(ctx context.Context) { _ = st.HandlePacket(message(15)) })
+			_, err := st.RawRecv()
+			assert.NoError(t, err)
+		}
+		assert.That(t, !st.IsTerminated())
+
+		rd := drpcwire.NewReader(bytes.NewReader(buf.Bytes()))
+		for {
+			pkt, err := rd.ReadPacket()
+			if err != nil {
+				break
+			}
+			assert.Equal(t, pkt.Kind, drpcwire.KindMessage)
+		}
+	})
+}
+
+func TestStream_ErrorDetails(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	var buf bytes.Buffer
+	send := New(ctx, 1, drpcwire.NewWriter(&buf, 0))
+
+	detail := drpcerr.Detail{Type: "example.RetryInfo", Value: []byte("1s")}
+	serr := drpcerr.WithDetails(drpcerr.WithCode(errs.New("failed"), 5), detail)
+	assert.NoError(t, send.SendError(serr))
+
+	recv := New(ctx, 1, drpcwire.NewWriter(io.Discard, 0))
+	rd := drpcwire.NewReader(bytes.NewReader(buf.Bytes()))
+	for {
+		pkt, err := rd.ReadPacket()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.NoError(t, err)
+
+		// the details must be ignored by remotes that do not support them.
+		if pkt.Kind == drpcwire.KindErrorDetails {
+			assert.That(t, pkt.Control)
+		}
+		assert.NoError(t, recv.HandlePacket(pkt))
+	}
+
+	_, err := recv.RawRecv()
+	assert.Equal(t, err.Error(), "failed")
+	assert.Equal(t, drpcerr.Code(err), 5)
+	assert.DeepEqual(t, drpcerr.Details(err), []drpcerr.Detail{detail})
+}
+
+func TestStream_Compression(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	small := []byte("small")
+	large := bytes.Repeat([]byte("large"), 1000)
+
+	opts := Options{Compression: "gzip", CompressionThreshold: 100}
+	read := func(buf *bytes.Buffer) (pkts []drpcwire.Packet, kinds []drpcwire.Kind) {
+		rd := drpcwire.NewReader(bytes.NewReader(buf.Bytes()))
+		for {
+			pkt, err := rd.ReadPacket()
+			if errors.Is(err, io.EOF) {
+				return pkts, kinds
+			}
+			assert.NoError(t, err)
+			pkts = append(pkts, pkt)
+			kinds = append(kinds, pkt.Kind)
+		}
+	}
+
+	// a server confirms the compressor advertised by the client in the header
+	// and compresses only the messages over the threshold.
+	srvOpts := opts
+	drpcopts.SetStreamServer(&srvOpts.Internal, true)
+
+	var buf bytes.Buffer
+	send := NewWithOptions(ctx, 1, drpcwire.NewWriter(&buf, 0), srvOpts)
+	assert.NoError(t, send.MsgSend(small, byteEncoding{}))
+	assert.NoError(t, send.MsgSend(large, byteEncoding{}))
+
+	pkts, kinds := read(&buf)
+	assert.DeepEqual(t, kinds, []drpcwire.Kind{
+		drpcwire.KindHeader, drpcwire.KindMessage, drpcwire.KindCompressedMessage,
+	})
+	assert.That(t, len(pkts[2].Data) < len(large))
+
+	// a client does not compress until the server has confirmed the
+	// compressor, and the confirmation is not part of the header.
+	buf.Reset()
+	client := NewWithOptions(ctx, 1, drpcwire.NewWriter(&buf, 0), opts)
+	assert.NoError(t, client.MsgSend(large, byteEncoding{}))
+	assert.NoError(t, client.HandlePacket(pkts[0]))
+	assert.Nil(t, client.Header())
+	assert.NoError(t, client.MsgSend(large, byteEncoding{}))
+
+	_, kinds = read(&buf)
+	assert.DeepEqual(t, kinds, []drpcwire.Kind{
+		drpcwire.KindMessage, drpcwire.KindCompressedMessage,
+	})
+	pkts = pkts[1:]
+
+	recv := func(st *Stream, pkt drpcwire.Packet) ([]byte, error) {
+		ctx.Run(func(ctx context.Context) { _ = st.HandlePacket(pkt) })
+		var out []byte
+		err := st.MsgRecv(&out, byteEncoding{})
+		return out, err
+	}
+
+	// a stream with the compressor decompresses transparently.
+	st := NewWithOptions(ctx, 1, drpcwire.NewWriter(io.Discard, 0), Options{Compression: "gzip"})
+	out, err := recv(st, pkts[0])
+	assert.NoError(t, err)
+	assert.DeepEqual(t, out, small)
+	out, err = recv(st, pkts[1])
+	assert.NoError(t, err)
+	assert.DeepEqual(t, out, large)
+
+	// a stream without it fails to receive compressed messages.
+	st = New(ctx, 1, drpcwire.NewWriter(io.Discard, 0))
+	_, err = recv(st, pkts[1])
+	assert.Equal(t, drpcerr.Code(err), drpcerr.Unimplemented)
+
+	// as does a stream that would decompress a message that is too large.
+	st = NewWithOptions(ctx, 1, drpcwire.NewWriter(io.Discard, 0), Options{
+		Compression:             "gzip",
+		MaximumDecompressedSize: 100,
+	})
+	_, err = recv(st, pkts[1])
+	assert.Equal(t, drpcerr.Code(err), drpcerr.Internal)
+}
+
+func TestStream_ResponseMetadata(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	var opts Options
+	drpcopts.SetStreamServer(&opts.Internal, true)
+
+	var buf bytes.Buffer
+	send := NewWithOptions(ctx, 1, drpcwire.NewWriter(&buf, 0), opts)
+
+	// handlers can set metadata through the context of server streams.
+	assert.NoError(t, drpcmetadata.SetHeader(send.Context(), map[string]string{"version": "1"}))
+	assert.NoError(t, send.SetTrailer(map[string]string{"debug": "id"}))
+	assert.NoError(t, send.MsgSend([]byte("msg"), byteEncoding{}))
+	assert.Error(t, send.SetHeader(map[string]string{"late": "header"}))
+	assert.NoError(t, drpcmetadata.SetTrailer(send.Context(), map[string]string{"remaining": "10"}))
+	assert.NoError(t, send.CloseSend())
+	assert.Error(t, send.SetTrailer(map[string]string{"late": "trailer"}))
+
+	var pkts []drpcwire.Packet
+	var kinds []drpcwire.Kind
+	rd := drpcwire.NewReader(bytes.NewReader(buf.Bytes()))
+	for {
+		pkt, err := rd.ReadPacket()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.NoError(t, err)
+
+		// the metadata must be ignored by remotes that do not support it.
+		if pkt.Kind == drpcwire.KindHeader || pkt.Kind == drpcwire.KindTrailer {
+			assert.That(t, pkt.Control)
+		}
+		pkts = append(pkts, pkt)
+		kinds = append(kinds, pkt.Kind)
+	}
+	assert.DeepEqual(t, kinds, []drpcwire.Kind{
+		drpcwire.KindHeader, drpcwire.KindMessage, drpcwire.KindTrailer, drpcwire.KindCloseSend,
+	})
+
+	recv := New(ctx, 1, drpcwire.NewWriter(io.Discard, 0))
+	ctx.Run(func(ctx context.Context) {
+		for _, pkt := range pkts {
+			assert.NoError(t, recv.HandlePacket(pkt))
+		}
+	})
+
+	assert.DeepEqual(t, recv.Header(), map[string]string{"version": "1"})
+	_, err := recv.RawRecv()
+	assert.NoError(t, err)
+	_, err = recv.RawRecv()
+	assert.Equal(t, err, io.EOF)
+	assert.DeepEqual(t, recv.Trailer(), map[string]string{"debug": "id", "remaining": "10"})
+
+	// client streams do not have a response writer.
+	assert.Error(t, drpcmetadata.SetHeader(recv.Context(), map[string]string{"key": "value"}))
+}
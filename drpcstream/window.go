@@ -0,0 +1,181 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcstream
+
+import (
+	"sync"
+)
+
+// sendWindow tracks how much message data the remote has allowed the stream
+// to send. Until it is enabled, sends are not limited.
+type sendWindow struct {
+	mu      sync.Mutex
+	cond    sync.Cond
+	err     error
+	enabled bool
+	credit  int64
+}
+
+func (sw *sendWindow) init(enabled bool, credit int) {
+	sw.cond.L = &sw.mu
+	sw.enabled = enabled
+	sw.credit = int64(credit)
+}
+
+// Enabled returns true if sends are limited by the window.
+func (sw *sendWindow) Enabled() bool {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	return sw.enabled
+}
+
+// Enable starts limiting sends to the credit granted from now on. It returns
+// false if the window was already enabled.
+func (sw *sendWindow) Enable(credit uint64) bool {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	if sw.enabled {
+		return false
+	}
+	sw.enabled = true
+	sw.credit = int64(credit)
+	sw.cond.Broadcast()
+	return true
+}
+
+// Wait blocks until there is some credit available or the window is closed.
+// Any amount of credit allows a message of any size to be sent so that
+// messages larger than the window do not block forever.
+func (sw *sendWindow) Wait() error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	for sw.enabled && sw.credit <= 0 && sw.err == nil {
+		sw.cond.Wait()
+	}
+	if !sw.enabled {
+		return nil
+	}
+	return sw.err
+}
+
+// Take consumes n bytes of credit, possibly causing it to become negative. It
+// returns false if the window is not enabled.
+func (sw *sendWindow) Take(n int) bool {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	if sw.enabled {
+		sw.credit -= int64(n)
+	}
+	return sw.enabled
+}
+
+// Add grants n bytes of credit.
+func (sw *sendWindow) Add(n uint64) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	sw.credit += int64(n)
+	sw.cond.Broadcast()
+}
+
+// Close causes any current or future calls to Wait to return the error.
+func (sw *sendWindow) Close(err error) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	if sw.err == nil {
+		sw.err = err
+		sw.cond.Broadcast()
+	}
+}
+
+// recvWindow tracks how much message data the stream has consumed without
+// granting the remote credit for it, and how much the remote has sent compared
+// to the credit it was granted. The consumed data is only tracked while
+// holding the read lock.
+type recvWindow struct {
+	size     int
+	max      int
+	consumed int
+
+	mu       sync.Mutex
+	active   bool  // set when the remote uses the window, so credit is granted
+	enforced bool  // set when the remote must stay within the window
+	offset   int64 // message data the remote sent before it was enforced
+	received int64 // message data received from the remote
+	limit    int64 // initial size plus all of the credit granted
+}
+
+func (rw *recvWindow) init(active bool, size, max int) {
+	rw.size = size
+	rw.max = max
+	rw.active = active
+	rw.limit = int64(size)
+}
+
+// Active returns true if the remote uses the window.
+func (rw *recvWindow) Active() bool {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	return rw.active
+}
+
+// Enforce starts requiring the remote to stay within the window for any
+// message data beyond the first offset bytes it sent.
+func (rw *recvWindow) Enforce(offset uint64) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	rw.active = true
+	if !rw.enforced {
+		rw.enforced = true
+		rw.offset = int64(offset)
+	}
+}
+
+// Receive records that n bytes of message data were received and returns false
+// if the remote had no credit left to send it. Like the sender, any amount of
+// credit allows a message of any size.
+func (rw *recvWindow) Receive(n int) bool {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	before := rw.received - rw.offset
+	rw.received += int64(n)
+	return !rw.enforced || before < rw.limit
+}
+
+// Grant records that credit is about to be granted to the remote.
+func (rw *recvWindow) Grant(credit uint64) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	rw.limit += int64(credit)
+}
+
+// Consume records that n bytes of message data were consumed and returns the
+// amount of credit to grant the remote, if any. Credit is granted once half of
+// the window has been consumed, and the window doubles every time until it
+// reaches the maximum size.
+func (rw *recvWindow) Consume(n int) (credit uint64) {
+	rw.consumed += n
+	if rw.consumed < rw.size/2 {
+		return 0
+	}
+
+	credit, rw.consumed = uint64(rw.consumed), 0
+	if grow := rw.max - rw.size; grow > 0 {
+		if grow > rw.size {
+			grow = rw.size
+		}
+		rw.size += grow
+		credit += uint64(grow)
+	}
+	return credit
+}
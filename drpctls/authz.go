@@ -0,0 +1,131 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpctls
+
+import (
+	"context"
+	"crypto/x509"
+	"strings"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcctx"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcmux"
+)
+
+// SPIFFEID returns the SPIFFE ID of the certificate, which is its URI subject
+// alternative name with the spiffe scheme.
+func SPIFFEID(cert *x509.Certificate) (string, bool) {
+	for _, u := range cert.URIs {
+		if u.Scheme == "spiffe" {
+			return u.String(), true
+		}
+	}
+	return "", false
+}
+
+// Identities returns the subject alternative names of the certificate: its
+// URIs, including any SPIFFE ID, DNS names, email addresses and IP addresses.
+func Identities(cert *x509.Certificate) []string {
+	ids := make([]string, 0, len(cert.URIs)+len(cert.DNSNames)+len(cert.EmailAddresses)+len(cert.IPAddresses))
+	for _, u := range cert.URIs {
+		ids = append(ids, u.String())
+	}
+	ids = append(ids, cert.DNSNames...)
+	ids = append(ids, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		ids = append(ids, ip.String())
+	}
+	return ids
+}
+
+// Authorizer allows peers to call rpcs based on the identities in the leaf
+// certificate they presented. Only certificates verified during the TLS
+// handshake are trusted, so the server must verify client certificates, as
+// with tls.RequireAndVerifyClientCert. Rpcs from peers without a verified
+// certificate fail with an error with the Unauthenticated code, and rpcs that
+// are not allowed fail with an error with the PermissionDenied code.
+type Authorizer struct {
+	rules []authRule
+}
+
+// authRule allows peers with an identity matching the pattern to call the rpcs
+// matching any of the rpc patterns.
+type authRule struct {
+	identity string
+	rpcs     []string
+}
+
+// NewAuthorizer returns an Authorizer with the rules, which map a pattern for
+// the identities of peers to the patterns for the rpcs they may call. A pattern
+// matches exactly, or if it ends with "*", matches anything with the prefix
+// before it. For example,
+//
+//	drpctls.NewAuthorizer(map[string][]string{
+//		"spiffe://example.org/ns/prod/*": {"/service.Store/*"},
+//		"admin.example.org":              {"*"},
+//	})
+//
+// allows every workload in the prod namespace to call the Store service, and
+// the peer with the admin.example.org DNS name to call anything.
+func NewAuthorizer(rules map[string][]string) *Authorizer {
+	a := &Authorizer{rules: make([]authRule, 0, len(rules))}
+	for identity, rpcs := range rules {
+		a.rules = append(a.rules, authRule{identity: identity, rpcs: rpcs})
+	}
+	return a
+}
+
+// match returns true if the value matches the pattern.
+func match(pattern, value string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(value, pattern[:len(pattern)-1])
+	}
+	return pattern == value
+}
+
+// Authorize returns an error if the peer associated with the context is not
+// allowed to call the rpc.
+func (a *Authorizer) Authorize(ctx context.Context, rpc string) error {
+	// the peer certificates are not verified unless the server asked for it,
+	// so a self-signed certificate could claim any identity.
+	state, ok := drpcctx.TLSConnectionState(ctx)
+	if !ok || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return drpcerr.Newf(drpcerr.Unauthenticated, "no verified peer certificate for %s", rpc)
+	}
+
+	ids := Identities(state.VerifiedChains[0][0])
+	for _, rule := range a.rules {
+		for _, id := range ids {
+			if !match(rule.identity, id) {
+				continue
+			}
+			for _, pattern := range rule.rpcs {
+				if match(pattern, rpc) {
+					return nil
+				}
+			}
+		}
+	}
+
+	return drpcerr.Newf(drpcerr.PermissionDenied, "peer is not allowed to call %s", rpc)
+}
+
+// UnaryServerInterceptor is a drpcmux.UnaryServerInterceptor that rejects the
+// rpcs the peer is not allowed to call.
+func (a *Authorizer) UnaryServerInterceptor(ctx context.Context, req interface{}, rpc string, handler drpcmux.UnaryHandler) (interface{}, error) {
+	if err := a.Authorize(ctx, rpc); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamServerInterceptor is a drpcmux.StreamServerInterceptor that rejects
+// the rpcs the peer is not allowed to call.
+func (a *Authorizer) StreamServerInterceptor(stream drpc.Stream, rpc string, handler drpcmux.StreamHandler) (interface{}, error) {
+	if err := a.Authorize(stream.Context(), rpc); err != nil {
+		return nil, err
+	}
+	return handler(stream)
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpctls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/url"
+	"testing"
+
+	"github.com/zeebo/assert"
+
+	"storj.io/drpc/drpcctx"
+	"storj.io/drpc/drpcerr"
+)
+
+func TestAuthorizer(t *testing.T) {
+	a := NewAuthorizer(map[string][]string{
+		"spiffe://example.org/ns/prod/*": {"/service.Store/*"},
+		"admin.example.org":              {"*"},
+	})
+
+	newCert := func(dnsNames []string, uris ...string) *x509.Certificate {
+		cert := &x509.Certificate{DNSNames: dnsNames}
+		for _, uri := range uris {
+			u, err := url.Parse(uri)
+			assert.NoError(t, err)
+			cert.URIs = append(cert.URIs, u)
+		}
+		return cert
+	}
+	peer := func(cert *x509.Certificate) context.Context {
+		return drpcctx.WithTLSConnectionState(context.Background(), tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		})
+	}
+
+	web := newCert([]string{"web.example.org"}, "spiffe://example.org/ns/prod/sa/web")
+	id, ok := SPIFFEID(web)
+	assert.That(t, ok)
+	assert.Equal(t, id, "spiffe://example.org/ns/prod/sa/web")
+	assert.DeepEqual(t, Identities(web), []string{"spiffe://example.org/ns/prod/sa/web", "web.example.org"})
+
+	assert.NoError(t, a.Authorize(peer(web), "/service.Store/Get"))
+	err := a.Authorize(peer(web), "/service.Admin/Delete")
+	assert.Equal(t, drpcerr.Code(err), drpcerr.PermissionDenied)
+
+	admin := newCert([]string{"admin.example.org"})
+	_, ok = SPIFFEID(admin)
+	assert.That(t, !ok)
+	assert.NoError(t, a.Authorize(peer(admin), "/service.Admin/Delete"))
+
+	dev := newCert(nil, "spiffe://example.org/ns/dev/sa/web")
+	err = a.Authorize(peer(dev), "/service.Store/Get")
+	assert.Equal(t, drpcerr.Code(err), drpcerr.PermissionDenied)
+
+	err = a.Authorize(context.Background(), "/service.Store/Get")
+	assert.Equal(t, drpcerr.Code(err), drpcerr.Unauthenticated)
+
+	// certificates that were not verified are never trusted.
+	unverified := drpcctx.WithTLSConnectionState(context.Background(), tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{admin},
+	})
+	err = a.Authorize(unverified, "/service.Store/Get")
+	assert.Equal(t, drpcerr.Code(err), drpcerr.Unauthenticated)
+
+	// the interceptors only call the handler for allowed rpcs.
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return req, nil }
+	out, err := a.UnaryServerInterceptor(peer(web), "in", "/service.Store/Get", handler)
+	assert.NoError(t, err)
+	assert.Equal(t, out, "in")
+	_, err = a.UnaryServerInterceptor(peer(dev), "in", "/service.Store/Get", handler)
+	assert.Equal(t, drpcerr.Code(err), drpcerr.PermissionDenied)
+}
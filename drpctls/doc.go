@@ -0,0 +1,15 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package drpctls has helpers for serving and dialing drpc over TLS.
+//
+// A Reloader keeps a certificate, and optionally a pool of certificate
+// authorities, loaded from files on disk, reloading them when they change so
+// that certificates can be rotated without restarting. Its ServerConfig and
+// ClientConfig methods return tls.Configs that use them, which can be passed
+// to drpcserver.Options and drpcclient.DialTLS.
+//
+// An Authorizer is an interceptor that decides which rpcs a peer may call
+// based on the SPIFFE ID and other subject alternative names in its verified
+// certificate.
+package drpctls
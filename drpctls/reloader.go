@@ -0,0 +1,276 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpctls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+)
+
+// Error is the class of errors returned by this package.
+var Error = errs.Class("drpctls")
+
+// ReloaderOptions controls configuration settings for a Reloader.
+type ReloaderOptions struct {
+	// CAFile, if set, is a file of PEM encoded certificate authorities that
+	// are used to verify the certificates of peers. It is reloaded along with
+	// the certificate.
+	CAFile string
+
+	// Interval is the least amount of time between checks for changes to the
+	// files. Checks happen during handshakes, so an idle Reloader does not
+	// touch the disk. If zero, 10 seconds is used.
+	Interval time.Duration
+
+	// Log is called with errors reloading the files, in which case the
+	// previously loaded files keep being used. It is not called if nil.
+	Log func(error)
+}
+
+// Reloader loads a certificate and key, and optionally certificate
+// authorities, from files and reloads them when they are modified.
+type Reloader struct {
+	certFile string
+	keyFile  string
+	opts     ReloaderOptions
+
+	mu      sync.Mutex
+	checked time.Time
+	mods    [3]time.Time // modification times of the cert, key and ca files
+	cert    *tls.Certificate
+	cas     *x509.CertPool
+}
+
+// NewReloader returns a Reloader for the certificate and key files. It returns
+// an error if they can not be loaded.
+func NewReloader(certFile, keyFile string) (*Reloader, error) {
+	return NewReloaderWithOptions(certFile, keyFile, ReloaderOptions{})
+}
+
+// NewReloaderWithOptions returns a Reloader for the certificate and key files.
+// It returns an error if they, or the CAFile in the options, can not be
+// loaded.
+func NewReloaderWithOptions(certFile, keyFile string, opts ReloaderOptions) (*Reloader, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = 10 * time.Second
+	}
+
+	r := &Reloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		opts:     opts,
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload loads the files, returning an error if they can not be loaded. The
+// previously loaded files keep being used if it fails.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.reloadLocked(r.modTimes())
+}
+
+// modTimes returns the modification times of the files, or the zero time for
+// any that can not be read, so that they are retried on the next check.
+func (r *Reloader) modTimes() (mods [3]time.Time) {
+	for i, name := range []string{r.certFile, r.keyFile, r.opts.CAFile} {
+		if name == "" {
+			continue
+		}
+		if fi, err := os.Stat(name); err == nil {
+			mods[i] = fi.ModTime()
+		}
+	}
+	return mods
+}
+
+// reloadLocked loads the files that were modified at the times. It must be
+// called with the mutex held.
+func (r *Reloader) reloadLocked(mods [3]time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	var cas *x509.CertPool
+	if r.opts.CAFile != "" {
+		data, err := os.ReadFile(r.opts.CAFile)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		cas = x509.NewCertPool()
+		if !cas.AppendCertsFromPEM(data) {
+			return Error.New("no certificates found in %q", r.opts.CAFile)
+		}
+	}
+
+	r.cert, r.cas, r.mods = &cert, cas, mods
+	return nil
+}
+
+// check reloads the files if the interval has passed and any of them have
+// been modified.
+func (r *Reloader) check() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(r.checked) < r.opts.Interval {
+		return
+	}
+	r.checked = now
+
+	mods := r.modTimes()
+	if mods == r.mods {
+		return
+	}
+	if err := r.reloadLocked(mods); err != nil && r.opts.Log != nil {
+		r.opts.Log(err)
+	}
+}
+
+// Certificate returns the current certificate.
+func (r *Reloader) Certificate() *tls.Certificate {
+	r.check()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.cert
+}
+
+// CAs returns the current pool of certificate authorities, or nil if the
+// Reloader does not have a CAFile.
+func (r *Reloader) CAs() *x509.CertPool {
+	r.check()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.cas
+}
+
+// GetCertificate is suitable for use as tls.Config.GetCertificate.
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.Certificate(), nil
+}
+
+// GetClientCertificate is suitable for use as
+// tls.Config.GetClientCertificate.
+func (r *Reloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return r.Certificate(), nil
+}
+
+// ServerConfig returns a copy of the config, which may be nil, that serves
+// the current certificate. If the Reloader has a CAFile, client certificates
+// are verified with the current certificate authorities, and ClientAuth
+// defaults to requiring and verifying them. If the config has a
+// GetConfigForClient callback, it is still called, and the configs it returns
+// verify client certificates with the current certificate authorities too.
+func (r *Reloader) ServerConfig(base *tls.Config) *tls.Config {
+	config := cloneConfig(base)
+	config.Certificates = nil
+	config.GetCertificate = r.GetCertificate
+
+	if r.opts.CAFile != "" {
+		if config.ClientAuth == tls.NoClientCert {
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+		getConfig := config.GetConfigForClient
+		config.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			client := config
+			if getConfig != nil {
+				other, err := getConfig(hello)
+				if err != nil {
+					return nil, err
+				} else if other != nil {
+					client = other
+				}
+			}
+			client = client.Clone()
+			client.GetConfigForClient = nil
+			client.ClientCAs = r.CAs()
+			return client, nil
+		}
+	}
+
+	return config
+}
+
+// ClientConfig returns a copy of the config, which may be nil, that presents
+// the current certificate to servers that ask for one. If the Reloader has a
+// CAFile, server certificates are verified with the current certificate
+// authorities instead of the RootCAs of the config. They are verified against
+// the ServerName of the returned config, or the name sent with SNI if it is
+// empty. IP addresses are not sent with SNI, so dialing an IP address requires
+// the ServerName to be set, and the handshake fails otherwise.
+func (r *Reloader) ClientConfig(base *tls.Config) *tls.Config {
+	config := cloneConfig(base)
+	config.Certificates = nil
+	config.GetClientCertificate = r.GetClientCertificate
+
+	if r.opts.CAFile != "" && !config.InsecureSkipVerify {
+		// the roots can not change after the config is created, so the
+		// standard verification is replaced with the same verification
+		// using the current roots.
+		verify := config.VerifyConnection
+		config.InsecureSkipVerify = true
+		config.VerifyConnection = func(cs tls.ConnectionState) error {
+			// the name is read when verifying so that it can be set on
+			// the returned config before dialing.
+			name := config.ServerName
+			if name == "" {
+				name = cs.ServerName
+			}
+			if err := verifyServer(cs, name, r.CAs()); err != nil {
+				return err
+			}
+			if verify != nil {
+				return verify(cs)
+			}
+			return nil
+		}
+	}
+
+	return config
+}
+
+// verifyServer verifies the certificate chain of the server in the connection
+// state with the roots, and that it is valid for the name, which is a host
+// name or an IP address.
+func verifyServer(cs tls.ConnectionState, name string, roots *x509.CertPool) error {
+	if len(cs.PeerCertificates) == 0 {
+		return Error.New("server did not present a certificate")
+	} else if name == "" {
+		return Error.New("no server name to verify the server certificate with")
+	}
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		DNSName:       name,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return Error.Wrap(err)
+}
+
+// cloneConfig returns a copy of the config, or an empty config if it is nil.
+func cloneConfig(config *tls.Config) *tls.Config {
+	if config == nil {
+		return new(tls.Config)
+	}
+	return config.Clone()
+}
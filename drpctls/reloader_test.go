@@ -0,0 +1,254 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpctls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zeebo/assert"
+	"github.com/zeebo/errs"
+)
+
+// testCA is a certificate authority for tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue returns the PEM encoded certificate and key for a leaf certificate
+// with the common name and subject alternative names, including 127.0.0.1.
+func (ca *testCA) issue(t *testing.T, name string, dnsNames []string, uris ...string) (certPEM, keyPEM []byte) {
+	return ca.issueIPs(t, name, dnsNames, []net.IP{net.IPv4(127, 0, 0, 1)}, uris...)
+}
+
+// issueIPs is like issue but with only the IP addresses in the subject
+// alternative names.
+func (ca *testCA) issueIPs(t *testing.T, name string, dnsNames []string, ips []net.IP, uris ...string) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     dnsNames,
+		IPAddresses:  ips,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	for _, uri := range uris {
+		u, err := url.Parse(uri)
+		assert.NoError(t, err)
+		tmpl.URIs = append(tmpl.URIs, u)
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	assert.NoError(t, err)
+	kder, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kder})
+}
+
+// writeFiles writes the certificate, key and ca into the directory, returning
+// their paths.
+func writeFiles(t *testing.T, dir string, certPEM, keyPEM, caPEM []byte) (certFile, keyFile, caFile string) {
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	caFile = filepath.Join(dir, "ca.pem")
+	assert.NoError(t, os.WriteFile(certFile, certPEM, 0o600))
+	assert.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+	assert.NoError(t, os.WriteFile(caFile, caPEM, 0o600))
+	return certFile, keyFile, caFile
+}
+
+func leafName(t *testing.T, cert *tls.Certificate) string {
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	assert.NoError(t, err)
+	return leaf.Subject.CommonName
+}
+
+func TestReloader(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+
+	certPEM, keyPEM := ca.issue(t, "first", nil)
+	certFile, keyFile, caFile := writeFiles(t, dir, certPEM, keyPEM, ca.pem)
+
+	var logged []error
+	r, err := NewReloaderWithOptions(certFile, keyFile, ReloaderOptions{
+		CAFile:   caFile,
+		Interval: time.Hour,
+		Log:      func(err error) { logged = append(logged, err) },
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, leafName(t, r.Certificate()), "first")
+	assert.NotNil(t, r.CAs())
+
+	// changes are not noticed until the interval has passed.
+	certPEM, keyPEM = ca.issue(t, "second", nil)
+	writeFiles(t, dir, certPEM, keyPEM, ca.pem)
+	future := time.Now().Add(time.Minute)
+	for _, name := range []string{certFile, keyFile} {
+		assert.NoError(t, os.Chtimes(name, future, future))
+	}
+	assert.Equal(t, leafName(t, r.Certificate()), "first")
+
+	expire := func() {
+		r.mu.Lock()
+		r.checked = time.Time{}
+		r.mu.Unlock()
+	}
+
+	expire()
+	assert.Equal(t, leafName(t, r.Certificate()), "second")
+
+	// broken files are logged and the previous certificate is kept.
+	assert.NoError(t, os.WriteFile(certFile, []byte("garbage"), 0o600))
+	future = future.Add(time.Minute)
+	assert.NoError(t, os.Chtimes(certFile, future, future))
+
+	expire()
+	assert.Equal(t, leafName(t, r.Certificate()), "second")
+	assert.Equal(t, len(logged), 1)
+
+	// and a reloader can not be created with them.
+	_, err = NewReloader(certFile, keyFile)
+	assert.Error(t, err)
+}
+
+func TestReloader_ServerConfig(t *testing.T) {
+	ca := newTestCA(t)
+	certPEM, keyPEM := ca.issue(t, "server", nil)
+	certFile, keyFile, caFile := writeFiles(t, t.TempDir(), certPEM, keyPEM, ca.pem)
+	r, err := NewReloaderWithOptions(certFile, keyFile, ReloaderOptions{CAFile: caFile})
+	assert.NoError(t, err)
+
+	config := r.ServerConfig(&tls.Config{
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			switch hello.ServerName {
+			case "other":
+				return &tls.Config{NextProtos: []string{"other"}}, nil
+			case "broken":
+				return nil, errs.New("broken")
+			default:
+				return nil, nil
+			}
+		},
+	})
+
+	// the config serves the current certificate and verifies clients.
+	client, err := config.GetConfigForClient(&tls.ClientHelloInfo{})
+	assert.NoError(t, err)
+	assert.Equal(t, client.ClientAuth, tls.RequireAndVerifyClientCert)
+	assert.NotNil(t, client.GetCertificate)
+	assert.NotNil(t, client.ClientCAs)
+	assert.Nil(t, client.GetConfigForClient)
+
+	// configs returned by the callback of the base config are used with the
+	// current certificate authorities.
+	client, err = config.GetConfigForClient(&tls.ClientHelloInfo{ServerName: "other"})
+	assert.NoError(t, err)
+	assert.DeepEqual(t, client.NextProtos, []string{"other"})
+	assert.NotNil(t, client.ClientCAs)
+
+	// as are its errors.
+	_, err = config.GetConfigForClient(&tls.ClientHelloInfo{ServerName: "broken"})
+	assert.Error(t, err)
+}
+
+func TestReloader_ClientConfig(t *testing.T) {
+	ca := newTestCA(t)
+
+	newReloader := func(name string, dnsNames []string, ips []net.IP) *Reloader {
+		certPEM, keyPEM := ca.issueIPs(t, name, dnsNames, ips)
+		certFile, keyFile, caFile := writeFiles(t, t.TempDir(), certPEM, keyPEM, ca.pem)
+		r, err := NewReloaderWithOptions(certFile, keyFile, ReloaderOptions{CAFile: caFile})
+		assert.NoError(t, err)
+		return r
+	}
+
+	// handshake dials a server with the certificate of the reloader by IP
+	// address with a client config with the server name.
+	handshake := func(server *Reloader, serverName string) error {
+		lis, err := tls.Listen("tcp", "127.0.0.1:0", server.ServerConfig(&tls.Config{ClientAuth: tls.RequestClientCert}))
+		assert.NoError(t, err)
+		defer func() { _ = lis.Close() }()
+
+		go func() {
+			conn, err := lis.Accept()
+			if err == nil {
+				_ = conn.(*tls.Conn).Handshake()
+				_ = conn.Close()
+			}
+		}()
+
+		client := newReloader("client", nil, nil)
+		config := client.ClientConfig(&tls.Config{ServerName: serverName})
+		conn, err := tls.Dial("tcp", lis.Addr().String(), config)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	other := newReloader("other", []string{"other.example.org"}, nil)
+	local := newReloader("local", nil, []net.IP{net.IPv4(127, 0, 0, 1)})
+
+	// a certificate signed by the ca for another host is not accepted when
+	// dialing by IP address.
+	assert.Error(t, handshake(other, ""))
+	assert.Error(t, handshake(other, "127.0.0.1"))
+
+	// IP addresses are checked against the IP subject alternative names.
+	assert.NoError(t, handshake(local, "127.0.0.1"))
+	assert.Error(t, handshake(local, "127.0.0.2"))
+
+	// without a server name to verify against, the handshake fails.
+	assert.Error(t, handshake(local, ""))
+
+	// and host names are checked like usual.
+	assert.NoError(t, handshake(other, "other.example.org"))
+}
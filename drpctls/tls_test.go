@@ -0,0 +1,177 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpctls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/zeebo/assert"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcclient"
+	"storj.io/drpc/drpcctx"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcserver"
+	"storj.io/drpc/drpctest"
+)
+
+// Dummy encoding, which assumes the drpc.Message is a *string.
+type testEncoding struct{}
+
+func (testEncoding) Marshal(msg drpc.Message) ([]byte, error) {
+	return []byte(*msg.(*string)), nil
+}
+
+func (testEncoding) Unmarshal(buf []byte, msg drpc.Message) error {
+	*msg.(*string) = string(buf)
+	return nil
+}
+
+type handlerFunc func(stream drpc.Stream, rpc string) error
+
+func (fn handlerFunc) HandleRPC(stream drpc.Stream, rpc string) error { return fn(stream, rpc) }
+
+func TestMutualTLS(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	ca := newTestCA(t)
+
+	newReloader := func(name string, dnsNames []string, uris ...string) *Reloader {
+		certPEM, keyPEM := ca.issue(t, name, dnsNames, uris...)
+		certFile, keyFile, caFile := writeFiles(t, t.TempDir(), certPEM, keyPEM, ca.pem)
+		r, err := NewReloaderWithOptions(certFile, keyFile, ReloaderOptions{CAFile: caFile})
+		assert.NoError(t, err)
+		return r
+	}
+	server := newReloader("server", []string{"server.example.org"})
+	client := newReloader("client", nil, "spiffe://example.org/ns/prod/sa/client")
+
+	auth := NewAuthorizer(map[string][]string{
+		"spiffe://example.org/ns/prod/*": {"allowed"},
+	})
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer func() { _ = lis.Close() }()
+
+	srv := drpcserver.NewWithOptions(handlerFunc(func(stream drpc.Stream, rpc string) error {
+		if err := auth.Authorize(stream.Context(), rpc); err != nil {
+			return err
+		}
+
+		state, ok := drpcctx.TLSConnectionState(stream.Context())
+		assert.That(t, ok)
+		assert.Equal(t, state.NegotiatedProtocol, "drpc")
+		assert.Equal(t, state.ServerName, "server.example.org")
+		assert.That(t, state.CipherSuite != 0)
+
+		var in string
+		if err := stream.MsgRecv(&in, testEncoding{}); err != nil {
+			return err
+		}
+		return stream.MsgSend(&in, testEncoding{})
+	}), drpcserver.Options{
+		TLSConfig: server.ServerConfig(&tls.Config{NextProtos: []string{"drpc"}}),
+	})
+	ctx.Run(func(ctx context.Context) { _ = srv.Serve(ctx, lis) })
+
+	dial := func(config *tls.Config) *drpcclient.ClientConn {
+		config.ServerName = "server.example.org"
+		config.NextProtos = []string{"drpc"}
+		cc, err := drpcclient.DialTLS(ctx, "tcp", lis.Addr().String(), config)
+		assert.NoError(t, err)
+		return cc
+	}
+
+	cc := dial(client.ClientConfig(nil))
+	defer func() { _ = cc.Close() }()
+
+	in, out := "hello", ""
+	assert.NoError(t, cc.Invoke(ctx, "allowed", testEncoding{}, &in, &out))
+	assert.Equal(t, out, "hello")
+	err = cc.Invoke(ctx, "denied", testEncoding{}, &in, &out)
+	assert.Equal(t, drpcerr.Code(err), drpcerr.PermissionDenied)
+
+	// clients without a certificate are rejected during the handshake.
+	anon := dial(&tls.Config{RootCAs: client.CAs()})
+	defer func() { _ = anon.Close() }()
+	assert.Error(t, anon.Invoke(ctx, "allowed", testEncoding{}, &in, &out))
+
+	// clients that do not trust the server fail to dial.
+	_, err = drpcclient.DialTLS(ctx, "tcp", lis.Addr().String(), &tls.Config{ServerName: "server.example.org"})
+	assert.Error(t, err)
+}
+
+func TestMutualTLS_Unverified(t *testing.T) {
+	ctx := drpctest.NewTracker(t)
+	defer ctx.Close()
+
+	ca := newTestCA(t)
+	certPEM, keyPEM := ca.issue(t, "server", []string{"server.example.org"})
+	certFile, keyFile, caFile := writeFiles(t, t.TempDir(), certPEM, keyPEM, ca.pem)
+	server, err := NewReloaderWithOptions(certFile, keyFile, ReloaderOptions{CAFile: caFile})
+	assert.NoError(t, err)
+
+	auth := NewAuthorizer(map[string][]string{
+		"spiffe://example.org/ns/prod/*": {"allowed"},
+	})
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer func() { _ = lis.Close() }()
+
+	// the server asks for any client certificate without verifying it.
+	srv := drpcserver.NewWithOptions(handlerFunc(func(stream drpc.Stream, rpc string) error {
+		return auth.Authorize(stream.Context(), rpc)
+	}), drpcserver.Options{
+		TLSConfig: server.ServerConfig(&tls.Config{ClientAuth: tls.RequireAnyClientCert}),
+	})
+	ctx.Run(func(ctx context.Context) { _ = srv.Serve(ctx, lis) })
+
+	// a self-signed certificate claims an allowed identity.
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	id, err := url.Parse("spiffe://example.org/ns/prod/sa/client")
+	assert.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		URIs:         []*url.URL{id},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	// the certificate is sent even though the server asks for ones issued by
+	// its certificate authorities.
+	cert := &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	cc, err := drpcclient.DialTLS(ctx, "tcp", lis.Addr().String(), &tls.Config{
+		ServerName: "server.example.org",
+		RootCAs:    server.CAs(),
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return cert, nil
+		},
+	})
+	assert.NoError(t, err)
+	defer func() { _ = cc.Close() }()
+
+	in, out := "hello", ""
+	err = cc.Invoke(ctx, "allowed", testEncoding{}, &in, &out)
+	assert.Equal(t, drpcerr.Code(err), drpcerr.Unauthenticated)
+}
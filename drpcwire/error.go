@@ -25,3 +25,79 @@ func UnmarshalError(data []byte) error {
 	}
 	return drpcerr.WithCode(errs.New("%s", data[8:]), binary.BigEndian.Uint64(data[:8]))
 }
+
+// MarshalErrorDetails returns a byte form of the details associated with the
+// error, or nil if there are none. The byte form is compatible with a protobuf
+// message containing a repeated message field numbered 1 with a string type
+// field numbered 1 and a bytes value field numbered 2.
+func MarshalErrorDetails(err error) []byte {
+	var buf []byte
+	for _, detail := range drpcerr.Details(err) {
+		size := encodedFieldSize(len(detail.Type)) + encodedFieldSize(len(detail.Value))
+
+		buf = append(buf, 10) // 1<<3 | 2
+		buf = AppendVarint(buf, uint64(size))
+
+		buf = append(buf, 10) // 1<<3 | 2
+		buf = AppendVarint(buf, uint64(len(detail.Type)))
+		buf = append(buf, detail.Type...)
+
+		buf = append(buf, 18) // 2<<3 | 2
+		buf = AppendVarint(buf, uint64(len(detail.Value)))
+		buf = append(buf, detail.Value...)
+	}
+	return buf
+}
+
+// UnmarshalErrorDetails unmarshals the byte form of error details.
+func UnmarshalErrorDetails(data []byte) (details []drpcerr.Detail, err error) {
+	for len(data) > 0 {
+		var field uint64
+		var entry []byte
+
+		data, field, entry, err = readField(data)
+		if err != nil {
+			return nil, err
+		} else if field != 1 {
+			continue
+		}
+
+		var detail drpcerr.Detail
+		for len(entry) > 0 {
+			var value []byte
+
+			entry, field, value, err = readField(entry)
+			if err != nil {
+				return nil, err
+			}
+
+			switch field {
+			case 1:
+				detail.Type = string(value)
+			case 2:
+				detail.Value = append([]byte(nil), value...)
+			}
+		}
+		details = append(details, detail)
+	}
+	return details, nil
+}
+
+// encodedFieldSize returns the number of bytes used to encode a length
+// delimited field with a small field number and a body of size n.
+func encodedFieldSize(n int) int {
+	return 1 + len(AppendVarint(nil, uint64(n))) + n
+}
+
+// readField reads a length delimited protobuf field from the buffer.
+func readField(buf []byte) (rem []byte, field uint64, value []byte, err error) {
+	rem, tag, ok, err := ReadVarint(buf)
+	if err != nil || !ok || tag&7 != 2 {
+		return nil, 0, nil, errs.New("invalid error details")
+	}
+	rem, length, ok, err := ReadVarint(rem)
+	if err != nil || !ok || length > uint64(len(rem)) {
+		return nil, 0, nil, errs.New("invalid error details")
+	}
+	return rem[length:], tag >> 3, rem[:length], nil
+}
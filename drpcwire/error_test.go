@@ -18,3 +18,25 @@ func TestError(t *testing.T) {
 	assert.Equal(t, drpcerr.Code(err), 5)
 	assert.Equal(t, err.Error(), "test")
 }
+
+func TestErrorDetails(t *testing.T) {
+	assert.Nil(t, MarshalErrorDetails(errors.New("test")))
+
+	details := []drpcerr.Detail{
+		{Type: "example.BadRequest", Value: []byte("field")},
+		{Type: "example.Empty"},
+		{Type: "example.Large", Value: make([]byte, 1000)},
+	}
+	data := MarshalErrorDetails(drpcerr.WithDetails(errors.New("test"), details...))
+
+	got, err := UnmarshalErrorDetails(data)
+	assert.NoError(t, err)
+	assert.Equal(t, len(got), len(details))
+	for i := range details {
+		assert.Equal(t, got[i].Type, details[i].Type)
+		assert.Equal(t, string(got[i].Value), string(details[i].Value))
+	}
+
+	_, err = UnmarshalErrorDetails(data[:len(data)-1])
+	assert.Error(t, err)
+}
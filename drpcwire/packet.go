@@ -36,6 +36,56 @@ const (
 
 	// KindInvokeMetadata includes metadata about the next Invoke packet.
 	KindInvokeMetadata Kind = 7
+
+	// KindWindowUpdate grants the remote permission to send more message data
+	// on the stream. The body is a varint of the number of bytes. The first
+	// one a server sends is its initial window, and the first one a client
+	// sends after receiving it is followed by a varint of the number of bytes
+	// of message data the client sent before it started using the window. It
+	// is always sent as a control packet so that it is ignored by remotes that
+	// do not support flow control.
+	KindWindowUpdate Kind = 8
+
+	// KindPing is sent to check that the remote is still responsive. Like all
+	// connection level packets, it is sent as a single control frame on
+	// stream zero. It has no body.
+	KindPing Kind = 9
+
+	// KindPong is sent in response to a KindPing. It has no body.
+	KindPong Kind = 10
+
+	// KindGoAway is sent by a server that is shutting down to tell the remote
+	// not to start any new streams. It is a connection level packet whose body
+	// is a varint of the largest stream id that the server will handle. Any
+	// stream with a larger id was not handled and may be safely retried.
+	KindGoAway Kind = 11
+
+	// KindErrorDetails carries the structured details of the error sent in
+	// the KindError packet that immediately follows it on the same stream. It
+	// is always sent as a control packet so that it is ignored by remotes that
+	// do not support error details.
+	KindErrorDetails Kind = 12
+
+	// KindCompressedMessage is used to send messages that have been compressed.
+	// The body is an encoded message compressed with the compressor named in
+	// the invoke metadata of the stream. Servers only send it on streams where
+	// the client advertised a compressor, and clients only send it once the
+	// server has confirmed the compressor in its header, so remotes that do
+	// not support compression never receive it.
+	KindCompressedMessage Kind = 13
+
+	// KindHeader carries the header metadata the server sends in response to
+	// an rpc. The body is encoded metadata. It is sent before the first
+	// message, or before the packet that ends the stream if there are no
+	// messages. It is always sent as a control packet so that it is ignored
+	// by remotes that do not support response metadata.
+	KindHeader Kind = 14
+
+	// KindTrailer carries the trailer metadata the server sends in response
+	// to an rpc. The body is encoded metadata. It is sent immediately before
+	// the packets that end the stream. Like KindHeader, it is always sent as
+	// a control packet.
+	KindTrailer Kind = 15
 )
 
 //
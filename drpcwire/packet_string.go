@@ -15,16 +15,24 @@ func _() {
 	_ = x[KindClose-5]
 	_ = x[KindCloseSend-6]
 	_ = x[KindInvokeMetadata-7]
+	_ = x[KindWindowUpdate-8]
+	_ = x[KindPing-9]
+	_ = x[KindPong-10]
+	_ = x[KindGoAway-11]
+	_ = x[KindErrorDetails-12]
+	_ = x[KindCompressedMessage-13]
+	_ = x[KindHeader-14]
+	_ = x[KindTrailer-15]
 }
 
-const _Kind_name = "InvokeMessageErrorCancelCloseCloseSendInvokeMetadata"
+const _Kind_name = "InvokeMessageErrorCancelCloseCloseSendInvokeMetadataWindowUpdatePingPongGoAwayErrorDetailsCompressedMessageHeaderTrailer"
 
-var _Kind_index = [...]uint8{0, 6, 13, 18, 24, 29, 38, 52}
+var _Kind_index = [...]uint8{0, 6, 13, 18, 24, 29, 38, 52, 64, 68, 72, 78, 90, 107, 113, 120}
 
 func (i Kind) String() string {
-	i -= 1
-	if i >= Kind(len(_Kind_index)-1) {
-		return "Kind(" + strconv.FormatInt(int64(i+1), 10) + ")"
+	idx := int(i) - 1
+	if i < 1 || idx >= len(_Kind_index)-1 {
+		return "Kind(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
-	return _Kind_name[_Kind_index[i]:_Kind_index[i+1]]
+	return _Kind_name[_Kind_index[idx]:_Kind_index[idx+1]]
 }
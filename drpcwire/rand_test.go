@@ -49,7 +49,7 @@ func RandUint64() uint64 {
 
 func RandKind() Kind {
 	for {
-		kind := Kind(rand.Intn(7))
+		kind := Kind(rand.Intn(9))
 		if _, ok := payloadSize[kind]; ok {
 			return kind
 		}
@@ -64,6 +64,7 @@ var payloadSize = map[Kind]func() int{
 	KindClose:          func() int { return 0 },
 	KindCloseSend:      func() int { return 0 },
 	KindInvokeMetadata: func() int { return rand.Intn(1023) + 1 },
+	KindWindowUpdate:   func() int { return rand.Intn(9) + 1 },
 }
 
 func RandFrame() Frame {
@@ -14,6 +14,13 @@ type ReaderOptions struct {
 	// MaximumBufferSize controls the maximum size of buffered
 	// packet data.
 	MaximumBufferSize int
+
+	// Multiplexed relaxes the id monotonicity requirement so that packets
+	// for different streams may be interleaved with each other. Frames
+	// within a single packet must still be contiguous, and a frame is
+	// only required to have an id greater than the previous frame if
+	// they are for the same stream.
+	Multiplexed bool
 }
 
 // Reader reconstructs packets from frames read from an io.Reader.
@@ -82,9 +89,11 @@ func (r *Reader) ReadPacket() (pkt Packet, err error) {
 // ReadPacketUsing reads a packet from the io.Reader. IDs read from
 // frames must be monotonically increasing. When a new ID is read, the
 // old data is discarded. This allows for easier asynchronous interrupts.
-// If the amount of data in the Packet becomes too large, an error is
-// returned. The returned packet's Data field is constructed by appending
-// to the provided buf after it has been resliced to be zero length.
+// Connection level packets, which are single control frames on stream
+// zero, are exempt from the monotonicity requirement. If the amount of
+// data in the Packet becomes too large, an error is returned. The
+// returned packet's Data field is constructed by appending to the
+// provided buf after it has been resliced to be zero length.
 func (r *Reader) ReadPacketUsing(buf []byte) (pkt Packet, err error) {
 	pkt.Data = buf[:0]
 
@@ -135,12 +144,24 @@ func (r *Reader) ReadPacketUsing(buf []byte) (pkt Packet, err error) {
 			r.buf = r.buf[:0]
 		}
 
+		// connection level packets are sent as a single control frame on
+		// stream zero. they are not part of any stream, so they do not
+		// participate in id monotonicity.
+		if fr.ID.Stream == 0 && fr.Control && fr.Done {
+			return Packet{
+				Data:    append(pkt.Data[:0], fr.Data...),
+				ID:      fr.ID,
+				Kind:    fr.Kind,
+				Control: true,
+			}, nil
+		}
+
 		// If any frames are set to control, then the whole packet is
 		// considered to be control.
 		pkt.Control = pkt.Control || fr.Control
 
 		switch {
-		case fr.ID.Less(r.id):
+		case r.monotonicityViolation(fr.ID):
 			return Packet{}, drpc.ProtocolError.New("id monotonicity violation (fr:%v r:%v)", fr.ID, r.id)
 
 		case r.id != fr.ID || pkt.ID == ID{}:
@@ -171,3 +192,13 @@ func (r *Reader) ReadPacketUsing(buf []byte) (pkt Packet, err error) {
 		}
 	}
 }
+
+// monotonicityViolation returns true if a frame with the given id is not
+// allowed to follow the most recently read frame.
+func (r *Reader) monotonicityViolation(id ID) bool {
+	if !r.opts.Multiplexed {
+		return id.Less(r.id)
+	}
+	return id.Stream == 0 || id.Message == 0 ||
+		(id.Stream == r.id.Stream && id.Message < r.id.Message)
+}
@@ -154,6 +154,58 @@ func TestReader(t *testing.T) {
 			Frames: []Frame{{ID: ID{Stream: 0, Message: 1}}},
 			Error:  "id monotonicity violation",
 		},
+
+		{ // connection level packets do not affect monotonicity
+			Packets: []Packet{
+				p(KindMessage, 2, false, "1"),
+				{ID: ID{Stream: 0, Message: 1}, Kind: KindPing, Control: true},
+			},
+			Frames: []Frame{
+				f(KindMessage, 2, "1", true, false),
+				{ID: ID{Stream: 0, Message: 1}, Kind: KindPing, Done: true, Control: true},
+				f(KindMessage, 1, "X", true, false),
+			},
+			Error: "id monotonicity violation",
+		},
+
+		{ // multiplexed packets for different streams may interleave
+			Packets: []Packet{
+				{Data: []byte("a"), ID: ID{Stream: 2, Message: 1}, Kind: KindMessage},
+				{Data: []byte("b"), ID: ID{Stream: 1, Message: 3}, Kind: KindMessage},
+				{Data: []byte("c"), ID: ID{Stream: 2, Message: 2}, Kind: KindMessage},
+			},
+			Frames: []Frame{
+				{Data: []byte("a"), ID: ID{Stream: 2, Message: 1}, Kind: KindMessage, Done: true},
+				{Data: []byte("b"), ID: ID{Stream: 1, Message: 3}, Kind: KindMessage, Done: true},
+				{Data: []byte("c"), ID: ID{Stream: 2, Message: 2}, Kind: KindMessage, Done: true},
+			},
+			Options: ReaderOptions{Multiplexed: true},
+		},
+
+		{ // multiplexed packets still have monotonic ids within a stream
+			Packets: []Packet{
+				{Data: []byte("a"), ID: ID{Stream: 2, Message: 2}, Kind: KindMessage},
+				{Data: []byte("b"), ID: ID{Stream: 1, Message: 3}, Kind: KindMessage},
+			},
+			Frames: []Frame{
+				{Data: []byte("a"), ID: ID{Stream: 2, Message: 2}, Kind: KindMessage, Done: true},
+				{Data: []byte("b"), ID: ID{Stream: 1, Message: 3}, Kind: KindMessage, Done: true},
+				{Data: []byte("c"), ID: ID{Stream: 1, Message: 2}, Kind: KindMessage, Done: true},
+			},
+			Options: ReaderOptions{Multiplexed: true},
+			Error:   "id monotonicity violation",
+		},
+
+		{ // multiplexed partial packets are abandoned by a frame for another stream
+			Packets: []Packet{
+				{Data: []byte("b"), ID: ID{Stream: 2, Message: 1}, Kind: KindMessage},
+			},
+			Frames: []Frame{
+				{Data: []byte("a"), ID: ID{Stream: 1, Message: 1}, Kind: KindMessage},
+				{Data: []byte("b"), ID: ID{Stream: 2, Message: 1}, Kind: KindMessage, Done: true},
+			},
+			Options: ReaderOptions{Multiplexed: true},
+		},
 	}
 
 	for _, tc := range cases {
@@ -78,6 +78,23 @@ func (b *Writer) WriteFrame(fr Frame) (err error) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
+	return b.writeFrameLocked(fr)
+}
+
+// WriteSplitPacket writes the packet as a sequence of frames that each have at
+// most n bytes of data, following the same rules as SplitN. The frames are
+// written while holding the Writer's lock so that no frames from concurrent
+// calls are interleaved with them.
+func (b *Writer) WriteSplitPacket(pkt Packet, n int) (err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return SplitN(pkt, n, b.writeFrameLocked)
+}
+
+// writeFrameLocked does the work of WriteFrame assuming the caller is holding
+// the mutex.
+func (b *Writer) writeFrameLocked(fr Frame) (err error) {
 	if len(b.buf) == 0 {
 		atomic.StoreUint32(&b.empty, 1)
 	}
@@ -30,3 +30,20 @@ func TestWriter(t *testing.T) {
 	t.Run("Size 0B", run(0))
 	t.Run("Size 1MB", run(1024*1024))
 }
+
+func TestWriterSplitPacket(t *testing.T) {
+	var exp []byte
+	var got bytes.Buffer
+
+	wr := NewWriter(&got, 0)
+	for i := 0; i < 100; i++ {
+		pkt := RandPacket()
+		assert.NoError(t, SplitN(pkt, 10, func(fr Frame) error {
+			exp = AppendFrame(exp, fr)
+			return nil
+		}))
+		assert.NoError(t, wr.WriteSplitPacket(pkt, 10))
+	}
+	assert.NoError(t, wr.Flush())
+	assert.That(t, bytes.Equal(exp, got.Bytes()))
+}
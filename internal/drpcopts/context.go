@@ -0,0 +1,22 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package drpcopts
+
+import "context"
+
+type softCancelKey struct{}
+
+// WithSoftCancel returns a context that causes a manager to soft cancel the
+// stream created with it when the context is canceled, even if the manager
+// does not use soft cancels.
+func WithSoftCancel(ctx context.Context) context.Context {
+	return context.WithValue(ctx, softCancelKey{}, true)
+}
+
+// GetSoftCancel returns true if streams created with the context must be soft
+// canceled.
+func GetSoftCancel(ctx context.Context) bool {
+	soft, _ := ctx.Value(softCancelKey{}).(bool)
+	return soft
+}
@@ -15,6 +15,9 @@ type Stream struct {
 	kind      string
 	rpc       string
 	stats     *drpcstats.Stats
+	mux       bool
+	server    bool
+	window    int
 }
 
 // GetStreamTransport returns the drpc.Transport stored in the options.
@@ -46,3 +49,23 @@ func GetStreamStats(opts *Stream) *drpcstats.Stats { return opts.stats }
 
 // SetStreamStats sets the Stats stored in the options.
 func SetStreamStats(opts *Stream, stats *drpcstats.Stats) { opts.stats = stats }
+
+// GetStreamMultiplexed returns if the stream shares its transport with other
+// concurrently active streams.
+func GetStreamMultiplexed(opts *Stream) bool { return opts.mux }
+
+// SetStreamMultiplexed sets if the stream shares its transport with other
+// concurrently active streams.
+func SetStreamMultiplexed(opts *Stream, mux bool) { opts.mux = mux }
+
+// GetStreamServer returns if the stream is the server side of an rpc.
+func GetStreamServer(opts *Stream) bool { return opts.server }
+
+// SetStreamServer sets if the stream is the server side of an rpc.
+func SetStreamServer(opts *Stream, server bool) { opts.server = server }
+
+// GetStreamWindow returns the flow control window the remote advertised.
+func GetStreamWindow(opts *Stream) int { return opts.window }
+
+// SetStreamWindow sets the flow control window the remote advertised.
+func SetStreamWindow(opts *Stream, window int) { opts.window = window }
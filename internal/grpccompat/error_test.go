@@ -6,9 +6,16 @@ package grpccompat
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/zeebo/assert"
 	"github.com/zeebo/errs"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"storj.io/drpc/drpcerr"
 )
 
 func TestError_ErrorPassedBack(t *testing.T) {
@@ -29,3 +36,27 @@ func TestError_ErrorPassedBack(t *testing.T) {
 		ensure(nil, stream.Send(in(0)))
 	})
 }
+
+func TestError_GRPCStatus(t *testing.T) {
+	st, err := status.New(codes.NotFound, "missing").WithDetails(durationpb.New(time.Second))
+	assert.NoError(t, err)
+
+	// statuses convert to errors with the same code, message and details
+	err = drpcerr.FromGRPCStatus(st.Err())
+	assert.Equal(t, drpcerr.Code(err), uint64(codes.NotFound))
+	assert.Equal(t, err.Error(), "missing")
+
+	details := drpcerr.Details(err)
+	assert.Equal(t, len(details), 1)
+	assert.Equal(t, details[0].Type, "google.protobuf.Duration")
+
+	var dur durationpb.Duration
+	assert.NoError(t, proto.Unmarshal(details[0].Value, &dur))
+	assert.Equal(t, dur.AsDuration(), time.Second)
+
+	// and errors convert back to the same status
+	code, msg := drpcerr.ToGRPCStatus(err)
+	st = status.New(codes.Code(code), msg)
+	assert.Equal(t, st.Code(), codes.NotFound)
+	assert.Equal(t, st.Message(), "missing")
+}
@@ -73,8 +73,8 @@ func TestHTTP(t *testing.T) {
 
 	// basic erroring request
 	assertEqual(t, request("/service.Service/Method1", `{"in": 5}`), response{
-		StatusCode: http.StatusInternalServerError,
-		Code:       "drpcerr(5)",
+		StatusCode: http.StatusNotFound,
+		Code:       "not_found",
 		Msg:        "test",
 	})
 